@@ -0,0 +1,137 @@
+// +build generic
+
+package bench
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aybabtme/datagen/generic/heap"
+	"github.com/aybabtme/datagen/generic/queue"
+	"github.com/aybabtme/datagen/generic/redblackbst"
+)
+
+// The benchmarks of the generic libraries have the same names as the ones
+// of the generated code, to compare them with `benchcmp`.
+
+// heap
+
+func Benchmark_Heap_StringHeap(b *testing.B) {
+	const n = 10000
+	h := heap.New(make([]string, 0, n)...)
+	vals := makeStrings(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < n; j++ {
+			h.Push(vals[j])
+		}
+		for h.Len() > 0 {
+			h.Pop()
+		}
+	}
+}
+
+func Benchmark_Heap_IntHeap(b *testing.B) {
+	const n = 10000
+	h := heap.New(make([]int, 0, n)...)
+	vals := makeInts(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < n; j++ {
+			h.Push(vals[j])
+		}
+		for h.Len() > 0 {
+			h.Pop()
+		}
+	}
+}
+
+func Benchmark_Heap_BytesHeap(b *testing.B) {
+	const n = 10000
+	h := heap.NewFunc(bytes.Compare, make([][]byte, 0, n)...)
+	vals := makeBytes(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < n; j++ {
+			h.Push(vals[j])
+		}
+		for h.Len() > 0 {
+			h.Pop()
+		}
+	}
+}
+
+// sorted map
+
+func Benchmark_SortedMap_IntToString_Insert(b *testing.B) {
+	tree := redblackbst.NewMap[int, string]()
+	strs := makeStrings(b.N)
+	b.ResetTimer()
+	for i := b.N - 1; i > 0; i-- {
+		tree.Put(i, strs[i])
+	}
+}
+
+func Benchmark_SortedMap_StringToString_Insert(b *testing.B) {
+	tree := redblackbst.NewMap[string, string]()
+	strs := makeStrings(b.N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Put(strs[b.N-i-1], strs[i])
+	}
+}
+
+func Benchmark_SortedMap_BytesToString_Insert(b *testing.B) {
+	tree := redblackbst.NewMapFunc[[]byte, string](bytes.Compare)
+	bytes := makeBytes(b.N)
+	strs := makeStrings(b.N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Put(bytes[b.N-i-1], strs[i])
+	}
+}
+
+// sorted set
+
+func Benchmark_SortedSet_Int_Insert(b *testing.B) {
+	tree := redblackbst.NewSet[int]()
+	b.ResetTimer()
+	for i := b.N - 1; i > 0; i-- {
+		tree.Put(i)
+	}
+}
+
+func Benchmark_SortedSet_String_Insert(b *testing.B) {
+	tree := redblackbst.NewSet[string]()
+	strs := makeStrings(b.N)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Put(strs[b.N-i-1])
+	}
+}
+
+// queue
+
+func Benchmark_Queue_String_Serial(b *testing.B) {
+	vals := makeStrings(b.N)
+	q := queue.New[string](0)
+	b.ResetTimer()
+	for _, v := range vals {
+		q.Push(v)
+	}
+	for i := 0; i < b.N; i++ {
+		q.Pop()
+	}
+}
+
+func Benchmark_Queue_Int_Serial(b *testing.B) {
+	vals := makeInts(b.N)
+	q := queue.New[int](0)
+	b.ResetTimer()
+	for _, v := range vals {
+		q.Push(v)
+	}
+	for i := 0; i < b.N; i++ {
+		q.Pop()
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// generatedFilename is the name given to the generated code in the errors
+// reported while checking it.
+const generatedFilename = "<generated>"
+
+// finish adds the header to the generated source, formats it and makes sure
+// it compiles as part of the package it's generated for.
+func finish(out output, src []byte) ([]byte, error) {
+	formatted, err := format.Source(append([]byte(out.header), src...))
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	if err := typecheck(out, formatted); err != nil {
+		return nil, err
+	}
+	return formatted, nil
+}
+
+// typecheck type-checks src along with the other files of the output's
+// package, so that the types it refers to are resolved like they will be once
+// the generated code is written. Only the errors found in src are
+// reported; the package is allowed to be broken in other ways.
+func typecheck(out output, src []byte) error {
+	var errs []string
+	_, err := checkGenerated(out, src, nil, func(terr types.Error) {
+		errs = append(errs, fmt.Sprintf("\t%s: %s", sourceLine(src, terr.Fset.Position(terr.Pos)), terr.Msg))
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	const maxErrs = 10
+	if len(errs) > maxErrs {
+		errs = append(errs[:maxErrs], "\t...")
+	}
+	return fmt.Errorf("generated code doesn't compile:\n%s", strings.Join(errs, "\n"))
+}
+
+// typeOf resolves typ in the output's package.
+func typeOf(out output, typ *goType) (types.Type, error) {
+	src := bytes.NewBuffer(nil)
+	fmt.Fprintf(src, "package %s\n\n", out.pkgName)
+	for _, imp := range typ.imports {
+		fmt.Fprintf(src, "import %s %q\n", imp.name, imp.path)
+	}
+	fmt.Fprintf(src, "\nvar _ %s\n", typ.name)
+
+	info := &types.Info{Types: make(map[ast.Expr]types.TypeAndValue)}
+	var errs []string
+	gen, err := checkGenerated(out, src.Bytes(), info, func(terr types.Error) {
+		errs = append(errs, terr.Msg)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s isn't a type: %s", typ.name, errs[0])
+	}
+	decl := gen.Decls[len(gen.Decls)-1].(*ast.GenDecl)
+	return info.TypeOf(decl.Specs[0].(*ast.ValueSpec).Type), nil
+}
+
+// checkGenerated type-checks src along with the other files of the
+// output's package, and reports the errors found in src.
+func checkGenerated(out output, src []byte, info *types.Info, report func(types.Error)) (*ast.File, error) {
+	fset := token.NewFileSet()
+	gen, err := parser.ParseFile(fset, generatedFilename, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing generated code: %v", err)
+	}
+
+	files := []*ast.File{gen}
+	pkgfiles, err := packageFiles(fset, out, gen.Name.Name, topLevelNames(gen))
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, pkgfiles...)
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			terr, ok := err.(types.Error)
+			if !ok || terr.Fset.Position(terr.Pos).Filename != generatedFilename {
+				return
+			}
+			report(terr)
+		},
+	}
+	_, _ = conf.Check(gen.Name.Name, fset, files, info)
+	return gen, nil
+}
+
+// packageFiles parses the files of package pkgName in the output's
+// directory, leaving aside the output file and those that declare some of
+// the declared names: they're a previous version of the code being
+// generated. Files that can't be parsed are also left aside, such as the
+// empty file a shell creates when redirecting datagen's output.
+func packageFiles(fset *token.FileSet, out output, pkgName string, declared map[string]bool) ([]*ast.File, error) {
+	entries, err := ioutil.ReadDir(out.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading package in %q: %v", out.dir, err)
+	}
+
+	// the files generated but not written yet are part of the package
+	// too, in place of what's on disk
+	var names []string
+	for _, entry := range entries {
+		path := filepath.Join(out.dir, entry.Name())
+		if _, ok := out.pending[path]; !entry.IsDir() && !ok {
+			names = append(names, entry.Name())
+		}
+	}
+	for path := range out.pending {
+		if filepath.Dir(path) == out.dir {
+			names = append(names, filepath.Base(path))
+		}
+	}
+	sort.Strings(names)
+
+	var files []*ast.File
+	for _, name := range names {
+		path := filepath.Join(out.dir, name)
+		if path == out.filename {
+			continue
+		}
+		// generated tests can use what the package's tests declare
+		if strings.HasSuffix(name, "_test.go") && !strings.HasSuffix(out.filename, "_test.go") {
+			continue
+		}
+		var src interface{}
+		if pending, ok := out.pending[path]; ok {
+			src = pending
+		} else if ok, err := build.Default.MatchFile(out.dir, name); err != nil || !ok {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			continue
+		}
+		if file.Name.Name != pkgName || overlaps(declared, topLevelNames(file)) {
+			continue
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// declaredNames returns the names declared by the other files of the
+// output's package, apart from those declaring typ: they're a previous
+// version of the datastructure named typ.
+func declaredNames(out output, typ string) (map[string]bool, error) {
+	files, err := packageFiles(token.NewFileSet(), out, out.pkgName, map[string]bool{typ: true})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	for _, file := range files {
+		for name := range topLevelNames(file) {
+			names[name] = true
+		}
+	}
+	return names, nil
+}
+
+func topLevelNames(file *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				names[d.Name.Name] = true
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					names[s.Name.Name] = true
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						names[name.Name] = true
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+func overlaps(a, b map[string]bool) bool {
+	for name := range a {
+		if b[name] && name != "_" {
+			return true
+		}
+	}
+	return false
+}
+
+// sourceLine describes a position in the generated code by quoting the
+// line it's found on, since the generated code was not written anywhere
+// the user can look at.
+func sourceLine(src []byte, pos token.Position) string {
+	lines := bytes.Split(src, []byte("\n"))
+	if pos.Line < 1 || pos.Line > len(lines) {
+		return pos.String()
+	}
+	return fmt.Sprintf("%s (in %q)", pos, strings.TrimSpace(string(lines[pos.Line-1])))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTypecheckUsesPackageFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src, err := newTemplate(queueSrc).rewrite("foo", map[string]string{
+		"KType":    "Item",
+		"nilKType": "nilItem",
+		"Queue":    "ItemQueue",
+		"NewQueue": "NewItemQueue",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = finish(output{dir: dir}, src)
+	if err == nil || !strings.Contains(err.Error(), "undefined: Item") {
+		t.Fatalf("want an error about Item being undefined, got %v", err)
+	}
+
+	files := map[string]string{
+		"item.go": "package foo\n\ntype Item struct{}\n",
+		// previous version of the generated code, must be ignored
+		"queue.go": "package foo\n\ntype ItemQueue struct{}\n",
+		// left behind by a shell redirection
+		"empty.go": "",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := finish(output{dir: dir}, src); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTypeOfResolvesPackageTypes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := "package foo\n\ntype Item struct{}\n\ntype Items struct{ all []Item }\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "item.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := output{dir: dir, pkgName: "foo"}
+
+	comparable := map[string]bool{
+		"int":       true,
+		"*Items":    true,
+		"Item":      true,
+		"time.Time": true,
+		"Items":     false,
+		"[]byte":    false,
+	}
+	for name, want := range comparable {
+		typ, err := parseType(name, out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resolved, err := typeOf(out, typ)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := types.Comparable(resolved); got != want {
+			t.Errorf("%s: want comparable=%v, got %v", name, want, got)
+		}
+	}
+
+	typ, err := parseType("Missing", out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := typeOf(out, typ); err == nil {
+		t.Error("want an error resolving an undefined type")
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// codegenDir holds the datastructures generated for the benchmarks, from
+// its datagen.yaml manifest.
+var codegenDir = filepath.Join("..", "..", "codegen")
+
+// staleCompares are compare methods the generator wrote before, which the
+// checked-in codegen must not hold anymore.
+var staleCompares = map[string]string{
+	// subtracting integers overflows when they're far apart
+	"heap_int.go":        "return int(a) - int(b)",
+	"smap_int_string.go": "return int(a) - int(b)",
+	"sset_int.go":        "return int(a) - int(b)",
+	// a relative difference isn't a total order, and divides by zero
+	"heap_float.go":        "diff := (a-b)/a",
+	"smap_float_string.go": "diff := (a-b)/a",
+	"sset_float.go":        "diff := (a-b)/a",
+}
+
+// TestCodegenIsUpToDate checks that codegen holds the datastructures that
+// datagen writes today, so that fixing the generator also fixes them.
+func TestCodegenIsUpToDate(t *testing.T) {
+	for name, stale := range staleCompares {
+		src, err := ioutil.ReadFile(filepath.Join(codegenDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(src), stale) {
+			t.Errorf("%s still has the compare method %q", name, stale)
+		}
+	}
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{sortedMap(), sortedSet(), heap(), queue(), genCommand(), verifyCommand()}
+	if err := app.Run([]string{"datagen", "verify", codegenDir}); err != nil {
+		t.Fatalf("codegen is stale, regenerate it with datagen gen: %v", err)
+	}
+}
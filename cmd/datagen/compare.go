@@ -0,0 +1,381 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"log"
+	"strconv"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	epsilonFlag = cli.Float64Flag{
+		Name:  "epsilon",
+		Usage: "for float keys, consider keys within this relative distance of each other equal, instead of ordering them exactly",
+	}
+	cmpFlag = cli.StringFlag{
+		Name:  "cmp",
+		Usage: "name of a `func(a, b T) int` ordering the keys, like bytes.Compare, instead of their Compare method",
+	}
+	lessFlag = cli.StringFlag{
+		Name:  "less",
+		Usage: "name of a `func(a, b T) bool` reporting whether key a is ordered before key b, instead of their Compare method",
+	}
+	orderFlag = cli.StringFlag{
+		Name:  "order",
+		Usage: "asc or desc, to hold the keys in increasing or decreasing order (default: desc for heaps, which pop their largest key first, asc for sorted maps and sets)",
+	}
+)
+
+// keyOrder is how the keys of a datastructure are ordered.
+type keyOrder struct {
+	ktype *goType
+	// epsilon within which float keys are equal, or 0 to order them
+	// exactly.
+	epsilon float64
+	// cmp or less is the function given to order the keys, as it's
+	// written in the generated code.
+	cmp, less string
+	// field ordering the keys, when they're ordered by one.
+	field *keyField
+	// composite is the key, when it's made of many fields.
+	composite *compositeKey
+	// imports needed by that function.
+	imports []importSpec
+	// reverse tells if the keys are held in the reverse of the order of the
+	// template, like in a min-heap.
+	reverse bool
+}
+
+// newKeyOrder returns the order of keys of type ktype given on the command
+// line.
+func newKeyOrder(ctx *cli.Context, out output, ktype *goType) (*keyOrder, error) {
+	epsilon, err := compareEpsilon(ctx, ktype)
+	if err != nil {
+		return nil, err
+	}
+	order := &keyOrder{ktype: ktype, epsilon: epsilon}
+
+	cmp, less, field := ctx.String("cmp"), ctx.String("less"), ctx.String("key-field")
+	switch {
+	case cmp != "" && less != "":
+		return nil, errors.New("-cmp and -less can't be used together")
+	case field != "" && (cmp != "" || less != ""):
+		return nil, errors.New("-key-field can't be used with -cmp or -less")
+	case (cmp != "" || less != "" || field != "") && epsilon != 0:
+		return nil, errors.New("-epsilon can't be used with -cmp, -less or -key-field")
+	case cmp != "":
+		order.cmp, order.imports, err = parseFuncName(cmp, out)
+	case less != "":
+		order.less, order.imports, err = parseFuncName(less, out)
+	case field != "":
+		order.field, err = lookupKeyField(out, ktype, field)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cmp == "" && less == "" && field == "" && ordersByMethod(ktype.name) {
+		if warning := compareMethodWarning(out, ktype); warning != "" {
+			log.Print(warning)
+		}
+	}
+	return order, nil
+}
+
+// ordersByMethod tells if the keys of type ktype are ordered by their
+// Compare method when no other order is given.
+func ordersByMethod(ktype string) bool {
+	return ktype != "float32" && ktype != "float64" && !isOrderedBasic(ktype) && !strings.HasPrefix(ktype, "[]")
+}
+
+// compareMethodWarning returns the warning for keys of type ktype that
+// have no `Compare(T) int` method to be ordered by, or nothing when they
+// have one. It shows how to write it for the named types of the output's
+// package, the only ones it can be declared on.
+func compareMethodWarning(out output, ktype *goType) string {
+	typ, err := typeOf(out, ktype)
+	if err != nil {
+		// the type-check of the generated code reports it
+		return ""
+	}
+	if hasCompareMethod(typ) {
+		return ""
+	}
+	warning := fmt.Sprintf("type %q will need to implement a Compare func, or be ordered by a function given with -cmp or -less", ktype.name)
+
+	expr := ktype.expr
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	named, isNamed := derefType(typ).(*types.Named)
+	if !ok || !isNamed || named.Obj().Pkg() == nil || types.IsInterface(named) {
+		return warning
+	}
+	recv := strings.ToLower(ident.Name[:1])
+	return fmt.Sprintf(`%s:
+	func (%[2]s %[3]s) Compare(other %[3]s) int {
+		if %[2]s > other {
+			return 1
+		} else if %[2]s < other {
+			return -1
+		}
+		return 0
+	}`, warning, recv, ktype.name)
+}
+
+// parseFuncName verifies that name refers to a function, which is
+// qualified like the types are when it's from another package, and
+// returns it as it's written in the generated code.
+func parseFuncName(name string, out output) (string, []importSpec, error) {
+	qualified, imports := resolveQualifiers(name, out)
+	expr, err := parser.ParseExpr(qualified)
+	if err != nil {
+		return "", nil, fmt.Errorf("%q is not a valid function name: %v", name, err)
+	}
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return qualified, imports, nil
+	case *ast.SelectorExpr:
+		if _, ok := e.X.(*ast.Ident); ok {
+			return qualified, imports, nil
+		}
+	}
+	return "", nil, fmt.Errorf("%q is not the name of a function", name)
+}
+
+// reversedOrder tells if the -order given on the command line is the
+// reverse of natural, the order of the datastructure's template: asc or
+// desc.
+func reversedOrder(ctx *cli.Context, natural string) (bool, error) {
+	switch order := ctx.String("order"); order {
+	case "":
+		return false, nil
+	case "asc", "desc":
+		return order != natural, nil
+	default:
+		return false, fmt.Errorf("-order is asc or desc, not %q", order)
+	}
+}
+
+// compareEpsilon returns the epsilon given for keys of type ktype, or 0
+// when they're to be compared exactly.
+func compareEpsilon(ctx *cli.Context, ktype *goType) (float64, error) {
+	epsilon := ctx.Float64("epsilon")
+	switch {
+	case epsilon == 0:
+		return 0, nil
+	case ctx.Bool("generic"):
+		return 0, errors.New("-epsilon can't be used with -generic")
+	case ktype.name != "float32" && ktype.name != "float64":
+		return 0, fmt.Errorf("-epsilon only applies to float32 and float64 keys, not %s", ktype.name)
+	case epsilon < 0 || epsilon != epsilon:
+		return 0, fmt.Errorf("-epsilon must be positive, not %v", epsilon)
+	}
+	return epsilon, nil
+}
+
+// replaceCompareFunc replaces the compare method of the template, which
+// calls the Compare method of the keys, by one ordering the keys like
+// order does. The receiver of the method is recv, like `h Heap`. When the
+// order is reversed, so are the words of the comments describing it, for
+// Min to return the largest key.
+func replaceCompareFunc(recv string, order *keyOrder, src *template) error {
+	if order.reverse {
+		if err := src.renameCommentWords(orderedDecls, reversedWords); err != nil {
+			return err
+		}
+	}
+	for _, imp := range order.imports {
+		if err := src.addImport(imp.name, imp.path); err != nil {
+			return err
+		}
+	}
+
+	var tmpl string
+	ktype := order.ktype.name
+	switch {
+
+	// the keys are compared by the Compare method generated with them
+	case order.composite != nil:
+		src.appendDecl(order.composite.decl(order.ktype.name))
+		if !order.reverse {
+			return nil
+		}
+		tmpl = fmt.Sprintf(methodCompareFunc, recv)
+
+	case order.field != nil:
+		for _, imp := range order.field.imports {
+			if err := src.addImport(imp.name, imp.path); err != nil {
+				return err
+			}
+		}
+		tmpl = fmt.Sprintf(`
+// compare orders the keys by their %[2]s.
+func (%[1]s) compare(a, b KType) int {
+	x, y := a.%[2]s, b.%[2]s
+	%[3]s
+	return 0
+}`, recv, order.field.path, order.field.compare)
+
+	case order.cmp != "":
+		tmpl = fmt.Sprintf("func (%s) compare(a, b KType) int { return %s(a, b) }", recv, order.cmp)
+
+	case order.less != "":
+		tmpl = fmt.Sprintf(`
+func (%s) compare(a, b KType) int {
+	switch {
+	case %[2]s(a, b):
+		return -1
+	case %[2]s(b, a):
+		return 1
+	}
+	return 0
+}`, recv, order.less)
+
+	case ktype == "float32" || ktype == "float64":
+		if order.epsilon != 0 {
+			if err := src.addImport("", "math"); err != nil {
+				return err
+			}
+		}
+		tmpl = floatCompareFunc(recv, order.epsilon)
+
+	// subtracting integers overflows when they're far apart, so they're
+	// compared like strings
+	case isOrderedBasic(ktype):
+		tmpl = fmt.Sprintf(`
+func (%s) compare(a, b KType) int {
+    if a < b {
+        return -1
+    }
+    if a > b {
+        return 1
+    }
+    return 0
+}`, recv)
+
+	case ktype == "[]byte":
+		log.Printf("WARNING: using []byte as keys can lead to undefined behavior if the []byte are modified after insertion!!!")
+		if err := src.addImport("", "bytes"); err != nil {
+			return err
+		}
+		tmpl = fmt.Sprintf(`// WARNING: using []byte as keys can lead to undefined behavior if the
+// []byte are modified after insertion!!!
+func (%s) compare(a, b KType) int { return bytes.Compare(a, b) }`, recv)
+
+	// if storing slices, use `len()` for comparison
+	case strings.HasPrefix(ktype, "[]"):
+		log.Printf("%s: order will be determined based on value of len(%s)", ktype, ktype)
+		tmpl = fmt.Sprintf("func (%s) compare(a, b KType) int { return len(a)-len(b) }", recv)
+
+	default:
+		// otherwise don't change anything by default, the keys are
+		// ordered by their `Compare` func
+		if !order.reverse {
+			return nil
+		}
+		tmpl = fmt.Sprintf(methodCompareFunc, recv)
+	}
+
+	if order.reverse {
+		tmpl = reverseCompareFunc(tmpl)
+	}
+	return src.replaceFunc("compare", tmpl)
+}
+
+// methodCompareFunc is the compare method of the templates, which orders
+// the keys by their Compare method.
+const methodCompareFunc = "func (%s) compare(a, b KType) int { return a.Compare(b) }"
+
+// orderedDecls are the declarations of the templates whose comments
+// describe the order of the keys, which are reworded when it's reversed.
+// The other comments keep their words, like a heap's Fix being "less
+// expensive" than popping and pushing everything.
+var orderedDecls = []string{
+	// heaps
+	"Heap", "Peek", "Pop", "Remove", "Drain",
+	// sorted maps and sets
+	"Min", "Max", "Floor", "Ceiling", "Lower", "Higher", "Rank",
+	"KeysAbove", "KeysBelow", "above", "below", "DeleteMin", "DeleteMax",
+	"Iterator", "First", "Last", "SeekCeiling", "SeekFloor",
+	"All", "Backward", "Range", "AllKeys", "AllValues",
+}
+
+// reversedWords are the words of the comments describing the order of the
+// keys, swapped when the order is reversed.
+var reversedWords = map[string]string{
+	"smallest":   "largest",
+	"largest":    "smallest",
+	"smaller":    "larger",
+	"larger":     "smaller",
+	"less":       "greater",
+	"greater":    "less",
+	"increasing": "decreasing",
+	"decreasing": "increasing",
+}
+
+// reverseCompareFunc reverses the order of tmpl, a compare method, by
+// swapping its parameters. Its comments describe the keys in the order
+// they had before, so their words are swapped too.
+func reverseCompareFunc(tmpl string) string {
+	lines := strings.Split(strings.TrimSpace(tmpl), "\n")
+	doc := 0
+	for ; strings.HasPrefix(lines[doc], "//"); doc++ {
+		lines[doc] = renameWords(lines[doc], reversedWords)
+	}
+	note := "// The order is reversed by swapping a and b."
+	if doc == 0 {
+		note = "// compare orders the keys in reverse, by swapping a and b."
+	}
+	lines = append(lines[:doc], append([]string{note}, lines[doc:]...)...)
+	return strings.Replace(strings.Join(lines, "\n"), "compare(a, b KType)", "compare(b, a KType)", 1)
+}
+
+// isOrderedBasic tells if the basic type ktype is ordered by the < and >
+// operators, apart from the floats which also need NaNs to be ordered.
+func isOrderedBasic(ktype string) bool {
+	switch ktype {
+	case "string", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte", "rune":
+		return true
+	}
+	return false
+}
+
+// floatCompareFunc returns the compare method of recv for float keys.
+// They're totally ordered like cmp.Compare orders them, with -0 equal to
+// +0 and NaNs equal to each other and smaller than any other key. With an
+// epsilon, the finite keys within that relative distance of each other are
+// also equal, which isn't transitive: keys equal to the same key might not
+// be equal to each other. The template needs to import math then.
+func floatCompareFunc(recv string, epsilon float64) string {
+	doc := "// compare orders the keys exactly, with -0 equal to +0, and NaNs equal to\n// each other and smaller than any other key."
+	var tolerance string
+	if epsilon != 0 {
+		doc = "// compare considers the finite keys within a relative epsilon of each\n// other equal, and otherwise orders them exactly, with NaNs equal to each\n// other and smaller than any other key."
+		tolerance = fmt.Sprintf(`
+	const epsilon = %s
+	diff := math.Abs(float64(a) - float64(b))
+	if diff <= epsilon*math.Max(math.Abs(float64(a)), math.Abs(float64(b))) && !math.IsInf(diff, 0) {
+		return 0
+	}`, strconv.FormatFloat(epsilon, 'g', -1, 64))
+	}
+	return fmt.Sprintf(`
+%s
+func (%s) compare(a, b KType) int {%s
+	aNaN, bNaN := a != a, b != b
+	switch {
+	case a < b || aNaN && !bNaN:
+		return -1
+	case a > b || bNaN && !aNaN:
+		return 1
+	}
+	return 0
+}`, doc, recv, tolerance)
+}
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// integerBounds are the keys, in increasing order, that the datastructures
+// of each integer type are checked to order right. Subtracting them from
+// each other overflows.
+var integerBounds = map[string][]string{
+	"int":    {"math.MinInt", "math.MinInt + 1", "-1", "0", "1", "math.MaxInt - 1", "math.MaxInt"},
+	"int8":   {"math.MinInt8", "-1", "0", "1", "math.MaxInt8"},
+	"int16":  {"math.MinInt16", "-1", "0", "1", "math.MaxInt16"},
+	"int32":  {"math.MinInt32", "-1", "0", "1", "math.MaxInt32"},
+	"int64":  {"math.MinInt64", "math.MinInt64 + 1", "-1", "0", "1", "math.MaxInt64 - 1", "math.MaxInt64"},
+	"uint":   {"0", "1", "math.MaxUint / 2", "math.MaxUint/2 + 1", "math.MaxUint"},
+	"uint8":  {"0", "1", "math.MaxUint8"},
+	"uint16": {"0", "1", "math.MaxUint16"},
+	"uint32": {"0", "1", "math.MaxUint32/2 + 1", "math.MaxUint32"},
+	"uint64": {"0", "1", "math.MaxInt64", "math.MaxInt64 + 1", "math.MaxUint64 - 1", "math.MaxUint64"},
+}
+
+// integerBoundsTest checks the datastructures generated for one integer
+// type, given the name of the type and of its bounds.
+const integerBoundsTest = `
+func Test%[1]sBounds(t *testing.T) {
+	bounds := []%[2]s{%[3]s}
+	perm := rand.Perm(len(bounds))
+
+	m, s, h := NewSorted%[1]sToStringMap(), NewSorted%[1]sSet(), New%[1]sHeap()
+	for _, i := range perm {
+		m.Put(bounds[i], "")
+		s.Put(bounds[i])
+		h.Push(bounds[i])
+	}
+
+	var mkeys, skeys []%[2]s
+	m.Keys(func(k %[2]s, _ string) bool { mkeys = append(mkeys, k); return true })
+	s.Keys(func(k %[2]s) bool { skeys = append(skeys, k); return true })
+	if !reflect.DeepEqual(mkeys, bounds) {
+		t.Errorf("map: want keys %%v, got %%v", bounds, mkeys)
+	}
+	if !reflect.DeepEqual(skeys, bounds) {
+		t.Errorf("set: want keys %%v, got %%v", bounds, skeys)
+	}
+	for i := len(bounds) - 1; i >= 0; i-- {
+		if k := h.Pop(); k != bounds[i] {
+			t.Fatalf("heap: want to pop %%v, got %%v", bounds[i], k)
+		}
+	}
+}
+`
+
+func TestIntegerKeysAtTheirBounds(t *testing.T) {
+	var cmds [][]string
+	tests := bytes.NewBufferString("package bounds\n\nimport (\n\t\"math\"\n\t\"math/rand\"\n\t\"reflect\"\n\t\"testing\"\n)\n")
+	for typ, bounds := range integerBounds {
+		cmds = append(cmds,
+			[]string{"smap", "-key", typ, "-val", "string", "-o", typ + "_map.go"},
+			[]string{"sset", "-key", typ, "-o", typ + "_set.go"},
+			[]string{"heap", "-key", typ, "-o", typ + "_heap.go"},
+		)
+		name, err := parseType(typ, output{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(tests, integerBoundsTest, typeIdent(name.expr), typ, strings.Join(bounds, ", "))
+	}
+	testGenerated(t, "bounds", cmds, tests.String())
+}
+
+const floatOrderTest = `package floats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloatKeysOrder(t *testing.T) {
+	negZero := math.Copysign(0, -1)
+	keys := []float64{math.Inf(1), 1, math.NaN(), negZero, -math.MaxFloat64, 0, math.SmallestNonzeroFloat64, math.NaN(), math.Inf(-1), -1}
+	want := []float64{math.NaN(), math.Inf(-1), -math.MaxFloat64, -1, 0, math.SmallestNonzeroFloat64, 1, math.Inf(1)}
+
+	m, s, h := NewSortedFloat64ToStringMap(), NewSortedFloat64Set(), NewFloat64Heap()
+	for _, k := range keys {
+		m.Put(k, "")
+		s.Put(k)
+		h.Push(k)
+	}
+	var mkeys, skeys, hkeys []float64
+	m.Keys(func(k float64, _ string) bool { mkeys = append(mkeys, k); return true })
+	s.Keys(func(k float64) bool { skeys = append(skeys, k); return true })
+	for h.Len() > 0 {
+		hkeys = append([]float64{h.Pop()}, hkeys...)
+	}
+	check := func(what string, got, want []float64) {
+		if len(got) != len(want) {
+			t.Fatalf("%s: want keys %v, got %v", what, want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] && !(math.IsNaN(got[i]) && math.IsNaN(want[i])) {
+				t.Fatalf("%s: want keys %v, got %v", what, want, got)
+			}
+		}
+	}
+	check("map", mkeys, want)
+	check("set", skeys, want)
+	// the heap keeps the duplicates
+	check("heap", hkeys, []float64{math.NaN(), math.NaN(), math.Inf(-1), -math.MaxFloat64, -1, 0, 0, math.SmallestNonzeroFloat64, 1, math.Inf(1)})
+
+	if !m.Has(negZero) || !s.Contains(negZero) {
+		t.Error("-0 isn't found as +0")
+	}
+	if !m.Has(math.NaN()) || !s.Contains(math.NaN()) {
+		t.Error("NaN isn't found")
+	}
+}
+
+func TestFloatKeysWithinEpsilon(t *testing.T) {
+	m := NewSortedFloat32ToStringMap()
+	m.Put(1, "one")
+	if v, ok := m.Get(1.0000001); !ok || v != "one" {
+		t.Errorf("want 1.0000001 to be equal to 1, got %q, %v", v, ok)
+	}
+	if m.Has(1.01) {
+		t.Error("want 1.01 to differ from 1")
+	}
+	m.Put(0, "zero")
+	if m.Has(float32(math.SmallestNonzeroFloat32)) {
+		t.Error("want the smallest float32 to differ from 0")
+	}
+	m.Put(float32(math.Inf(1)), "inf")
+	if v, _ := m.Get(math.MaxFloat32); v == "inf" {
+		t.Error("want the largest float32 to differ from +Inf")
+	}
+}
+`
+
+func TestFloatKeysOrder(t *testing.T) {
+	testGenerated(t, "floats", [][]string{
+		{"smap", "-key", "float64", "-val", "string", "-o", "float64_map.go"},
+		{"sset", "-key", "float64", "-o", "float64_set.go"},
+		{"heap", "-key", "float64", "-o", "float64_heap.go"},
+		{"smap", "-key", "float32", "-val", "string", "-epsilon", "1e-6", "-o", "float32_map.go"},
+	}, floatOrderTest)
+}
+
+func TestEpsilonOnlyForFloatKeys(t *testing.T) {
+	app := cli.NewApp()
+	app.Commands = []cli.Command{heap()}
+	for _, args := range [][]string{
+		{"heap", "-key", "int", "-epsilon", "0.1"},
+		{"heap", "-key", "float64", "-epsilon", "-0.1"},
+		{"heap", "-key", "float64", "-epsilon", "0.1", "-generic"},
+	} {
+		if err := app.Run(append([]string{"datagen"}, args...)); err == nil {
+			t.Errorf("%v: want an error", args)
+		}
+	}
+}
+
+// compareFuncTest checks the datastructures whose keys are ordered by the
+// functions given with -cmp and -less.
+const compareFuncTest = `package funcs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareFuncs(t *testing.T) {
+	// by their elements, rather than by their length
+	m := NewSortedIntsToStringMap()
+	for _, k := range [][]int{{2}, {1, 2, 3}, {1, 2}} {
+		m.Put(k, "")
+	}
+	var keys [][]int
+	m.Keys(func(k []int, _ string) bool { keys = append(keys, k); return true })
+	if want := [][]int{{1, 2}, {1, 2, 3}, {2}}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("map: want keys %v, got %v", want, keys)
+	}
+
+	h := NewFloat64Heap(1, 3, 2)
+	if k := h.Pop(); k != 3 {
+		t.Errorf("heap: want to pop 3, got %v", k)
+	}
+
+	s, g := NewSortedStringSet(), NewSortedIntsSet()
+	s.Put("b")
+	s.Put("a")
+	g.Put([]int{2})
+	g.Put([]int{1, 2})
+	if k, _ := s.Min(); k != "a" {
+		t.Errorf("set: want a first, got %q", k)
+	}
+	if k, _ := g.Min(); !reflect.DeepEqual(k, []int{1, 2}) {
+		t.Errorf("generic set: want [1 2] first, got %v", k)
+	}
+}
+`
+
+func TestCompareFuncs(t *testing.T) {
+	testGenerated(t, "funcs", [][]string{
+		{"smap", "-key", "[]int", "-val", "string", "-cmp", "slices.Compare", "-o", "map.go"},
+		{"heap", "-key", "float64", "-less", "cmp.Less", "-tests", "-o", "heap.go"},
+		{"sset", "-key", "string", "-cmp", "strings.Compare", "-tests", "-bench", "-o", "set.go"},
+		{"sset", "-key", "[]int", "-cmp", "slices.Compare", "-generic", "-o", "generic_set.go"},
+	}, compareFuncTest)
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{heap()}
+	for _, args := range [][]string{
+		{"heap", "-key", "string", "-cmp", "strings.Compare", "-less", "cmp.Less"},
+		{"heap", "-key", "float64", "-cmp", "cmp.Compare", "-epsilon", "0.1"},
+		{"heap", "-key", "int", "-cmp", "a + b"},
+		{"heap", "-key", "string", "-less", "strings.Compare"},
+	} {
+		if err := app.Run(append([]string{"datagen"}, args...)); err == nil {
+			t.Errorf("%v: want an error", args)
+		}
+	}
+}
+
+func TestCompareMethodWarning(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	src := "package foo\n\ntype Entry struct{}\n\nfunc (e *Entry) Compare(other *Entry) int { return 0 }\n\ntype Name string\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "entry.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := output{dir: dir, pkgName: "foo"}
+
+	// the warnings, and how they show to write the Compare method
+	tests := map[string]struct {
+		warn    bool
+		snippet string
+	}{
+		"time.Time":       {},
+		"*Entry":          {},
+		"Entry":           {warn: true, snippet: "func (e Entry) Compare(other Entry) int {"},
+		"*Name":           {warn: true, snippet: "func (n *Name) Compare(other *Name) int {"},
+		"bool":            {warn: true},
+		"io.Reader":       {warn: true},
+		"struct{ A int }": {warn: true},
+	}
+	for name, want := range tests {
+		typ, err := parseType(name, out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		warning := compareMethodWarning(out, typ)
+		if (warning != "") != want.warn {
+			t.Errorf("%s: want a warning=%v, got %q", name, want.warn, warning)
+		}
+		if got := strings.Contains(warning, "Compare(other"); got != (want.snippet != "") || !strings.Contains(warning, want.snippet) {
+			t.Errorf("%s: want the snippet %q, got %q", name, want.snippet, warning)
+		}
+	}
+}
+
+// testGenerated runs the datagen commands, with their -o files in a package
+// named pkgName, and then the tests of that package.
+func testGenerated(t *testing.T, pkgName string, cmds [][]string, tests string) {
+	testGeneratedWith(t, pkgName, nil, cmds, tests)
+}
+
+// testGeneratedWith is testGenerated for a package that also holds files,
+// by name, which the generated code can use.
+func testGeneratedWith(t *testing.T, pkgName string, files map[string]string, cmds [][]string, tests string) {
+	if testing.Short() {
+		t.Skip("builds and runs the generated code")
+	}
+	gotool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("needs the go tool to run the generated code")
+	}
+	// inside of the module, for the generated code to build with its
+	// dependencies
+	dir, err := ioutil.TempDir(".", "_"+pkgName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{sortedMap(), sortedSet(), heap(), queue()}
+	for _, args := range cmds {
+		args = append([]string{"datagen"}, append(args, "-package", pkgName)...)
+		for i := 1; i < len(args); i++ {
+			if args[i-1] == "-o" {
+				args[i] = filepath.Join(dir, args[i])
+			}
+		}
+		if err := app.Run(args); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, pkgName+"_test.go"), []byte(tests), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(gotool, "test", ".")
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated code fails its tests: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// compositeKey is a key made of many fields, given like `(tenant string,
+// ts int64 desc)`. A struct of those fields is generated along the
+// datastructure, which orders its keys by their first field, then by their
+// second one, and so on, each in increasing order unless it's followed by
+// desc.
+type compositeKey struct {
+	fields []compositeField
+}
+
+type compositeField struct {
+	name string
+	typ  *goType
+	desc bool
+	// compare are the statements returning the order of the fields of two
+	// keys, k and other, when they differ.
+	compare string
+	imports []importSpec
+}
+
+// parseKey parses the key given on the command line, which is either a
+// type or a composite key, and derives an identifier from it to name the
+// datastructure. The type of a composite key is only known once the
+// datastructure is named, by keyTypeOrder.
+func parseKey(key string, out output) (*goType, *compositeKey, string, error) {
+	if isCompositeKey(key) {
+		composite, err := parseCompositeKey(key, out)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		return nil, composite, composite.ident(), nil
+	}
+	ktype, err := parseType(key, out)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return ktype, nil, typeIdent(ktype.expr), nil
+}
+
+// keyTypeOrder returns the type of the keys of the datastructure, and
+// their order. The type of a composite key is named after the
+// datastructure.
+func keyTypeOrder(ctx *cli.Context, out output, n names, ktype *goType, composite *compositeKey) (*goType, *keyOrder, error) {
+	if composite == nil {
+		order, err := newKeyOrder(ctx, out, ktype)
+		return ktype, order, err
+	}
+	ktype = composite.keyType(n.typ + "Key")
+	order, err := composite.order(ctx, ktype)
+	return ktype, order, err
+}
+
+// isCompositeKey tells if the key given on the command line is a
+// composite key.
+func isCompositeKey(key string) bool {
+	return strings.HasPrefix(strings.TrimSpace(key), "(")
+}
+
+// parseCompositeKey parses the fields of a composite key. They're exported
+// in the generated struct, so `tenant` is its field `Tenant`.
+func parseCompositeKey(key string, out output) (*compositeKey, error) {
+	key = strings.TrimSpace(key)
+	if !strings.HasSuffix(key, ")") {
+		return nil, fmt.Errorf("composite key %q isn't closed by a parenthesis", key)
+	}
+
+	c := new(compositeKey)
+	seen := make(map[string]bool)
+	for _, spec := range splitFields(key[1 : len(key)-1]) {
+		words := strings.Fields(spec)
+		field := compositeField{}
+		if n := len(words); n > 2 && (words[n-1] == "asc" || words[n-1] == "desc") {
+			field.desc = words[n-1] == "desc"
+			words = words[:n-1]
+		}
+		if len(words) < 2 {
+			return nil, fmt.Errorf("composite key %s: want a name and a type, like `ts int64`, got %q", key, strings.TrimSpace(spec))
+		}
+		field.name = upperFirst(words[0])
+		if !token.IsIdentifier(field.name) || seen[field.name] {
+			return nil, fmt.Errorf("composite key %s: invalid or repeated field name %q", key, words[0])
+		}
+		seen[field.name] = true
+
+		typ, err := parseType(strings.Join(words[1:], " "), out)
+		if err != nil {
+			return nil, fmt.Errorf("composite key %s: %v", key, err)
+		}
+		resolved, err := typeOf(out, typ)
+		if err != nil {
+			return nil, fmt.Errorf("composite key %s: %v", key, err)
+		}
+		x, y := "k."+field.name, "other."+field.name
+		if field.desc {
+			x, y = y, x
+		}
+		compare, imports, ok := compareFields(resolved, x, y)
+		if !ok {
+			return nil, fmt.Errorf("composite key %s: can't order fields of type %s", key, typ.name)
+		}
+		field.typ, field.compare, field.imports = typ, compare, imports
+		c.fields = append(c.fields, field)
+	}
+	if len(c.fields) == 0 {
+		return nil, fmt.Errorf("composite key %s has no fields", key)
+	}
+	return c, nil
+}
+
+// splitFields splits the fields of a composite key on the commas that
+// aren't part of their types.
+func splitFields(s string) []string {
+	var fields []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				fields = append(fields, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if strings.TrimSpace(s[start:]) != "" {
+		fields = append(fields, s[start:])
+	}
+	return fields
+}
+
+// ident derives an identifier from the names of the fields, used like
+// typeIdent is to name the datastructure.
+func (c *compositeKey) ident() string {
+	var ident string
+	for _, f := range c.fields {
+		ident += f.name
+	}
+	return ident
+}
+
+// keyType is the struct of the fields, named name.
+func (c *compositeKey) keyType(name string) *goType {
+	typ := &goType{expr: ast.NewIdent(name), name: name}
+	for _, f := range c.fields {
+		typ.imports = append(typ.imports, f.typ.imports...)
+		typ.imports = append(typ.imports, f.imports...)
+	}
+	return typ
+}
+
+// order of the keys, which are only ordered by their fields.
+func (c *compositeKey) order(ctx *cli.Context, ktype *goType) (*keyOrder, error) {
+	for _, flag := range []string{"cmp", "less", "key-field", "epsilon"} {
+		if ctx.IsSet(flag) {
+			return nil, errors.New("composite keys are ordered by their fields, they can't be used with -cmp, -less, -key-field or -epsilon")
+		}
+	}
+	return &keyOrder{ktype: ktype, composite: c}, nil
+}
+
+// decl returns the declaration of the struct named name, and of its
+// Compare method.
+func (c *compositeKey) decl(name string) string {
+	buf := bytes.NewBuffer(nil)
+	var order []string
+	for _, f := range c.fields {
+		if f.desc {
+			order = append(order, f.name+" in decreasing order")
+		} else {
+			order = append(order, f.name)
+		}
+	}
+	buf.WriteString(docComment(fmt.Sprintf("%s is a composite key, ordered by its %s.", name, strings.Join(order, ", then by its "))))
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, f := range c.fields {
+		fmt.Fprintf(buf, "\t%s %s\n", f.name, f.typ.name)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+	buf.WriteString(docComment(fmt.Sprintf("Compare orders the keys by their %s.", strings.Join(order, ", then by their "))))
+	fmt.Fprintf(buf, "func (k %s) Compare(other %[1]s) int {\n", name)
+	for _, f := range c.fields {
+		fmt.Fprintf(buf, "%s\n", f.compare)
+	}
+	fmt.Fprintf(buf, "return 0\n}\n")
+	return buf.String()
+}
+
+// docComment wraps text in comment lines of about 76 columns.
+func docComment(text string) string {
+	var lines []string
+	line := "//"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > 76 && line != "//" {
+			lines = append(lines, line)
+			line = "//"
+		}
+		line += " " + word
+	}
+	return strings.Join(append(lines, line), "\n") + "\n"
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+func TestSplitFieldsLeavesTypesWhole(t *testing.T) {
+	got := splitFields("tenant string, ts int64 desc, tags map[string]func(a, b int), ")
+	want := []string{"tenant string", " ts int64 desc", " tags map[string]func(a, b int)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+const compositeKeyTest = `package composite
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompositeKeys(t *testing.T) {
+	events := NewEvents()
+	for _, tenant := range []string{"b", "a", "c"} {
+		for ts := int64(0); ts < 5; ts++ {
+			events.Put(EventsKey{Tenant: tenant, Ts: ts}, tenant)
+		}
+	}
+
+	// the entries of tenant a from 3 to 1, since the timestamps decrease
+	var got []EventsKey
+	events.RangedKeys(EventsKey{Tenant: "a", Ts: 3}, EventsKey{Tenant: "a", Ts: 1}, func(k EventsKey, _ string) bool {
+		got = append(got, k)
+		return true
+	})
+	want := []EventsKey{{"a", 3}, {"a", 2}, {"a", 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+	if k, _, _ := events.Min(); k != (EventsKey{"a", 4}) {
+		t.Errorf("want the latest event of a first, got %v", k)
+	}
+
+	now := time.Now()
+	set := NewSortedNameAtRawSet()
+	set.Put(SortedNameAtRawSetKey{Name: "x", At: now, Raw: []byte("b")})
+	set.Put(SortedNameAtRawSetKey{Name: "x", At: now, Raw: []byte("a")})
+	set.Put(SortedNameAtRawSetKey{Name: "x", At: now.Add(-time.Hour), Raw: []byte("c")})
+	var raws []string
+	set.Keys(func(k SortedNameAtRawSetKey) bool {
+		raws = append(raws, string(k.Raw))
+		return true
+	})
+	if want := []string{"c", "a", "b"}; !reflect.DeepEqual(raws, want) {
+		t.Errorf("want %v, got %v", want, raws)
+	}
+
+	generic := NewSortedPriorityNameSet()
+	generic.Put(SortedPriorityNameSetKey{Priority: 1, Name: "b"})
+	generic.Put(SortedPriorityNameSetKey{Priority: 2, Name: "a"})
+	if k, _ := generic.Min(); k.Priority != 2 {
+		t.Errorf("want the highest priority first, got %v", k)
+	}
+}
+`
+
+func TestCompositeKeys(t *testing.T) {
+	testGenerated(t, "composite", [][]string{
+		{"smap", "-key", "(tenant string, ts int64 desc)", "-val", "string", "-type", "Events", "-o", "events.go"},
+		{"sset", "-key", "(name string, at time.Time, raw []byte asc)", "-o", "set.go"},
+		{"sset", "-key", "(priority float64 desc, name string)", "-generic", "-o", "generic_set.go"},
+	}, compositeKeyTest)
+}
+
+func TestCompositeKeyErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{sortedSet()}
+	for _, key := range []string{
+		"(tenant string",
+		"()",
+		"(string)",
+		"(a int, a string)",
+		"(tags []string)",
+		"(1a int)",
+	} {
+		args := []string{"datagen", "sset", "-key", key, "-package", "foo", "-o", filepath.Join(dir, "set.go")}
+		if err := app.Run(args); err == nil {
+			t.Errorf("%s: want an error", key)
+		}
+	}
+	args := []string{"datagen", "sset", "-key", "(a int)", "-cmp", "compareA", "-package", "foo", "-o", filepath.Join(dir, "set.go")}
+	if err := app.Run(args); err == nil {
+		t.Error("want an error ordering a composite key with -cmp")
+	}
+}
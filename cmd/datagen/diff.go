@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around the changes.
+const diffContext = 3
+
+// diffOp is a line of a diff: kept (' '), removed ('-') or added ('+'),
+// with the line numbers it has in a and in b.
+type diffOp struct {
+	kind  byte
+	line  string
+	aLine int
+	bLine int
+}
+
+// unifiedDiff returns the changes from a to b in the unified format, or
+// an empty string if they hold the same lines.
+func unifiedDiff(aName, bName string, a, b []byte) string {
+	ops := diffLines(splitLines(a), splitLines(b))
+
+	var changes []int
+	for i, op := range ops {
+		if op.kind != ' ' {
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	out := bytes.NewBuffer(nil)
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", aName, bName)
+	for i := 0; i < len(changes); {
+		// a hunk holds the changes whose contexts touch
+		j := i
+		for j+1 < len(changes) && changes[j+1]-changes[j] <= 2*diffContext {
+			j++
+		}
+		from, to := changes[i]-diffContext, changes[j]+diffContext+1
+		if from < 0 {
+			from = 0
+		}
+		if to > len(ops) {
+			to = len(ops)
+		}
+		writeHunk(out, ops[from:to])
+		i = j + 1
+	}
+	return out.String()
+}
+
+func writeHunk(out *bytes.Buffer, ops []diffOp) {
+	aStart, bStart := ops[0].aLine, ops[0].bLine
+	aLen, bLen := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			aLen++
+		}
+		if op.kind != '-' {
+			bLen++
+		}
+	}
+	// an empty range is given by the line before it
+	if aLen == 0 {
+		aStart--
+	}
+	if bLen == 0 {
+		bStart--
+	}
+	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+	for _, op := range ops {
+		fmt.Fprintf(out, "%c%s\n", op.kind, op.line)
+	}
+}
+
+// diffLines finds the fewest lines to remove from a and add to b, by way
+// of Myers' algorithm in linear space. The lines removed from a run of
+// changes come before those added.
+func diffLines(a, b []string) []diffOp {
+	var ops []diffOp
+	diffRange(a, b, &ops)
+
+	// the removed lines of each run of changes come first
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		j := i
+		for j < len(ops) && ops[j].kind != ' ' {
+			j++
+		}
+		sort.SliceStable(ops[i:j], func(x, y int) bool { return ops[i+x].kind == '-' && ops[i+y].kind == '+' })
+		i = j
+	}
+	aLine, bLine := 1, 1
+	for i := range ops {
+		ops[i].aLine, ops[i].bLine = aLine, bLine
+		if ops[i].kind != '+' {
+			aLine++
+		}
+		if ops[i].kind != '-' {
+			bLine++
+		}
+	}
+	return ops
+}
+
+// diffRange appends the changes from a to b to ops, without their line
+// numbers. It splits a and b around their middle snake, and recurses on
+// each side of it.
+func diffRange(a, b []string, ops *[]diffOp) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	for _, line := range a[:prefix] {
+		*ops = append(*ops, diffOp{kind: ' ', line: line})
+	}
+
+	switch midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]; {
+	case len(midA) == 0:
+		for _, line := range midB {
+			*ops = append(*ops, diffOp{kind: '+', line: line})
+		}
+	case len(midB) == 0:
+		for _, line := range midA {
+			*ops = append(*ops, diffOp{kind: '-', line: line})
+		}
+	default:
+		// both sides differ at their ends, so the snake leaves changes on
+		// each of its sides, which are smaller than a and b
+		x, y, u, v := middleSnake(midA, midB)
+		diffRange(midA[:x], midB[:y], ops)
+		for _, line := range midA[x:u] {
+			*ops = append(*ops, diffOp{kind: ' ', line: line})
+		}
+		diffRange(midA[u:], midB[v:], ops)
+	}
+
+	for _, line := range a[len(a)-suffix:] {
+		*ops = append(*ops, diffOp{kind: ' ', line: line})
+	}
+}
+
+// middleSnake finds the lines a[x:u], equal to b[y:v], in the middle of
+// a shortest path of changes from a to b. The path is searched from both
+// of its ends at once, keeping only the furthest point reached on each
+// diagonal k, where x-y == k.
+func middleSnake(a, b []string) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	max := n + m
+	delta := n - m
+	odd := delta%2 != 0
+
+	// the diagonals of the search from the end are shifted by delta
+	off := 2*max + 2
+	forward := make([]int, 2*off+1)
+	backward := make([]int, 2*off+1)
+	backward[off+delta-1] = n
+
+	for d := 0; d <= (max+1)/2; d++ {
+		for k := -d; k <= d; k += 2 {
+			if k == -d || (k != d && forward[off+k-1] < forward[off+k+1]) {
+				x = forward[off+k+1]
+			} else {
+				x = forward[off+k-1] + 1
+			}
+			y = x - k
+			u, v = x, y
+			for u < n && v < m && a[u] == b[v] {
+				u, v = u+1, v+1
+			}
+			forward[off+k] = u
+			if odd && k >= delta-(d-1) && k <= delta+(d-1) && u >= backward[off+k] {
+				return x, y, u, v
+			}
+		}
+		for k := -d; k <= d; k += 2 {
+			c := k + delta
+			if k == d || (k != -d && backward[off+c-1] < backward[off+c+1]) {
+				u = backward[off+c-1]
+			} else {
+				u = backward[off+c+1] - 1
+			}
+			v = u - c
+			x, y = u, v
+			for x > 0 && y > 0 && a[x-1] == b[y-1] {
+				x, y = x-1, y-1
+			}
+			backward[off+c] = x
+			if !odd && c >= -d && c <= d && x <= forward[off+c] {
+				return x, y, u, v
+			}
+		}
+	}
+	panic("no middle snake between the lines")
+}
+
+func splitLines(src []byte) []string {
+	if len(src) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(src), "\n"), "\n")
+}
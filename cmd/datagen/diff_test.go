@@ -0,0 +1,90 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "0\n1\n2\n3\n4\n5\n6\n7\nhuit\n9\n10\n11\n12\n"
+	want := `--- a
++++ b
+@@ -1,3 +1,4 @@
++0
+ 1
+ 2
+ 3
+@@ -5,7 +6,7 @@
+ 5
+ 6
+ 7
+-8
++huit
+ 9
+ 10
+ 11
+`
+	if got := unifiedDiff("a", "b", []byte(a), []byte(b)); got != want {
+		t.Errorf("want diff:\n%s\ngot:\n%s", want, got)
+	}
+	if got := unifiedDiff("a", "a", []byte(a), []byte(a)); got != "" {
+		t.Errorf("want no diff between the same lines, got:\n%s", got)
+	}
+	if got := unifiedDiff("a", "b", nil, []byte("1\n")); got != "--- a\n+++ b\n@@ -0,0 +1,1 @@\n+1\n" {
+		t.Errorf("want the lines of a new file added, got:\n%s", got)
+	}
+}
+
+func TestDiffLinesIsShortest(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	lines := func() []string {
+		l := make([]string, rnd.Intn(20))
+		for i := range l {
+			l[i] = string(rune('a' + rnd.Intn(4)))
+		}
+		return l
+	}
+	for n := 0; n < 1000; n++ {
+		a, b := lines(), lines()
+
+		// lcs[i][j] is the length of the longest common subsequence of
+		// a[i:] and b[j:]
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] > lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+
+		var gotA, gotB []string
+		changes := 0
+		for _, op := range diffLines(a, b) {
+			if op.kind != '+' {
+				gotA = append(gotA, op.line)
+			}
+			if op.kind != '-' {
+				gotB = append(gotB, op.line)
+			}
+			if op.kind != ' ' {
+				changes++
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("%q to %q: the diff gives %q to %q", a, b, gotA, gotB)
+		}
+		if want := len(a) + len(b) - 2*lcs[0][0]; changes != want {
+			t.Fatalf("%q to %q: want %d changes, got %d", a, b, want, changes)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+var keyFieldFlag = cli.StringFlag{
+	Name:  "key-field",
+	Usage: "order struct keys by this field, or path of fields like Meta.CreatedAt, instead of their Compare method",
+}
+
+// keyField is the field of the keys that orders them.
+type keyField struct {
+	// path of the field, like Meta.CreatedAt.
+	path string
+	// compare are the statements returning the order of the fields x and
+	// y of two keys when they differ.
+	compare string
+	// imports needed by those statements.
+	imports []importSpec
+}
+
+// lookupKeyField finds the field of the keys of type ktype at path, which
+// can go through embedded structs and pointers to structs.
+func lookupKeyField(out output, ktype *goType, path string) (*keyField, error) {
+	typ, err := typeOf(out, ktype)
+	if err != nil {
+		return nil, err
+	}
+	// types are named like in the generated code
+	qualifier := func(pkg *types.Package) string {
+		if pkg.Name() == out.pkgName {
+			return ""
+		}
+		return pkg.Name()
+	}
+	for _, name := range strings.Split(path, ".") {
+		var pkg *types.Package
+		if named, ok := derefType(typ).(*types.Named); ok {
+			pkg = named.Obj().Pkg()
+		}
+		obj, _, _ := types.LookupFieldOrMethod(typ, true, pkg, name)
+		field, ok := obj.(*types.Var)
+		if !ok || !field.IsField() {
+			return nil, fmt.Errorf("-key-field %s: %s has no field %s", path, types.TypeString(typ, qualifier), name)
+		}
+		// the output's package is type-checked under its name, and the
+		// generated code can't see the unexported fields of the others
+		if !field.Exported() && field.Pkg() != nil && field.Pkg().Path() != out.pkgName {
+			return nil, fmt.Errorf("-key-field %s: field %s of %s is unexported", path, name, types.TypeString(typ, qualifier))
+		}
+		typ = field.Type()
+	}
+	compare, imports, ok := compareFields(typ, "x", "y")
+	if !ok {
+		return nil, fmt.Errorf("-key-field %s: can't order fields of type %s, order the keys with -cmp or -less instead", path, types.TypeString(typ, qualifier))
+	}
+	return &keyField{path: path, compare: compare, imports: imports}, nil
+}
+
+func derefType(typ types.Type) types.Type {
+	if ptr, ok := typ.Underlying().(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+	return typ
+}
+
+// compareFields returns the statements returning the order of x and y,
+// fields of type typ, when they differ, and falling through when they're
+// equal, along with the imports they need. The fields are compared like
+// keys of their type would be: time.Time by Before and After, types with a
+// Compare method by that method, and builtin types by their operators.
+// It's false when fields of type typ can't be ordered.
+func compareFields(typ types.Type, x, y string) (string, []importSpec, bool) {
+	less, greater := "%[1]s < %[2]s", "%[1]s > %[2]s"
+	var imports []importSpec
+
+	named, _ := typ.(*types.Named)
+	switch basic, _ := typ.Underlying().(*types.Basic); {
+	case named != nil && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Time":
+		less, greater = "%[1]s.Before(%[2]s)", "%[1]s.After(%[2]s)"
+	case hasCompareMethod(typ):
+		return fmt.Sprintf("if c := %s.Compare(%s); c != 0 {\n\treturn c\n}", x, y), nil, true
+	case isByteSlice(typ):
+		imports = append(imports, importSpec{path: "bytes"})
+		return fmt.Sprintf("if c := bytes.Compare(%s, %s); c != 0 {\n\treturn c\n}", x, y), imports, true
+	case basic == nil:
+		return "", nil, false
+	case basic.Info()&types.IsFloat != 0:
+		// NaNs are smaller than any other float
+		less, greater = "%[1]s < %[2]s || %[1]s != %[1]s && %[2]s == %[2]s", "%[1]s > %[2]s || %[2]s != %[2]s && %[1]s == %[1]s"
+	case basic.Info()&types.IsBoolean != 0:
+		less, greater = "!%[1]s && %[2]s", "%[1]s && !%[2]s"
+	case basic.Info()&(types.IsInteger|types.IsString) == 0:
+		return "", nil, false
+	}
+	return fmt.Sprintf("switch {\ncase "+less+":\n\treturn -1\ncase "+greater+":\n\treturn 1\n}", x, y), imports, true
+}
+
+func isByteSlice(typ types.Type) bool {
+	slice, ok := typ.Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	elem, ok := slice.Elem().Underlying().(*types.Basic)
+	return ok && elem.Kind() == types.Byte
+}
+
+// hasCompareMethod tells if typ has a `Compare(other T) int` method.
+func hasCompareMethod(typ types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(typ, true, nil, "Compare")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 {
+		return false
+	}
+	result, ok := sig.Results().At(0).Type().(*types.Basic)
+	return ok && result.Kind() == types.Int && types.Identical(sig.Params().At(0).Type(), typ)
+}
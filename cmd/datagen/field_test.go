@@ -0,0 +1,145 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// jobSrc declares the keys ordered by one of their fields.
+const jobSrc = `package fields
+
+import (
+	"net/netip"
+	"time"
+)
+
+type Priority int
+
+type Meta struct {
+	CreatedAt time.Time
+	Weight    float64
+}
+
+type Job struct {
+	Name     string
+	Priority Priority
+	Deadline time.Time
+	Addr     netip.Addr
+	Raw      []byte
+	Done     bool
+	*Meta
+
+	retries int
+}
+
+type Tags struct{ Names []string }
+
+func makeJob(i int) *Job {
+	at := time.Unix(int64(i), 0)
+	return &Job{Priority: Priority(i), Deadline: at, Meta: &Meta{CreatedAt: at}}
+}
+`
+
+const keyFieldTest = `package fields
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestKeyFields(t *testing.T) {
+	now := time.Now()
+	a := &Job{Name: "a", Priority: 3, Deadline: now.Add(time.Hour), Addr: netip.MustParseAddr("10.0.0.1"), Raw: []byte("b"), Done: true, Meta: &Meta{CreatedAt: now, Weight: 2}}
+	b := &Job{Name: "b", Priority: 1, Deadline: now, Addr: netip.MustParseAddr("10.0.0.2"), Raw: []byte("a"), Meta: &Meta{CreatedAt: now.Add(time.Hour), Weight: 1}}
+
+	byDeadline := NewJobHeap(a, b)
+	if byDeadline.Pop() != a {
+		t.Error("want the latest deadline first")
+	}
+	byPriority := NewJobsByPriority(a, b)
+	if byPriority.Pop() != a {
+		t.Error("want the highest priority first")
+	}
+	byCreation := NewSortedJobSet()
+	byCreation.Put(a)
+	byCreation.Put(b)
+	if k, _ := byCreation.Min(); k != a {
+		t.Error("want the earliest creation first")
+	}
+
+	cases := []struct {
+		what string
+		cmp  func(a, b *Job) int
+		want int
+	}{
+		{"name", JobsByName{}.compare, -1},
+		{"addr", JobsByAddr{}.compare, -1},
+		{"raw", JobsByRaw{}.compare, 1},
+		{"done", JobsByDone{}.compare, 1},
+		{"weight", JobsByWeight{}.compare, 1},
+		{"priority", JobsByPriority{}.compare, 1},
+	}
+	for _, c := range cases {
+		if got := c.cmp(a, b); got != c.want {
+			t.Errorf("%s: want %d, got %d", c.what, c.want, got)
+		}
+		if got := c.cmp(a, a); got != 0 {
+			t.Errorf("%s: want a key equal to itself, got %d", c.what, got)
+		}
+	}
+
+	m := NewGenericJobs()
+	m.Put(a, "a")
+	m.Put(b, "b")
+	if _, v, _ := m.Min(); v != "b" {
+		t.Errorf("generic: want the lowest priority first, got %q", v)
+	}
+}
+`
+
+func TestKeyField(t *testing.T) {
+	testGeneratedWith(t, "fields", map[string]string{"job.go": jobSrc}, [][]string{
+		{"heap", "-key", "*Job", "-key-field", "Deadline", "-tests", "-key-gen", "makeJob", "-o", "job_heap.go"},
+		{"heap", "-key", "*Job", "-key-field", "Priority", "-type", "JobsByPriority", "-o", "by_priority.go"},
+		{"sset", "-key", "*Job", "-key-field", "Meta.CreatedAt", "-tests", "-key-gen", "makeJob", "-o", "job_set.go"},
+		{"heap", "-key", "*Job", "-key-field", "Name", "-type", "JobsByName", "-o", "by_name.go"},
+		{"heap", "-key", "*Job", "-key-field", "Addr", "-type", "JobsByAddr", "-o", "by_addr.go"},
+		{"heap", "-key", "*Job", "-key-field", "Raw", "-type", "JobsByRaw", "-o", "by_raw.go"},
+		{"heap", "-key", "*Job", "-key-field", "Done", "-type", "JobsByDone", "-o", "by_done.go"},
+		{"heap", "-key", "*Job", "-key-field", "Weight", "-type", "JobsByWeight", "-o", "by_weight.go"},
+		{"heap", "-key", "*Job", "-key-field", "retries", "-type", "JobsByRetries", "-o", "by_retries.go"},
+		{"smap", "-key", "*Job", "-val", "string", "-key-field", "Priority", "-type", "GenericJobs", "-generic", "-o", "generic_jobs.go"},
+	}, keyFieldTest)
+}
+
+func TestKeyFieldErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "job.go"), []byte(jobSrc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	app := cli.NewApp()
+	app.Commands = []cli.Command{heap()}
+	for _, args := range [][]string{
+		{"heap", "-key", "*Job", "-key-field", "Missing"},
+		{"heap", "-key", "*Job", "-key-field", "Name.Missing"},
+		{"heap", "-key", "Tags", "-key-field", "Names"},
+		{"heap", "-key", "*Job", "-key-field", "Name", "-cmp", "compareJobs"},
+		{"heap", "-key", "int", "-key-field", "Name"},
+		{"heap", "-key", "time.Time", "-key-field", "wall"},
+	} {
+		args = append([]string{"datagen"}, append(args, "-o", filepath.Join(dir, "heap.go"))...)
+		if err := app.Run(args); err == nil || !strings.Contains(err.Error(), "-key-field") {
+			t.Errorf("%v: want an error about -key-field, got %v", args, err)
+		}
+	}
+}
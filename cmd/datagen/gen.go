@@ -0,0 +1,414 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+	"gopkg.in/yaml.v2"
+)
+
+// manifestNames are the files looked for by `datagen gen` when it isn't
+// given one.
+var manifestNames = []string{"datagen.yaml", "datagen.yml", "datagen.json"}
+
+// manifest lists the datastructures to generate in one run.
+type manifest struct {
+	// Package the datastructures are generated for, unless it can be
+	// found in the output directories.
+	Package    string      `json:"package" yaml:"package"`
+	Structures []structure `json:"structures" yaml:"structures"`
+}
+
+// structure is a datastructure of a manifest. Its options are the other
+// flags of the command generating it, like `tests: true` for -tests.
+type structure struct {
+	Kind    string                 `json:"kind" yaml:"kind"`
+	Key     string                 `json:"key" yaml:"key"`
+	Val     string                 `json:"val" yaml:"val"`
+	Type    string                 `json:"type" yaml:"type"`
+	Output  string                 `json:"output" yaml:"output"`
+	Options map[string]interface{} `json:"options" yaml:"options"`
+}
+
+func genCommand() cli.Command {
+	fileFlag := cli.StringFlag{
+		Name:  "file, f",
+		Usage: "manifest listing the datastructures to generate (default: datagen.yaml, datagen.yml or datagen.json)",
+	}
+
+	return cli.Command{
+		Name:  "gen",
+		Usage: "Generate the datastructures listed in a manifest.",
+		Description: `Generate every datastructure listed in a datagen.yaml or datagen.json
+manifest, like:
+
+    package: codegen
+    structures:
+      - kind: smap
+        key: string
+        val: int
+        output: string_map.go
+      - kind: heap
+        key: '*Job'
+        output: job_heap.go
+        options:
+          tests: true
+          key-gen: makeJob
+
+The outputs are relative to the manifest. The declarations that many
+datastructures of a package share are only written once, while different
+declarations of the same name are reported.`,
+		Flags: []cli.Flag{fileFlag},
+		Action: func(ctx *cli.Context) error {
+			filename, err := findManifest(ctx.String("file"))
+			if err != nil {
+				return err
+			}
+			m, err := readManifest(filename)
+			if err != nil {
+				return err
+			}
+			cmds, err := m.commands(ctx.App, filepath.Dir(filename))
+			if err != nil {
+				return fmt.Errorf("%s: %v", filename, err)
+			}
+
+			// the files are only written once they've all been generated
+			// and checked together
+			if ctx.App.Metadata == nil {
+				ctx.App.Metadata = make(map[string]interface{})
+			}
+			write, _ := ctx.App.Metadata[captureKey].(captureFunc)
+			var files []genFile
+			pending := make(map[string][]byte)
+			ctx.App.Metadata[captureKey] = captureFunc(func(filename string, src []byte) error {
+				files = append(files, genFile{filename: filename, src: src})
+				pending[filename] = src
+				return nil
+			})
+			ctx.App.Metadata[pendingKey] = pending
+			ctx.App.Metadata[manifestKey] = filename
+			defer func() {
+				ctx.App.Metadata[captureKey] = write
+				delete(ctx.App.Metadata, pendingKey)
+				delete(ctx.App.Metadata, manifestKey)
+			}()
+			for _, args := range cmds {
+				if err := ctx.App.Run(args); err != nil {
+					return fmt.Errorf("%s: %v", strings.Join(args[1:], " "), err)
+				}
+			}
+
+			if err := dedupeDecls(files); err != nil {
+				return fmt.Errorf("%s: %v", filename, err)
+			}
+			for _, f := range files {
+				if write == nil {
+					if err := os.MkdirAll(filepath.Dir(f.filename), 0755); err != nil {
+						return err
+					}
+				}
+				if err := (output{filename: f.filename, capture: write}).write(f.src); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// findManifest returns the path of the manifest given by name, or of the
+// one found in the current directory.
+func findManifest(name string) (string, error) {
+	if name != "" {
+		return filepath.Abs(name)
+	}
+	for _, name := range manifestNames {
+		if _, err := os.Stat(name); err == nil {
+			return filepath.Abs(name)
+		}
+	}
+	return "", fmt.Errorf("no manifest found, looked for %s", strings.Join(manifestNames, ", "))
+}
+
+// readManifest decodes the manifest as JSON or YAML, depending on its
+// extension. Unknown fields are errors, since they're likely misspelled.
+func readManifest(filename string) (*manifest, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	m := new(manifest)
+	if filepath.Ext(filename) == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(m)
+	} else {
+		err = yaml.UnmarshalStrict(data, m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", filename, err)
+	}
+	return m, nil
+}
+
+// commands returns the datagen commands generating the datastructures of
+// the manifest, with their outputs found relative to dir.
+func (m *manifest) commands(app *cli.App, dir string) ([][]string, error) {
+	kinds := map[string]bool{"heap": true, "queue": true, "sorted-map": true, "sorted-set": true}
+	reserved := map[string]bool{"key": true, "val": true, "type": true, "o": true, "output": true, "package": true}
+
+	if len(m.Structures) == 0 {
+		return nil, errors.New("no structures listed")
+	}
+	var cmds [][]string
+	outputs := make(map[string]int)
+	for i, s := range m.Structures {
+		desc := fmt.Sprintf("structure %d", i+1)
+		cmd := app.Command(s.Kind)
+		if cmd == nil || !kinds[cmd.Name] {
+			return nil, fmt.Errorf("%s: unknown kind %q, want heap, queue, smap or sset", desc, s.Kind)
+		}
+		if s.Key == "" || s.Output == "" {
+			return nil, fmt.Errorf("%s: needs a key and an output", desc)
+		}
+		if (s.Val != "") != (cmd.Name == "sorted-map") {
+			return nil, fmt.Errorf("%s: only the sorted maps have values", desc)
+		}
+		output := filepath.Join(dir, s.Output)
+		if j, ok := outputs[output]; ok {
+			return nil, fmt.Errorf("%s: %s is also the output of structure %d", desc, s.Output, j+1)
+		}
+		outputs[output] = i
+
+		args := []string{"datagen", cmd.Name, "-key", s.Key}
+		if s.Val != "" {
+			args = append(args, "-val", s.Val)
+		}
+		if s.Type != "" {
+			args = append(args, "-type", s.Type)
+		}
+		args = append(args, "-o", output)
+		if m.Package != "" {
+			args = append(args, "-package", m.Package)
+		}
+		var names []string
+		for name := range s.Options {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if reserved[name] {
+				return nil, fmt.Errorf("%s: %s isn't an option, but a field of the structure", desc, name)
+			}
+			switch value := s.Options[name].(type) {
+			case bool:
+				if value {
+					args = append(args, "-"+name)
+				}
+			case nil:
+				return nil, fmt.Errorf("%s: option %s has no value", desc, name)
+			default:
+				args = append(args, "-"+name, fmt.Sprint(value))
+			}
+		}
+		cmds = append(cmds, args)
+	}
+	return cmds, nil
+}
+
+// genFile is a file generated for a manifest, before it's written.
+type genFile struct {
+	filename string
+	src      []byte
+}
+
+// dedupeDecls leaves the top-level declarations that many files of a
+// package share in the first file declaring them only. Different
+// declarations of the same name are an error.
+func dedupeDecls(files []genFile) error {
+	type declared struct {
+		file int
+		src  string
+	}
+	seen := make(map[string]declared)
+	for i := range files {
+		f := &files[i]
+		fset := token.NewFileSet()
+		file, err := parser.ParseFile(fset, f.filename, f.src, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		pkg := filepath.Dir(f.filename) + ":" + file.Name.Name
+
+		// removed from the end, for the offsets to remain valid
+		var dups [][2]int
+		removed := make(map[ast.Decl]bool)
+		for _, decl := range file.Decls {
+			name := declName(decl)
+			if name == "" {
+				continue
+			}
+			from, to := decl.Pos(), decl.End()
+			if doc := declDoc(decl); doc != nil {
+				from = doc.Pos()
+			}
+			start, end := fset.Position(from).Offset, fset.Position(to).Offset
+			src := string(f.src[start:end])
+
+			prev, ok := seen[pkg+"."+name]
+			if !ok {
+				seen[pkg+"."+name] = declared{file: i, src: src}
+				continue
+			}
+			if prev.src != src {
+				return fmt.Errorf("%s is declared differently by %s and %s", name, filepath.Base(files[prev.file].filename), filepath.Base(f.filename))
+			}
+			if prev.file == i {
+				continue
+			}
+			dups = append(dups, [2]int{start, end})
+			removed[decl] = true
+		}
+		if len(dups) == 0 {
+			continue
+		}
+		dups = append(dups, unusedImports(fset, file, f.src, removed, filepath.Dir(f.filename))...)
+		sort.Slice(dups, func(a, b int) bool { return dups[a][0] < dups[b][0] })
+		src := f.src
+		for j := len(dups) - 1; j >= 0; j-- {
+			src = append(src[:dups[j][0]:dups[j][0]], src[dups[j][1]:]...)
+		}
+		if f.src, err = format.Source(src); err != nil {
+			return fmt.Errorf("%s: %v", f.filename, err)
+		}
+	}
+	return nil
+}
+
+// unusedImports returns the offsets in src of the imports of file that
+// only the removed declarations use, and that would be left unused. The
+// whole import declaration is removed when none of its imports remain.
+func unusedImports(fset *token.FileSet, file *ast.File, src []byte, removed map[ast.Decl]bool, dir string) [][2]int {
+	used, usedByRemoved := make(map[string]bool), make(map[string]bool)
+	for _, decl := range file.Decls {
+		names := used
+		if removed[decl] {
+			names = usedByRemoved
+		}
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if x, ok := sel.X.(*ast.Ident); ok {
+					names[x.Name] = true
+				}
+			}
+			return true
+		})
+	}
+	orphans := false
+	for name := range usedByRemoved {
+		orphans = orphans || !used[name]
+	}
+	if !orphans {
+		return nil
+	}
+
+	var unnamed []string
+	for _, imp := range file.Imports {
+		if path, err := strconv.Unquote(imp.Path.Value); err == nil && imp.Name == nil {
+			unnamed = append(unnamed, path)
+		}
+	}
+	pkgNames := packageNames(dir, unnamed)
+
+	var offsets [][2]int
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		var specs [][2]int
+		for _, spec := range gen.Specs {
+			imp := spec.(*ast.ImportSpec)
+			path, _ := strconv.Unquote(imp.Path.Value)
+			name := pkgNames[path]
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			if !usedByRemoved[name] || used[name] {
+				continue
+			}
+			// the lines of the import, along with its comment
+			start, end := fset.Position(imp.Pos()).Offset, fset.Position(imp.End()).Offset
+			for start > 0 && src[start-1] != '\n' {
+				start--
+			}
+			if i := bytes.IndexByte(src[end:], '\n'); i >= 0 {
+				end += i + 1
+			} else {
+				end = len(src)
+			}
+			specs = append(specs, [2]int{start, end})
+		}
+		if len(specs) > 0 && len(specs) == len(gen.Specs) {
+			specs = [][2]int{{fset.Position(gen.Pos()).Offset, fset.Position(gen.End()).Offset}}
+		}
+		offsets = append(offsets, specs...)
+	}
+	return offsets
+}
+
+// declName names the declaration, with methods named after their receiver
+// type. It's empty for the declarations that don't declare one name, like
+// imports.
+func declName(decl ast.Decl) string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			return d.Name.Name
+		}
+		recv := d.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		if id, ok := recv.(*ast.Ident); ok {
+			return id.Name + "." + d.Name.Name
+		}
+	case *ast.GenDecl:
+		if d.Tok == token.IMPORT || len(d.Specs) != 1 {
+			return ""
+		}
+		switch s := d.Specs[0].(type) {
+		case *ast.TypeSpec:
+			return s.Name.Name
+		case *ast.ValueSpec:
+			if len(s.Names) == 1 {
+				return s.Names[0].Name
+			}
+		}
+	}
+	return ""
+}
+
+func declDoc(decl ast.Decl) *ast.CommentGroup {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		return d.Doc
+	case *ast.GenDecl:
+		return d.Doc
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+func TestGenWritesManifestStructures(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	manifest := `package: foo
+structures:
+  - kind: heap
+    key: int
+    output: heap.go
+    options:
+      tests: true
+  - kind: sorted-map
+    key: string
+    val: '[]byte'
+    output: maps/smap.go
+`
+	filename := filepath.Join(dir, "datagen.yaml")
+	if err := ioutil.WriteFile(filename, []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{heap(), sortedMap(), genCommand(), verifyCommand()}
+	if err := app.Run([]string{"datagen", "gen", "-f", filename}); err != nil {
+		t.Fatal(err)
+	}
+	headers := map[string]string{
+		"heap.go":      "//\tdatagen gen -f datagen.yaml\n",
+		"heap_test.go": "//\tdatagen gen -f datagen.yaml\n",
+		"maps/smap.go": "//\tdatagen gen -f ../datagen.yaml\n",
+	}
+	for name, header := range headers {
+		src, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(src), header) {
+			t.Errorf("%s: want the manifest in its header, got:\n%s", name, src)
+		}
+	}
+	if err := app.Run([]string{"datagen", "verify", dir}); err != nil {
+		t.Errorf("want the generated files to be up to date, got %v", err)
+	}
+}
+
+func TestGenRejectsInvalidManifests(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := map[string]string{
+		"datagen.yaml": "structures:\n  - kind: tree\n    key: int\n    output: a.go\n",
+		"unknown.yaml": "structures:\n  - kind: heap\n    key: int\n    ouput: a.go\n",
+		"outputs.json": `{"structures": [{"kind": "heap", "key": "int", "output": "a.go"}, {"kind": "queue", "key": "int", "output": "a.go"}]}`,
+		"val.json":     `{"structures": [{"kind": "heap", "key": "int", "val": "int", "output": "a.go"}]}`,
+		"option.yml":   "structures:\n  - kind: heap\n    key: int\n    output: a.go\n    options: {o: b.go}\n",
+	}
+	app := cli.NewApp()
+	app.Commands = []cli.Command{heap(), queue(), genCommand()}
+	for name, manifest := range tests {
+		filename := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(filename, []byte(manifest), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.Run([]string{"datagen", "gen", "-f", filename}); err == nil {
+			t.Errorf("%s: want an error", name)
+		}
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "*.go")); len(matches) > 0 {
+		t.Errorf("want nothing written, got %v", matches)
+	}
+}
+
+func TestDedupeDeclsKeepsTheFirst(t *testing.T) {
+	shared := "// helper is shared.\nfunc helper() int { return 1 }\n"
+	files := []genFile{
+		{filename: "/foo/a.go", src: []byte("package foo\n\n" + shared + "\ntype A struct{}\n")},
+		{filename: "/foo/b.go", src: []byte("package foo\n\ntype B struct{}\n\n" + shared)},
+		// another package
+		{filename: "/bar/c.go", src: []byte("package bar\n\n" + shared)},
+	}
+	if err := dedupeDecls(files); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"package foo\n\n" + shared + "\ntype A struct{}\n",
+		"package foo\n\ntype B struct{}\n",
+		"package bar\n\n" + shared,
+	}
+	for i, f := range files {
+		if string(f.src) != want[i] {
+			t.Errorf("%s: want:\n%s\ngot:\n%s", f.filename, want[i], f.src)
+		}
+	}
+
+	files = []genFile{
+		{filename: "/foo/a.go", src: []byte("package foo\n\nfunc helper() int { return 1 }\n")},
+		{filename: "/foo/b.go", src: []byte("package foo\n\nfunc helper() int { return 2 }\n")},
+	}
+	err := dedupeDecls(files)
+	if err == nil || !strings.Contains(err.Error(), "helper is declared differently by a.go and b.go") {
+		t.Errorf("want the different helpers to be reported, got %v", err)
+	}
+}
+
+func TestDedupeDeclsRemovesTheirImports(t *testing.T) {
+	// a helper that datastructures of a manifest share, which only it uses
+	// an import for
+	shared := "// helper is shared.\nfunc helper(a, b string) int { return strings.Compare(a, b) }\n"
+	files := []genFile{
+		{filename: "/foo/a.go", src: []byte("package foo\n\nimport \"strings\"\n\n" + shared)},
+		{filename: "/foo/b.go", src: []byte("package foo\n\nimport (\n\t\"sort\"\n\t\"strings\"\n)\n\ntype B []string\n\nfunc (b B) Sort() { sort.Strings(b) }\n\n" + shared)},
+		{filename: "/foo/c.go", src: []byte("package foo\n\nimport \"strings\"\n\n" + shared + "\nfunc upper(s string) string { return strings.ToUpper(s) }\n")},
+		{filename: "/foo/d.go", src: []byte("package foo\n\nimport \"strings\"\n\ntype D struct{}\n\n" + shared)},
+	}
+	if err := dedupeDecls(files); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"package foo\n\nimport \"strings\"\n\n" + shared,
+		"package foo\n\nimport (\n\t\"sort\"\n)\n\ntype B []string\n\nfunc (b B) Sort() { sort.Strings(b) }\n",
+		"package foo\n\nimport \"strings\"\n\nfunc upper(s string) string { return strings.ToUpper(s) }\n",
+		"package foo\n\ntype D struct{}\n",
+	}
+	for i, f := range files {
+		if string(f.src) != want[i] {
+			t.Errorf("%s: want:\n%s\ngot:\n%s", f.filename, want[i], f.src)
+		}
+	}
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// genericImportPath is where the generic libraries are found.
+const genericImportPath = "github.com/aybabtme/datagen/generic"
+
+var genericFlag = cli.BoolFlag{
+	Name:  "generic",
+	Usage: "emit a thin instantiation of the generic library instead of a full implementation (Go 1.22+)",
+}
+
+// genericLibrary describes how one of the templates is turned into a
+// generic library, so that the generic libraries are the same code as
+// the generated datastructures.
+type genericLibrary struct {
+	src    string
+	idents map[string]string
+	// typeParams of the generic types, as named once rewritten.
+	typeParams map[string][]string
+	// fields added to the datastructure, to hold its comparison function.
+	fields map[string]string
+	// funcs replaced by generic versions, written in terms of the
+	// template's placeholders.
+	funcs map[string]string
+	// vars removed, since package variables can't be generic.
+	vars []string
+	// iterSrc is the template of the iterators, generated in a file of
+	// their own since they need Go 1.23.
+	iterSrc string
+}
+
+var genericLibraries = map[string]genericLibrary{
+	"heap": {
+		src:        heapSrc,
+		iterSrc:    heapIterSrc,
+		idents:     map[string]string{"KType": "K"},
+		typeParams: map[string][]string{"Heap": {"K"}},
+		fields:     map[string]string{"Heap": "compareFunc func(a, b KType) int"},
+		funcs: map[string]string{
+			"compare": `func (h Heap) compare(a, b KType) int { return h.compareFunc(a, b) }`,
+			"NewHeap": `
+// New creates a heap of ordered keys, optionaly with keys already
+// populating it. The complexity is O(n) where n = len(keys).
+func New[KType cmp.Ordered](keys ...KType) *Heap {
+	return NewFunc(cmp.Compare[KType], keys...)
+}
+
+// NewFunc creates a heap where keys are ordered by compare, optionaly with
+// keys already populating it. The complexity is O(n) where n = len(keys).
+func NewFunc[KType any](compare func(a, b KType) int, keys ...KType) *Heap {
+	h := &Heap{
+		n:           len(keys),
+		pq:          append(make([]KType, 1), keys...),
+		compareFunc: compare,
+	}
+	h.Fix()
+	return h
+}`,
+		},
+	},
+	"queue": {
+		src:     queueSrc,
+		iterSrc: queueIterSrc,
+		idents: map[string]string{
+			"KType":    "K",
+			"NewQueue": "New",
+			"nilKType": "*new(K)",
+		},
+		typeParams: map[string][]string{
+			"Queue":    {"K"},
+			"NewQueue": {"K"},
+		},
+		vars: []string{"nilKType"},
+	},
+	"smap": {
+		src:     redblackbstMapSrc,
+		iterSrc: redblackbstMapIterSrc,
+		idents:  map[string]string{"KType": "K", "VType": "V", "RedBlack": "Map", "RedBlackIterator": "MapIterator"},
+		typeParams: map[string][]string{
+			"RedBlack":         {"K", "V"},
+			"RedBlackIterator": {"K", "V"},
+			"mapnode":          {"K", "V"},
+		},
+		fields: map[string]string{"RedBlack": "compareFunc func(a, b KType) int"},
+		funcs: map[string]string{
+			"compare": `func (r RedBlack) compare(a, b KType) int { return r.compareFunc(a, b) }`,
+			"NewRedBlack": `
+// NewMap creates a sorted map of ordered keys.
+func NewMap[KType cmp.Ordered, VType any]() *RedBlack {
+	return NewMapFunc[KType, VType](cmp.Compare[KType])
+}
+
+// NewMapFunc creates a sorted map where keys are ordered by compare.
+func NewMapFunc[KType, VType any](compare func(a, b KType) int) *RedBlack {
+	return &RedBlack{compareFunc: compare}
+}`,
+		},
+	},
+	"sset": {
+		src:     redblackbstSetSrc,
+		iterSrc: redblackbstSetIterSrc,
+		idents:  map[string]string{"KType": "K", "RedBlack": "Set"},
+		typeParams: map[string][]string{
+			"RedBlack": {"K"},
+			"treenode": {"K"},
+		},
+		fields: map[string]string{"RedBlack": "compareFunc func(a, b KType) int"},
+		funcs: map[string]string{
+			"compare": `func (r RedBlack) compare(a, b KType) int { return r.compareFunc(a, b) }`,
+			"NewRedBlack": `
+// NewSet creates a sorted set of ordered keys.
+func NewSet[KType cmp.Ordered]() *RedBlack {
+	return NewSetFunc[KType](cmp.Compare[KType])
+}
+
+// NewSetFunc creates a sorted set where keys are ordered by compare.
+func NewSetFunc[KType any](compare func(a, b KType) int) *RedBlack {
+	return &RedBlack{compareFunc: compare}
+}`,
+		},
+	},
+}
+
+func genericLibraryCommand() cli.Command {
+	kindFlag := cli.StringFlag{
+		Name:  "kind",
+		Usage: "datastructure to turn into a generic library: heap, queue, smap or sset",
+	}
+	iteratorsFlag := cli.BoolFlag{
+		Name:  "iterators",
+		Usage: "create the iterators of the library, built from Go 1.23 on, instead of the library",
+	}
+
+	return cli.Command{
+		Name:   "generic-library",
+		Usage:  "Create the generic libraries from the templates.",
+		Hidden: true,
+		Flags:  []cli.Flag{kindFlag, iteratorsFlag, outputFlag, packageFlag},
+		Action: func(ctx *cli.Context) error {
+			out, err := newOutput(ctx)
+			if err != nil {
+				return err
+			}
+
+			kind := valOrDefault(ctx, kindFlag)
+			lib, ok := genericLibraries[kind]
+			if !ok {
+				return fmt.Errorf("no generic library for %q", kind)
+			}
+
+			if ctx.Bool("iterators") {
+				return writeGenericIterators(out, kind, lib)
+			}
+
+			tmpl := newTemplate(lib.src)
+			if err := tmpl.replaceComment("// GENERATED CODE!!!", ""); err != nil {
+				return err
+			}
+			for _, name := range lib.vars {
+				if err := tmpl.removeVar(name); err != nil {
+					return err
+				}
+			}
+			for typeName, field := range lib.fields {
+				if err := tmpl.addField(typeName, field); err != nil {
+					return err
+				}
+			}
+			for name, fn := range lib.funcs {
+				if err := tmpl.replaceFunc(name, fn); err != nil {
+					return err
+				}
+			}
+			if len(lib.funcs) > 0 {
+				if err := tmpl.addImport("", "cmp"); err != nil {
+					return err
+				}
+			}
+			for name, params := range lib.typeParams {
+				tmpl.generic(name, params...)
+			}
+
+			src, err := tmpl.rewrite(out.pkgName, lib.idents)
+			if err != nil {
+				return err
+			}
+			src, err = finish(out, src)
+			if err != nil {
+				return fmt.Errorf("generic %s: %v", kind, err)
+			}
+			return out.write(src)
+		},
+	}
+}
+
+// writeGenericIterators writes the iterators of the generic library lib,
+// in a file built from Go 1.23 on. The notes of the template about when
+// they're generated are left out, since the library always has them.
+func writeGenericIterators(out output, kind string, lib genericLibrary) error {
+	tmpl := newTemplate("//go:build " + iteratorsGoVersion + "\n\npackage " + out.pkgName + "\n")
+	if err := tmpl.appendTemplate(lib.iterSrc); err != nil {
+		return err
+	}
+	for name, params := range lib.typeParams {
+		tmpl.generic(name, params...)
+	}
+	src, err := tmpl.rewrite(out.pkgName, lib.idents)
+	if err != nil {
+		return err
+	}
+	src, err = finish(out, src)
+	if err != nil {
+		return fmt.Errorf("generic %s iterators: %v", kind, err)
+	}
+	return out.write(src)
+}
+
+// genericInstance is the source of a thin instantiation of a generic
+// library, emitted in --generic mode.
+type genericInstance struct {
+	pkgName string
+	imports []importSpec
+	decls   bytes.Buffer
+}
+
+func (g *genericInstance) addImport(name, path string) {
+	for _, imp := range g.imports {
+		if imp.path == path {
+			return
+		}
+	}
+	g.imports = append(g.imports, importSpec{name: name, path: path})
+}
+
+func (g *genericInstance) addImports(types ...*goType) {
+	for _, typ := range types {
+		for _, imp := range typ.imports {
+			g.addImport(imp.name, imp.path)
+		}
+	}
+}
+
+func (g *genericInstance) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.decls, format, args...)
+}
+
+func (g *genericInstance) source() []byte {
+	src := bytes.NewBuffer(nil)
+	fmt.Fprintf(src, "package %s\n\n", g.pkgName)
+
+	// standard library first, like goimports does
+	std := func(path string) bool { return !strings.Contains(strings.Split(path, "/")[0], ".") }
+	sort.Slice(g.imports, func(i, j int) bool {
+		a, b := g.imports[i].path, g.imports[j].path
+		if std(a) != std(b) {
+			return std(a)
+		}
+		return a < b
+	})
+	src.WriteString("import (\n")
+	for i, imp := range g.imports {
+		if i > 0 && std(g.imports[i-1].path) != std(imp.path) {
+			src.WriteString("\n")
+		}
+		fmt.Fprintf(src, "\t%s %q\n", imp.name, imp.path)
+	}
+	src.WriteString(")\n\n")
+
+	g.decls.WriteTo(src)
+	return src.Bytes()
+}
+
+// genericCompare returns the function ordering the keys, to be given to
+// the generic libraries. It's empty when the keys are cmp.Ordered and need
+// no such function, unless their order is reversed.
+func genericCompare(order *keyOrder, g *genericInstance) string {
+	compare := genericKeyCompare(order, g)
+	if !order.reverse {
+		return compare
+	}
+	ktype := order.ktype.name
+	if compare == "" {
+		g.addImport("", "cmp")
+		compare = fmt.Sprintf("cmp.Compare[%s]", ktype)
+	}
+	// the keys are reversed by swapping the parameters of the function
+	if params := fmt.Sprintf("func(a, b %s)", ktype); strings.HasPrefix(compare, params) {
+		return fmt.Sprintf("func(b, a %s)", ktype) + strings.TrimPrefix(compare, params)
+	}
+	return fmt.Sprintf("func(a, b %s) int { return %s(b, a) }", ktype, compare)
+}
+
+// genericKeyCompare returns the function ordering the keys in increasing
+// order, or nothing when they're cmp.Ordered.
+func genericKeyCompare(order *keyOrder, g *genericInstance) string {
+	ktype := order.ktype
+	for _, imp := range order.imports {
+		g.addImport(imp.name, imp.path)
+	}
+	switch {
+	case order.cmp != "":
+		return order.cmp
+	case order.less != "":
+		return fmt.Sprintf(`func(a, b %s) int {
+	switch {
+	case %[2]s(a, b):
+		return -1
+	case %[2]s(b, a):
+		return 1
+	}
+	return 0
+}`, ktype.name, order.less)
+	case order.field != nil:
+		for _, imp := range order.field.imports {
+			g.addImport(imp.name, imp.path)
+		}
+		return fmt.Sprintf(`func(a, b %s) int {
+	x, y := a.%[2]s, b.%[2]s
+	%[3]s
+	return 0
+}`, ktype.name, order.field.path, order.field.compare)
+	}
+	switch {
+	// the basic types are ordered like the templates order them
+	case isOrderedBasic(ktype.name) || ktype.name == "float32" || ktype.name == "float64":
+		return ""
+	case ktype.name == "[]byte":
+		g.addImport("", "bytes")
+		return "bytes.Compare"
+	case strings.HasPrefix(ktype.name, "[]"):
+		return fmt.Sprintf("func(a, b %s) int { return len(a) - len(b) }", ktype.name)
+	}
+	return fmt.Sprintf("(%s).Compare", ktype.name)
+}
+
+func genericHeap(pkgName string, n names, order *keyOrder) []byte {
+	ktype := order.ktype
+	g := &genericInstance{pkgName: pkgName}
+	g.addImport("", genericImportPath+"/heap")
+	g.addImports(ktype)
+
+	g.printf("// %s is a heap of %s.\ntype %[1]s = heap.Heap[%[2]s]\n\n", n.typ, ktype.name)
+	g.printf("// %s creates a heap, optionaly with keys already populating\n", n.constructor)
+	g.printf("// it. The complexity is O(n) where n = len(keys).\n")
+	if compare := genericCompare(order, g); compare == "" {
+		g.printf("func %s(keys ...%s) *%s { return heap.New(keys...) }\n", n.constructor, ktype.name, n.typ)
+	} else {
+		g.printf("func %s(keys ...%s) *%s { return heap.NewFunc(%s, keys...) }\n", n.constructor, ktype.name, n.typ, compare)
+	}
+	return g.source()
+}
+
+func genericQueue(pkgName string, n names, ktype *goType) []byte {
+	g := &genericInstance{pkgName: pkgName}
+	g.addImport("", genericImportPath+"/queue")
+	g.addImports(ktype)
+
+	g.printf("// %s is a queue of %s.\ntype %[1]s = queue.Queue[%[2]s]\n\n", n.typ, ktype.name)
+	g.printf("// %s constructs and returns a new %s with an initial capacity.\n", n.constructor, n.typ)
+	g.printf("func %s(capacity int) *%s { return queue.New[%s](capacity) }\n", n.constructor, n.typ, ktype.name)
+	return g.source()
+}
+
+func genericSortedMap(pkgName string, n names, order *keyOrder, vtype *goType) []byte {
+	ktype := order.ktype
+	g := &genericInstance{pkgName: pkgName}
+	g.addImport("", genericImportPath+"/redblackbst")
+	g.addImports(ktype, vtype)
+
+	if order.composite != nil {
+		g.printf("%s\n", order.composite.decl(ktype.name))
+	}
+	g.printf("// %s is a sorted map of %s to %s.\n", n.typ, ktype.name, vtype.name)
+	g.printf("type %s = redblackbst.Map[%s, %s]\n\n", n.typ, ktype.name, vtype.name)
+	g.printf("// %sIterator is a cursor over the keys/values of a %[1]s.\n", n.typ)
+	g.printf("type %sIterator = redblackbst.MapIterator[%s, %s]\n\n", n.typ, ktype.name, vtype.name)
+	g.printf("// %s creates a sorted map.\n", n.constructor)
+	if compare := genericCompare(order, g); compare == "" {
+		g.printf("func %s() *%s { return redblackbst.NewMap[%s, %s]() }\n", n.constructor, n.typ, ktype.name, vtype.name)
+	} else {
+		g.printf("func %s() *%s { return redblackbst.NewMapFunc[%s, %s](%s) }\n", n.constructor, n.typ, ktype.name, vtype.name, compare)
+	}
+	return g.source()
+}
+
+func genericSortedSet(pkgName string, n names, order *keyOrder) []byte {
+	ktype := order.ktype
+	g := &genericInstance{pkgName: pkgName}
+	g.addImport("", genericImportPath+"/redblackbst")
+	g.addImports(ktype)
+
+	if order.composite != nil {
+		g.printf("%s\n", order.composite.decl(ktype.name))
+	}
+	g.printf("// %s is a sorted set of %s.\ntype %[1]s = redblackbst.Set[%[2]s]\n\n", n.typ, ktype.name)
+	g.printf("// %s creates a sorted set.\n", n.constructor)
+	if compare := genericCompare(order, g); compare == "" {
+		g.printf("func %s() *%s { return redblackbst.NewSet[%s]() }\n", n.constructor, n.typ, ktype.name)
+	} else {
+		g.printf("func %s() *%s { return redblackbst.NewSetFunc[%s](%s) }\n", n.constructor, n.typ, ktype.name, compare)
+	}
+	return g.source()
+}
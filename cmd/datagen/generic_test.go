@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestGenericInstancesCompile(t *testing.T) {
+	// inside of the module, for the generic libraries to be found
+	dir, err := ioutil.TempDir(".", "_generic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := output{dir: dir, pkgName: "foo"}
+	vtype := &goType{name: "string"}
+	var srcs [][]byte
+	for _, typ := range []string{"int", "byte", "rune", "float32", "string", "[]byte", "[]string", "time.Time"} {
+		ktype, err := parseType(typ, out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := typeIdent(ktype.expr)
+		order := &keyOrder{ktype: ktype}
+		srcs = append(srcs,
+			genericHeap(out.pkgName, names{typ: name + "Heap", constructor: "New" + name + "Heap"}, order),
+			genericQueue(out.pkgName, names{typ: name + "Queue", constructor: "New" + name + "Queue"}, ktype),
+			genericSortedMap(out.pkgName, names{typ: "Sorted" + name + "ToStringMap", constructor: "NewSorted" + name + "ToStringMap"}, order, vtype),
+			genericSortedSet(out.pkgName, names{typ: "Sorted" + name + "Set", constructor: "newSorted" + name + "Set"}, order),
+		)
+	}
+
+	for _, src := range srcs {
+		if _, err := finish(out, src); err != nil {
+			t.Errorf("%v\n%s", err, src)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+// generatedCodeHeader is the comment written at the top of the generated
+// files, in the form recognized by the Go tools. It records the command
+// that generated the file, so that regenerating it anywhere gives the same
+// file.
+func generatedCodeHeader(command string) string {
+	return fmt.Sprintf(`// Code generated by datagen %s. DO NOT EDIT.
+//
+// The command that generated this file was:
+//
+//	%s
+
+`, version, command)
+}
+
+// commandLine is the canonical form of the command being run: its flags
+// are given in the order the command declares them, by their first name,
+// and the output file is relative to the output directory. The package is
+// given when it's set, or when it isn't the one found in the output
+// directory, dirPkg.
+func commandLine(ctx *cli.Context, out output, dirPkg string) string {
+	args := []string{"datagen", ctx.Command.Name}
+	for _, flag := range ctx.Command.Flags {
+		name := strings.TrimSpace(strings.Split(flag.GetName(), ",")[0])
+		if name == "package" {
+			if ctx.IsSet(name) || out.pkgName != dirPkg {
+				args = append(args, "-package", out.pkgName)
+			}
+			continue
+		}
+		if !ctx.IsSet(name) {
+			continue
+		}
+		switch flag.(type) {
+		case cli.BoolFlag:
+			if ctx.Bool(name) {
+				args = append(args, "-"+name)
+			}
+		case cli.Float64Flag:
+			args = append(args, "-"+name, strconv.FormatFloat(ctx.Float64(name), 'g', -1, 64))
+		case cli.StringFlag:
+			value := ctx.String(name)
+			if name == "output" {
+				name, value = "o", filepath.Base(out.filename)
+			}
+			args = append(args, "-"+name, shellQuote(value))
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s if a shell would otherwise not read it back as is,
+// as happens with types like []byte or *Item.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_-+./:=,@%") == "" {
+		return s
+	}
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// headerCommand returns the arguments of the datagen command recorded in
+// the header of a generated file, without the program's name. The headers
+// written by older versions of datagen, after the package clause and with
+// the command as it was invoked, are read too. It's false for the files
+// that datagen didn't generate.
+func headerCommand(src []byte) ([]string, bool, error) {
+	generated := false
+	lines := strings.Split(string(src), "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "package "):
+			continue
+		case !strings.HasPrefix(line, "//"):
+			return nil, false, nil
+		case generatedByDatagen.MatchString(line) || line == "// GENERATED CODE, DO NOT EDIT":
+			generated = true
+		case generated && (line == "// The command that generated this file was:" || line == "// The command that generated this was:"):
+			for _, next := range lines[i+1:] {
+				if strings.HasPrefix(next, "//\t") {
+					args, err := splitWords(strings.TrimPrefix(next, "//\t"))
+					if err != nil || len(args) < 2 {
+						return nil, true, fmt.Errorf("can't read the command in the header: %q", next)
+					}
+					return args[1:], true, nil
+				}
+				if strings.TrimSpace(next) != "//" {
+					break
+				}
+			}
+			return nil, true, errors.New("no command found in the header")
+		}
+	}
+	return nil, false, nil
+}
+
+var generatedByDatagen = regexp.MustCompile(`^// Code generated by datagen .* DO NOT EDIT\.$`)
+
+// splitWords splits a command line into its arguments, like a shell does
+// with quotes and backslashes.
+func splitWords(line string) ([]string, error) {
+	var (
+		args  []string
+		word  []rune
+		quote rune
+		inArg bool
+		esc   bool
+	)
+	for _, r := range line {
+		switch {
+		case esc:
+			word, esc = append(word, r), false
+		case quote == '\'':
+			if r == quote {
+				quote = 0
+			} else {
+				word = append(word, r)
+			}
+		case r == '\\':
+			esc, inArg = true, true
+		case quote == '"':
+			if r == quote {
+				quote = 0
+			} else {
+				word = append(word, r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args, word, inArg = append(args, string(word)), nil, false
+			}
+		default:
+			word, inArg = append(word, r), true
+		}
+	}
+	if quote != 0 || esc {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	if inArg {
+		args = append(args, string(word))
+	}
+	return args, nil
+}
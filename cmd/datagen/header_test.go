@@ -0,0 +1,56 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+func TestHeaderIsReproducible(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{sortedMap()}
+
+	// the same command, spelled in different ways and run from different
+	// places
+	invocations := [][]string{
+		{"/tmp/go-build123/exe/datagen", "smap", "-key", "[]byte", "-val", "*time.Time", "-package", "foo", "-o", filepath.Join(dir, "a", "map.go")},
+		{"datagen", "sorted-map", "--output=" + filepath.Join(dir, "b", "map.go"), "-package=foo", "-val=*time.Time", "-key=[]byte"},
+	}
+	var srcs [][]byte
+	for i, args := range invocations {
+		sub := filepath.Join(dir, string(rune('a'+i)))
+		if err := os.Mkdir(sub, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.Run(args); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		src, err := ioutil.ReadFile(filepath.Join(sub, "map.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		srcs = append(srcs, src)
+	}
+	if string(srcs[0]) != string(srcs[1]) {
+		t.Fatalf("the same command generated different files:\n%s\n\n%s", srcs[0], srcs[1])
+	}
+
+	generated := regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+	if loc := generated.FindIndex(srcs[0]); loc == nil || loc[0] != 0 {
+		t.Errorf("want the file to start with the standard generated code comment:\n%s", srcs[0])
+	}
+	want := "//\tdatagen sorted-map -key '[]byte' -val '*time.Time' -o map.go -package foo\n"
+	if !regexp.MustCompile(regexp.QuoteMeta(want)).Match(srcs[0]) {
+		t.Errorf("want the canonical command line %q in:\n%s", want, srcs[0])
+	}
+}
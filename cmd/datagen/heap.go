@@ -1,12 +1,8 @@
 package main
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
-	"log"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"gopkg.in/urfave/cli.v1"
 )
@@ -24,114 +20,94 @@ func heap() cli.Command {
 		Usage:     "Create a heap (priority queue) customized for your types.",
 		Description: `Create a heap customized for your types. The implementation
 has good performance and is well tested, with 100% test coverage.
-(the tests are not generated with the custom type)`,
-		Flags: []cli.Flag{keyTypeFlag},
-		Action: func(ctx *cli.Context) {
-			ktype := valOrDefault(ctx, keyTypeFlag)
+The tests can be generated for your type with -tests, and benchmarks
+comparing it to the standard library with -bench.
+The heap pops its largest key first, or its smallest with -order asc.`,
+		Flags: []cli.Flag{keyTypeFlag, outputFlag, packageFlag, typeFlag, constructorFlag, unexportedFlag, genericFlag, epsilonFlag, cmpFlag, lessFlag, keyFieldFlag, orderFlag, testsFlag, benchFlag, keyGenFlag},
+		Action: func(ctx *cli.Context) error {
+			out, err := newOutput(ctx)
+			if err != nil {
+				return err
+			}
 
-			kname := ktype
-			if len(kname) > 1 && []byte(kname)[0] == '*' {
-				kname = kname[1:]
+			ktype, err := parseType(valOrDefault(ctx, keyTypeFlag), out)
+			if err != nil {
+				return err
 			}
-			if len(kname) > 2 && kname[:2] == "[]" {
-				kname = strings.Title(kname[2:]) + "s"
+			order, err := newKeyOrder(ctx, out, ktype)
+			if err != nil {
+				return err
+			}
+			// the heaps pop their largest key first, unless they're
+			// min-heaps
+			if order.reverse, err = reversedOrder(ctx, "desc"); err != nil {
+				return err
 			}
 
-			typeName := fmt.Sprintf("%sHeap", strings.Title(kname))
-
-			cwd, _ := os.Getwd()
-			pkgname := fmt.Sprintf("package %s", filepath.Base(cwd))
+			kname := typeIdent(ktype.expr)
+			derived := kname + "Heap"
+			if order.reverse {
+				derived = kname + "MinHeap"
+			}
+			names, err := chooseNames(ctx, derived, kname)
+			if err != nil {
+				return err
+			}
 
-			src := []byte(heapSrc)
-			src = bytes.Replace(src, []byte("package heap"), []byte(pkgname), 1)
+			if ctx.Bool("generic") {
+				if ctx.Bool("tests") || ctx.Bool("bench") {
+					return errors.New("-tests and -bench can't be used with -generic, the generic libraries have their own tests")
+				}
+				src, err := finish(out, genericHeap(out.pkgName, names, order))
+				if err != nil {
+					return fmt.Errorf("heap of %s: %v", ktype.name, err)
+				}
+				return out.write(src)
+			}
 
-			src = bytes.Replace(src, []byte("// GENERATED CODE!!!"), []byte(generatedCodeComment()), 1)
+			tmpl := newTemplate(heapSrc)
+			if err := tmpl.replaceComment("// GENERATED CODE!!!", ""); err != nil {
+				return err
+			}
+			if out.iterators() {
+				if err := tmpl.appendTemplate(heapIterSrc); err != nil {
+					return err
+				}
+			}
+			if err := tmpl.addImports(ktype); err != nil {
+				return err
+			}
+			if err := replaceCompareFunc("h Heap", order, tmpl); err != nil {
+				return err
+			}
+			idents := map[string]string{
+				"KType":   ktype.name,
+				"Heap":    names.typ,
+				"NewHeap": names.constructor,
+			}
+			src, err := tmpl.rewrite(out.pkgName, idents)
+			if err != nil {
+				return err
+			}
+			src, err = finish(out, src)
+			if err != nil {
+				return fmt.Errorf("heap of %s: %v", ktype.name, err)
+			}
 
-			// need to replace Compare before replacing KType
-			src = replaceHeapCompareFunc(ktype, src)
-			src = bytes.Replace(src, []byte("KType"), []byte(ktype), -1)
-			src = bytes.Replace(src, []byte("Heap"), []byte(typeName), -1)
+			suites, err := generateSuites(ctx, out, idents, ktype, nil, order.reverse, heapTests, heapBenchmarks)
+			if err != nil {
+				return fmt.Errorf("heap of %s: %v", ktype.name, err)
+			}
 
-			fmt.Println(string(src))
+			if err := out.write(src); err != nil {
+				return err
+			}
+			for _, suite := range suites {
+				if err := suite.write(); err != nil {
+					return fmt.Errorf("heap of %s: %v", ktype.name, err)
+				}
+			}
+			return nil
 		},
 	}
 }
-
-func replaceHeapCompareFunc(ktype string, src []byte) []byte {
-	var tmpl string
-	orig := "func (h Heap) compare(a, b KType) int { return a.Compare(b) }"
-
-	switch ktype {
-
-	case "int", "int8", "int16", "int32", "int64",
-		"uint", "uint8", "uint16", "uint32", "uint64":
-		tmpl = "func (h Heap) compare(a, b KType) int { return int(a) - int(b) }"
-
-	case "float32", "float64":
-		tmpl = `
-func (h Heap) compare(a, b KType) int {
-	const e = 0.00000001
-
-    diff := (a-b)/a
-    if diff < -e {
-        return -1
-    } else if diff > e {
-        return 1
-    }
-    return 0
-}`
-
-	case "string":
-		tmpl = `
-func (h Heap) compare(a, b KType) int {
-    if a < b {
-        return -1
-    }
-    if a > b {
-        return 1
-    }
-    return 0
-}`
-
-	case "[]byte":
-		log.Printf("WARNING: using []byte as keys can lead to undefined behavior if the []byte are modified after insertion!!!")
-		tmpl = `import "bytes"
-
-// WARNING: using []byte as keys can lead to undefined behavior if the
-// []byte are modified after insertion!!!
-func (h Heap) compare(a, b KType) int { return bytes.Compare(a, b) }`
-
-	default:
-
-		// if storing slices, use `len()` for comparison
-		if len(ktype) > 2 && ktype[:2] == "[]" {
-			log.Printf("%s: order will be determined based on value of len(%s)", ktype, ktype)
-			tmpl = fmt.Sprintf(
-				"func (h Heap) compare(a, b %s) int { return len(a)-len(b) }",
-				ktype,
-			)
-		} else {
-			l := 0
-			if []rune(ktype)[0] == '*' {
-				l = 1
-			}
-			// otherwise don't change anything by default, let the user
-			// provide a `Compare` func
-			log.Printf("type %q will need to implement a Compare func: %s",
-				ktype,
-				fmt.Sprintf(`
-	func (%[1]s %s) Compare(other %s) int {
-		if %[1]s > other {
-			return 1
-		} else if %[1]s < other {
-			return -1
-		}
-		return 0
-	}`, strings.ToLower(ktype[l:l+1]), ktype, ktype))
-			return src
-		}
-
-	}
-
-	return bytes.Replace(src, []byte(orig), []byte(tmpl), -1)
-}
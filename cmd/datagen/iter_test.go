@@ -0,0 +1,119 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const iteratorsTest = `package iters
+
+import (
+	"io/ioutil"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestIterators(t *testing.T) {
+	names := NewNames()
+	for _, name := range []string{"b", "c", "a"} {
+		names.Put(name, len(name))
+	}
+	var keys []string
+	for k := range names.Backward() {
+		keys = append(keys, k)
+	}
+	if want := []string{"c", "b", "a"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("want %v, got %v", want, keys)
+	}
+
+	set := NewSortedFloat64Set()
+	set.Put(2)
+	set.Put(1)
+	if got, want := slices.Collect(set.All()), []float64{2, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	minHeap := NewIntMinHeap(3, 1, 2)
+	if got, want := slices.Collect(minHeap.Drain()), []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	queue := NewStringQueue(0)
+	queue.Push("a")
+	queue.Push("b")
+	if got, want := slices.Collect(queue.All()), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	src, err := ioutil.ReadFile("min_heap.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "Drain returns the elements of the heap in increasing order") {
+		t.Errorf("want the docs of the min-heap to drain it in increasing order:\n%s", src)
+	}
+}
+`
+
+func TestIterators(t *testing.T) {
+	testGeneratedWith(t, "iters", map[string]string{"go.mod": "module iters\n\ngo 1.23\n"}, [][]string{
+		{"smap", "-key", "string", "-val", "int", "-type", "Names", "-tests", "-o", "names.go"},
+		{"sset", "-key", "float64", "-order", "desc", "-tests", "-o", "floats.go"},
+		{"heap", "-key", "int", "-order", "asc", "-tests", "-o", "min_heap.go"},
+		{"queue", "-key", "string", "-tests", "-o", "queue.go"},
+	}, iteratorsTest)
+}
+
+const noIteratorsTest = `package iters
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestNoIterators(t *testing.T) {
+	for _, name := range []string{"names.go", "names_test.go"} {
+		src, err := ioutil.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(src), "\"iter\"") || strings.Contains(string(src), "All()") {
+			t.Errorf("want no iterators before Go 1.23, got:\n%s", src)
+		}
+	}
+}
+`
+
+func TestIteratorsNeedGo123(t *testing.T) {
+	// inside of this module, whose version of Go is older
+	testGenerated(t, "iters", [][]string{
+		{"smap", "-key", "string", "-val", "int", "-tests", "-o", "names.go"},
+	}, noIteratorsTest)
+}
+
+func TestModuleGoVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pkg := filepath.Join(dir, "a", "b")
+	if err := os.MkdirAll(pkg, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := moduleGoVersion(pkg); got != "" {
+		t.Errorf("want no version outside of a module, got %q", got)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module a\n\ngo 1.23.4\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := output{goVersion: moduleGoVersion(pkg)}
+	if out.goVersion != "go1.23.4" || !out.iterators() {
+		t.Errorf("want iterators for go1.23.4, got %q", out.goVersion)
+	}
+}
@@ -17,10 +17,8 @@
 package main
 
 import (
-	"fmt"
 	"log"
 	"os"
-	"strings"
 
 	"gopkg.in/urfave/cli.v1"
 )
@@ -41,6 +39,10 @@ func main() {
 	app.Commands = append(app.Commands, sortedSet())
 	app.Commands = append(app.Commands, heap())
 	app.Commands = append(app.Commands, queue())
+	app.Commands = append(app.Commands, genericLibraryCommand())
+	app.Commands = append(app.Commands, regenCommand())
+	app.Commands = append(app.Commands, verifyCommand())
+	app.Commands = append(app.Commands, genCommand())
 
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
@@ -59,14 +61,3 @@ func valOrDefault(ctx *cli.Context, f cli.StringFlag) string {
 	}
 	return f.Value
 }
-
-func generatedCodeComment() string {
-	return fmt.Sprintf(`// GENERATED CODE, DO NOT EDIT
-// This code was generated by a tool.
-//
-// 	github.com/aybabtme/datagen
-//
-// The command that generated this was:
-//
-//	%s`, strings.Join(os.Args, " "))
-}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	typeFlag = cli.StringFlag{
+		Name:  "type",
+		Usage: "name of the generated type (default: derived from the key and value types, like IntHeap)",
+	}
+	constructorFlag = cli.StringFlag{
+		Name:  "constructor",
+		Usage: "name of the function creating the generated type (default: New followed by the type, or new for unexported types)",
+	}
+	nodeFlag = cli.StringFlag{
+		Name:  "node",
+		Usage: "name of the type of the nodes of the tree (default: node followed by the key and value types, or by the -type)",
+	}
+	unexportedFlag = cli.BoolFlag{
+		Name:  "unexported",
+		Usage: "unexport the derived names, like intHeap and newIntHeap, for the datastructure to be private to its package",
+	}
+)
+
+// names of the declarations of a generated datastructure.
+type names struct {
+	typ         string
+	constructor string
+	// node is the type of the nodes of the sorted maps and sets.
+	node string
+	// nilValue is the variable holding the nil value of the queues.
+	nilValue string
+	// helpers is what the other unexported declarations are named after,
+	// like the nil value of the queues.
+	helpers string
+}
+
+// chooseNames names a datastructure after the naming flags. Otherwise
+// its type is named typ, and its helpers are named after the key and
+// value types they're derived from, unless the type was named with -type:
+// a package can then hold many datastructures of the same types.
+func chooseNames(ctx *cli.Context, typ, derived string) (names, error) {
+	n := names{typ: typ, helpers: derived}
+	if name := ctx.String("type"); name != "" {
+		if ctx.Bool("unexported") && token.IsExported(name) {
+			return names{}, errors.New("-unexported only changes the derived names, give an unexported -type instead")
+		}
+		n.typ, n.helpers = name, upperFirst(name)
+	} else if ctx.Bool("unexported") {
+		n.typ = lowerFirst(typ)
+	}
+
+	n.constructor = "New" + n.typ
+	if !token.IsExported(n.typ) {
+		n.constructor = "new" + upperFirst(n.typ)
+	}
+	if name := ctx.String("constructor"); name != "" {
+		n.constructor = name
+	}
+	n.nilValue = "nil" + n.helpers
+	n.node = "node" + n.helpers
+	if name := ctx.String("node"); name != "" {
+		n.node = name
+	}
+
+	for flag, name := range map[string]string{"type": n.typ, "constructor": n.constructor, "node": n.node} {
+		if !token.IsIdentifier(name) || name == "_" {
+			return names{}, fmt.Errorf("-%s %q isn't a valid Go identifier", flag, name)
+		}
+	}
+	if n.typ == n.constructor || n.typ == n.node || n.constructor == n.node {
+		return names{}, fmt.Errorf("the type, its constructor and its nodes need different names, got %s, %s and %s", n.typ, n.constructor, n.node)
+	}
+	return n, nil
+}
+
+// avoidDeclared renames the helpers that other files of the output's
+// package already declare, like the nil value of another queue of the same
+// type: they're named after the type of the datastructure instead, which
+// is unique to the package. Regenerating any of the files then gives the
+// same names, and any number of datastructures can share a package.
+func (n *names) avoidDeclared(ctx *cli.Context, out output) error {
+	declared, err := declaredNames(out, n.typ)
+	if err != nil {
+		return err
+	}
+	unique := func(name, prefix string) string {
+		if !declared[name] {
+			return name
+		}
+		name = prefix + upperFirst(n.typ)
+		for i := 2; declared[name]; i++ {
+			name = fmt.Sprintf("%s%s%d", prefix, upperFirst(n.typ), i)
+		}
+		return name
+	}
+	n.nilValue = unique(n.nilValue, "nil")
+	if ctx.String("node") == "" {
+		n.node = unique(n.node, "node")
+	}
+	return nil
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func lowerFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[size:]
+}
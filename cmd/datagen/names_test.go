@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"testing"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+func TestChooseNames(t *testing.T) {
+	tests := []struct {
+		args []string
+		want names
+	}{
+		{
+			want: names{typ: "SortedIntToStringMap", constructor: "NewSortedIntToStringMap", node: "nodeIntToString", nilValue: "nilIntToString", helpers: "IntToString"},
+		},
+		{
+			args: []string{"-unexported"},
+			want: names{typ: "sortedIntToStringMap", constructor: "newSortedIntToStringMap", node: "nodeIntToString", nilValue: "nilIntToString", helpers: "IntToString"},
+		},
+		{
+			args: []string{"-type", "Index"},
+			want: names{typ: "Index", constructor: "NewIndex", node: "nodeIndex", nilValue: "nilIndex", helpers: "Index"},
+		},
+		{
+			args: []string{"-type", "byName", "-unexported"},
+			want: names{typ: "byName", constructor: "newByName", node: "nodeByName", nilValue: "nilByName", helpers: "ByName"},
+		},
+		{
+			args: []string{"-type", "Index", "-constructor", "MakeIndex", "-node", "entry"},
+			want: names{typ: "Index", constructor: "MakeIndex", node: "entry", nilValue: "nilIndex", helpers: "Index"},
+		},
+	}
+	for _, tt := range tests {
+		got, err := chooseNames(namingContext(t, tt.args...), "SortedIntToStringMap", "IntToString")
+		if err != nil {
+			t.Errorf("%v: %v", tt.args, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%v: want %+v, got %+v", tt.args, tt.want, got)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"-type", "Index", "-unexported"},
+		{"-type", "2fast"},
+		{"-constructor", "func"},
+		{"-type", "Index", "-node", "Index"},
+	} {
+		if _, err := chooseNames(namingContext(t, args...), "SortedIntToStringMap", "IntToString"); err == nil {
+			t.Errorf("%v: want an error", args)
+		}
+	}
+}
+
+func namingContext(t *testing.T, args ...string) *cli.Context {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	for _, f := range []cli.Flag{typeFlag, constructorFlag, nodeFlag, unexportedFlag} {
+		f.Apply(set)
+	}
+	if err := set.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cli.NewContext(nil, set, nil)
+}
+
+func TestManyInstancesInOnePackage(t *testing.T) {
+	testGenerated(t, "instances", [][]string{
+		{"smap", "-key", "int", "-val", "string", "-type", "ByID", "-tests", "-o", "by_id.go"},
+		{"smap", "-key", "int", "-val", "string", "-type", "ByAge", "-tests", "-o", "by_age.go"},
+		{"queue", "-key", "int", "-type", "todo", "-tests", "-o", "todo.go"},
+		{"queue", "-key", "int", "-unexported", "-tests", "-bench", "-o", "queue.go"},
+		{"heap", "-key", "int", "-unexported", "-constructor", "heapOf", "-tests", "-o", "heap.go"},
+		{"sset", "-key", "string", "-unexported", "-generic", "-o", "set.go"},
+	}, `package instances
+
+import "testing"
+
+func TestInstances(t *testing.T) {
+	ids, ages := NewByID(), NewByAge()
+	ids.Put(1, "a")
+	ages.Put(1, "b")
+	if v, _ := ids.Get(1); v != "a" {
+		t.Errorf("want a, got %q", v)
+	}
+
+	todo, q := newTodo(1), newIntQueue(1)
+	todo.Push(1)
+	q.Push(2)
+	if todo.Pop() != 1 || q.Pop() != 2 {
+		t.Error("the queues share their elements")
+	}
+
+	var h *intHeap = heapOf(1, 3, 2)
+	if h.Pop() != 3 {
+		t.Error("want the largest element first")
+	}
+
+	var s *sortedStringSet = newSortedStringSet()
+	s.Put("a")
+	if !s.Contains("a") {
+		t.Error("want a in the set")
+	}
+}
+`)
+}
+
+func TestHelpersOfTheSameTypesDontCollide(t *testing.T) {
+	testGenerated(t, "helpers", [][]string{
+		{"queue", "-key", "int", "-tests", "-bench", "-o", "queue.go"},
+		{"queue", "-key", "int", "-unexported", "-tests", "-bench", "-o", "private_queue.go"},
+		{"sset", "-key", "int", "-tests", "-o", "set.go"},
+		{"sset", "-key", "int", "-unexported", "-tests", "-bench", "-o", "private_set.go"},
+		{"smap", "-key", "[]byte", "-val", "int", "-tests", "-o", "map.go"},
+		{"smap", "-key", "[]byte", "-val", "int", "-unexported", "-tests", "-o", "private_map.go"},
+		{"heap", "-key", "int", "-tests", "-o", "heap.go"},
+		{"heap", "-key", "int", "-unexported", "-tests", "-o", "private_heap.go"},
+	}, `package helpers
+
+import "testing"
+
+func TestHelpers(t *testing.T) {
+	q, private := NewIntQueue(1), newIntQueue(1)
+	q.Push(1)
+	private.Push(2)
+	if q.Pop() != 1 || private.Pop() != 2 {
+		t.Error("the queues share their elements")
+	}
+	if nilInt != 0 || nilIntQueue != 0 {
+		t.Error("want the nil values of both queues")
+	}
+	var _ *nodeInt = NewSortedIntSet().root
+	var _ *nodeSortedIntSet = newSortedIntSet().root
+}
+`)
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+func TestReverseCompareFunc(t *testing.T) {
+	got := reverseCompareFunc(floatCompareFunc("h Heap", 0))
+	for _, want := range []string{
+		"// each other and larger than any other key.\n// The order is reversed by swapping a and b.\n",
+		"func (h Heap) compare(b, a KType) int {",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want %q in:\n%s", want, got)
+		}
+	}
+	got = reverseCompareFunc("func (h Heap) compare(a, b KType) int { return len(a)-len(b) }")
+	want := "// compare orders the keys in reverse, by swapping a and b.\nfunc (h Heap) compare(b, a KType) int {"
+	if !strings.HasPrefix(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+// commentLines returns the lines of the comments of src, in order.
+func commentLines(t *testing.T, src []byte) []string {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			lines = append(lines, c.Text)
+		}
+	}
+	return lines
+}
+
+func TestReversedComments(t *testing.T) {
+	tests := []struct {
+		kind         string
+		src, iterSrc string
+		// reworded are the comment lines changed by the reversed order,
+		// all the others stay the same
+		reworded map[string]string
+	}{
+		{"heap", heapSrc, heapIterSrc, map[string]string{
+			"// retrieved in their decreasing order (according to their comparison":         "// retrieved in their increasing order (according to their comparison",
+			"// Peek at the largest element (according to their comparison rules), without": "// Peek at the smallest element (according to their comparison rules), without",
+			"// Pop removes the largest element (according to their comparison rules) from": "// Pop removes the smallest element (according to their comparison rules) from",
+			"// larger than largest, don't try to find it":                                  "// smaller than smallest, don't try to find it",
+			"// Drain returns the elements of the heap in decreasing order, removing":       "// Drain returns the elements of the heap in increasing order, removing",
+		}},
+		{"smap", redblackbstMapSrc, redblackbstMapIterSrc, map[string]string{
+			"// Min returns the smallest key/value in the sorted map, if it exists.":          "// Min returns the largest key/value in the sorted map, if it exists.",
+			"// Max returns the largest key/value in the sorted map, if it exists.":           "// Max returns the smallest key/value in the sorted map, if it exists.",
+			"// Floor returns the largest key/value in the sorted map that is smaller than":   "// Floor returns the smallest key/value in the sorted map that is larger than",
+			"// Ceiling returns the smallest key/value in the sorted map that is larger than": "// Ceiling returns the largest key/value in the sorted map that is smaller than",
+			"// Lower returns the largest key/value in the sorted map that is strictly":       "// Lower returns the smallest key/value in the sorted map that is strictly",
+			"// smaller than `k`.": "// larger than `k`.",
+			"// Higher returns the smallest key/value in the sorted map that is strictly": "// Higher returns the largest key/value in the sorted map that is strictly",
+			"// larger than `k`.":                          "// smaller than `k`.",
+			"// Rank is the number of keys less than `k`.": "// Rank is the number of keys greater than `k`.",
+			"// KeysAbove visit each keys larger than lo in the sorted map, or equal to":  "// KeysAbove visit each keys smaller than lo in the sorted map, or equal to",
+			"// KeysBelow visit each keys smaller than hi in the sorted map, or equal to": "// KeysBelow visit each keys larger than hi in the sorted map, or equal to",
+			"// larger than lo, or equal to it if inclusive.":                             "// smaller than lo, or equal to it if inclusive.",
+			"// smaller than hi, or equal to it if inclusive.":                            "// larger than hi, or equal to it if inclusive.",
+			"// order. It isn't positioned yet: Next moves it to the smallest key, and":   "// order. It isn't positioned yet: Next moves it to the largest key, and",
+			"// Prev to the largest one.":                                                 "// Prev to the smallest one.",
+			"// First moves the iterator to the smallest key, and tells if there's one.":  "// First moves the iterator to the largest key, and tells if there's one.",
+			"// Last moves the iterator to the largest key, and tells if there's one.":    "// Last moves the iterator to the smallest key, and tells if there's one.",
+			"// SeekCeiling moves the iterator to the smallest key larger than or equal":  "// SeekCeiling moves the iterator to the largest key smaller than or equal",
+			"// the key sought is the last one of the path to `k` that's larger":          "// the key sought is the last one of the path to `k` that's smaller",
+			"// SeekFloor moves the iterator to the largest key smaller than or equal":    "// SeekFloor moves the iterator to the smallest key larger than or equal",
+			"// the key sought is the last one of the path to `k` that's smaller":         "// the key sought is the last one of the path to `k` that's larger",
+			"// DeleteMin removes the smallest key and its value from the sorted map.":    "// DeleteMin removes the largest key and its value from the sorted map.",
+			"// DeleteMax removes the largest key and its value from the sorted map.":     "// DeleteMax removes the smallest key and its value from the sorted map.",
+			"// All returns the keys/values of the sorted map, in increasing order.":      "// All returns the keys/values of the sorted map, in decreasing order.",
+			"// Backward returns the keys/values of the sorted map, in decreasing order.": "// Backward returns the keys/values of the sorted map, in increasing order.",
+			"// increasing order.": "// decreasing order.",
+			"// AllKeys returns the keys of the sorted map, in increasing order.":        "// AllKeys returns the keys of the sorted map, in decreasing order.",
+			"// AllValues returns the values of the sorted map, in the increasing order": "// AllValues returns the values of the sorted map, in the decreasing order",
+		}},
+		{"sset", redblackbstSetSrc, redblackbstSetIterSrc, map[string]string{
+			"// Min returns the smallest key in the sorted set, if it exists.":          "// Min returns the largest key in the sorted set, if it exists.",
+			"// Max returns the largest key in the sorted set, if it exists.":           "// Max returns the smallest key in the sorted set, if it exists.",
+			"// Floor returns the largest key in the sorted set that is smaller than":   "// Floor returns the smallest key in the sorted set that is larger than",
+			"// Ceiling returns the smallest key in the sorted set that is larger than": "// Ceiling returns the largest key in the sorted set that is smaller than",
+			"// Lower returns the largest key in the sorted set that is strictly":       "// Lower returns the smallest key in the sorted set that is strictly",
+			"// smaller than `k`.": "// larger than `k`.",
+			"// Higher returns the smallest key in the sorted set that is strictly": "// Higher returns the largest key in the sorted set that is strictly",
+			"// larger than `k`.":                          "// smaller than `k`.",
+			"// Rank is the number of keys less than `k`.": "// Rank is the number of keys greater than `k`.",
+			"// KeysAbove visit each keys larger than lo in the sorted set, or equal to":  "// KeysAbove visit each keys smaller than lo in the sorted set, or equal to",
+			"// KeysBelow visit each keys smaller than hi in the sorted set, or equal to": "// KeysBelow visit each keys larger than hi in the sorted set, or equal to",
+			"// larger than lo, or equal to it if inclusive.":                             "// smaller than lo, or equal to it if inclusive.",
+			"// smaller than hi, or equal to it if inclusive.":                            "// larger than hi, or equal to it if inclusive.",
+			"// DeleteMin removes the smallest key from the sorted set.":                  "// DeleteMin removes the largest key from the sorted set.",
+			"// DeleteMax removes the largest key from the sorted set.":                   "// DeleteMax removes the smallest key from the sorted set.",
+			"// All returns the keys of the sorted set, in increasing order.":             "// All returns the keys of the sorted set, in decreasing order.",
+		}},
+	}
+	for _, tt := range tests {
+		tmpl := newTemplate(tt.src)
+		if err := tmpl.appendTemplate(tt.iterSrc); err != nil {
+			t.Fatal(err)
+		}
+		before := commentLines(t, tmpl.src)
+		if err := tmpl.renameCommentWords(orderedDecls, reversedWords); err != nil {
+			t.Fatal(err)
+		}
+		after := commentLines(t, tmpl.src)
+		if len(after) != len(before) {
+			t.Fatalf("%s: want %d comment lines, got %d", tt.kind, len(before), len(after))
+		}
+		seen := make(map[string]bool)
+		for i, line := range before {
+			want, ok := tt.reworded[line]
+			if !ok {
+				want = line
+			}
+			seen[line] = seen[line] || ok
+			if after[i] != want {
+				t.Errorf("%s: want %q, got %q", tt.kind, want, after[i])
+			}
+		}
+		for line := range tt.reworded {
+			if !seen[line] {
+				t.Errorf("%s: no comment line %q to reword", tt.kind, line)
+			}
+		}
+	}
+}
+
+const orderTest = `package orders
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOrders(t *testing.T) {
+	minHeap := NewIntMinHeap(3, 1, 2)
+	maxHeap := NewIntHeap(3, 1, 2)
+	if min, max := minHeap.Pop(), maxHeap.Pop(); min != 1 || max != 3 {
+		t.Errorf("want to pop 1 and 3, got %d and %d", min, max)
+	}
+
+	names := NewNames()
+	for _, name := range []string{"b", "c", "a"} {
+		names.Put(name, len(name))
+	}
+	if k, _, _ := names.Min(); k != "c" {
+		t.Errorf("want c first, got %q", k)
+	}
+	if k, _, _ := names.Floor("bb"); k != "c" {
+		t.Errorf("want c to be the largest key smaller than bb, got %q", k)
+	}
+
+	composite := NewSortedNameSet()
+	composite.Put(SortedNameSetKey{Name: "a"})
+	composite.Put(SortedNameSetKey{Name: "b"})
+	if k, _ := composite.Min(); k.Name != "b" {
+		t.Errorf("want b first, got %v", k)
+	}
+
+	now := time.Now()
+	generic := NewTimeMinHeap(now, now.Add(-time.Hour))
+	if k := generic.Pop(); !k.Equal(now.Add(-time.Hour)) {
+		t.Errorf("want the earliest time first, got %v", k)
+	}
+	genericSet := NewGenericSet()
+	genericSet.Put("a")
+	genericSet.Put("b")
+	if k, _ := genericSet.Min(); k != "b" {
+		t.Errorf("want b first, got %q", k)
+	}
+
+	src, err := ioutil.ReadFile("min_heap.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "Pop removes the smallest element") {
+		t.Errorf("want the docs of the min-heap to pop the smallest element:\n%s", src)
+	}
+}
+`
+
+func TestOrder(t *testing.T) {
+	testGenerated(t, "orders", [][]string{
+		{"heap", "-key", "int", "-order", "asc", "-tests", "-bench", "-o", "min_heap.go"},
+		{"heap", "-key", "int", "-order", "desc", "-tests", "-o", "heap.go"},
+		{"smap", "-key", "string", "-val", "int", "-order", "desc", "-type", "Names", "-tests", "-o", "names.go"},
+		{"sset", "-key", "float64", "-order", "desc", "-tests", "-o", "floats.go"},
+		{"sset", "-key", "(name string)", "-order", "desc", "-o", "composite.go"},
+		{"heap", "-key", "time.Time", "-order", "asc", "-generic", "-o", "generic_heap.go"},
+		{"sset", "-key", "string", "-order", "desc", "-generic", "-type", "GenericSet", "-o", "generic_set.go"},
+	}, orderTest)
+}
+
+func TestOrderErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{heap(), sortedSet()}
+	for _, args := range [][]string{
+		{"heap", "-key", "int", "-order", "min"},
+		{"sset", "-key", "[]int", "-order", "desc", "-tests"},
+	} {
+		args = append([]string{"datagen"}, append(args, "-package", "foo", "-o", filepath.Join(dir, "out.go"))...)
+		if err := app.Run(args); err == nil {
+			t.Errorf("%v: want an error", args)
+		}
+	}
+}
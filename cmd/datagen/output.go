@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	goversion "go/version"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	outputFlag = cli.StringFlag{
+		Name:  "output, o",
+		Usage: "file where the generated code is written, instead of stdout",
+	}
+	packageFlag = cli.StringFlag{
+		Name:  "package",
+		Usage: "name of the package the code is generated for (default: the package found in the output directory)",
+	}
+)
+
+// output is where the generated code goes.
+type output struct {
+	// dir of the package the code is generated for.
+	dir string
+	// filename to write to, or empty for stdout.
+	filename string
+
+	// pkgName is the name of the package the code is generated for.
+	pkgName string
+	// pkgPath is the import path of that package, if it's known.
+	pkgPath string
+	// goVersion of the module holding that package, like go1.23, or empty
+	// outside of a module.
+	goVersion string
+
+	// header written at the top of the generated files.
+	header string
+
+	// capture receives the generated files instead of them being written,
+	// when it's set.
+	capture captureFunc
+	// pending are the files generated by the same run but not written yet,
+	// by filename.
+	pending map[string][]byte
+}
+
+// captureFunc receives the generated files instead of them being written,
+// when it's found in the app's metadata under captureKey.
+type captureFunc func(filename string, src []byte) error
+
+// Keys of the app's metadata that change how the code is written.
+const (
+	captureKey  = "capture"
+	pendingKey  = "pending"
+	manifestKey = "manifest"
+	stdoutKey   = "stdout"
+)
+
+func newOutput(ctx *cli.Context) (output, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return output{}, fmt.Errorf("can't find current directory: %v", err)
+	}
+
+	out := output{dir: cwd}
+	filename := ctx.String("output")
+	if filename == "" && ctx.App != nil {
+		// the code of a command that wrote to stdout, being regenerated
+		// into the file it was redirected to
+		filename, _ = ctx.App.Metadata[stdoutKey].(string)
+	}
+	if filename != "" {
+		out.filename, err = filepath.Abs(filename)
+		if err != nil {
+			return output{}, fmt.Errorf("invalid output file: %v", err)
+		}
+		out.dir = filepath.Dir(out.filename)
+	} else if ctx.Bool("tests") || ctx.Bool("bench") {
+		return output{}, errors.New("-tests and -bench need a file given with -o, to write next to it")
+	}
+
+	out.pkgName, out.pkgPath = loadPackage(out.dir)
+	out.goVersion = moduleGoVersion(out.dir)
+	dirPkg := out.pkgName
+
+	// when invoked by `go generate`, the package of the file holding the
+	// directive is known, which matters when a directory holds both a
+	// package and its external tests
+	if name := os.Getenv("GOPACKAGE"); name != "" && out.dir == cwd {
+		out.pkgName = name
+	}
+	if name := ctx.String("package"); name != "" {
+		out.pkgName = name
+	}
+
+	if out.pkgName == "" {
+		return output{}, fmt.Errorf("no Go package found in %q, specify one with -package", out.dir)
+	}
+	if !token.IsIdentifier(out.pkgName) {
+		return output{}, fmt.Errorf("invalid package name %q", out.pkgName)
+	}
+	command := commandLine(ctx, out, dirPkg)
+	if ctx.App != nil {
+		out.capture, _ = ctx.App.Metadata[captureKey].(captureFunc)
+		out.pending, _ = ctx.App.Metadata[pendingKey].(map[string][]byte)
+		// the code generated for a manifest is regenerated with it
+		if manifest, ok := ctx.App.Metadata[manifestKey].(string); ok {
+			if rel, err := filepath.Rel(out.dir, manifest); err == nil {
+				manifest = rel
+			}
+			command = "datagen gen -f " + shellQuote(manifest)
+		}
+	}
+	out.header = generatedCodeHeader(command)
+	return out, nil
+}
+
+// loadPackage finds the name and import path of the package in dir. They
+// are left empty if there's no such package.
+func loadPackage(dir string) (name, path string) {
+	cfg := &packages.Config{Mode: packages.NeedName, Dir: dir}
+	pkgs, err := packages.Load(cfg, ".")
+	if err == nil && len(pkgs) == 1 && pkgs[0].Name != "" {
+		return pkgs[0].Name, pkgs[0].PkgPath
+	}
+	// outside of a module or GOPATH, the package clauses of the files are
+	// all there is to go by
+	return packageClause(dir), ""
+}
+
+// iteratorsGoVersion is the first version of Go that ranges over functions,
+// which the iterators of the datastructures are made for.
+const iteratorsGoVersion = "go1.23"
+
+// iterators tells if the datastructures get iterators, which they do when
+// their module's version of Go supports them.
+func (out output) iterators() bool {
+	return out.goVersion != "" && goversion.Compare(out.goVersion, iteratorsGoVersion) >= 0
+}
+
+// moduleGoVersion returns the version of Go declared by the go.mod of the
+// module holding dir, or nothing outside of a module.
+func moduleGoVersion(dir string) string {
+	for d := dir; ; d = filepath.Dir(d) {
+		src, err := ioutil.ReadFile(filepath.Join(d, "go.mod"))
+		if err == nil {
+			for _, line := range strings.Split(string(src), "\n") {
+				if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "go" {
+					return "go" + fields[1]
+				}
+			}
+			return ""
+		}
+		if filepath.Dir(d) == d {
+			return ""
+		}
+	}
+}
+
+// packageClause returns the package name shared by the non-test files of
+// dir, if there's exactly one.
+func packageClause(dir string) string {
+	filenames, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return ""
+	}
+	names := make(map[string]bool)
+	for _, filename := range filenames {
+		if strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.PackageClauseOnly)
+		if err != nil {
+			continue
+		}
+		names[file.Name.Name] = true
+	}
+	if len(names) != 1 {
+		return ""
+	}
+	for name := range names {
+		return name
+	}
+	return ""
+}
+
+// write the generated code to the output.
+func (o output) write(src []byte) error {
+	if o.capture != nil {
+		return o.capture(o.filename, src)
+	}
+	if o.filename == "" {
+		_, err := os.Stdout.Write(src)
+		return err
+	}
+	return writeFileAtomic(o.filename, src)
+}
+
+// writeFileAtomic writes data to a temporary file that's then renamed to
+// filename, so that a failure never leaves a partially written file
+// behind. Nothing is written if the file already holds the same data, so
+// that its modification time doesn't trigger needless rebuilds.
+func writeFileAtomic(filename string, data []byte) error {
+	mode := os.FileMode(0644)
+	if fi, err := os.Stat(filename); err == nil {
+		old, err := ioutil.ReadFile(filename)
+		if err == nil && bytes.Equal(old, data) {
+			return nil
+		}
+		mode = fi.Mode().Perm()
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %v", err)
+	}
+	// no-op once renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing temporary file: %v", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("syncing temporary file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing temporary file: %v", err)
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return fmt.Errorf("setting permissions of temporary file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("writing %q: %v", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "gen.go")
+	want := []byte("package foo\n")
+
+	if err := writeFileAtomic(filename, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(filename, past, past); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(filename, want); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.ModTime().Equal(past) {
+		t.Errorf("unchanged file was rewritten, mtime went from %v to %v", past, fi.ModTime())
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("want only the output file, got %d files", len(entries))
+	}
+}
+
+func TestLoadPackageUsesPackageClause(t *testing.T) {
+	dir, err := ioutil.TempDir("", "foo-bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"a.go":      "package foo\n",
+		"a_test.go": "package foo_test\n",
+		"empty.go":  "",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if name, _ := loadPackage(dir); name != "foo" {
+		t.Errorf("want package %q, got %q", "foo", name)
+	}
+}
+
+func TestLoadPackageInModule(t *testing.T) {
+	name, path := loadPackage(".")
+	if name != "main" {
+		t.Errorf("want package %q, got %q", "main", name)
+	}
+	if want := "github.com/aybabtme/datagen/cmd/datagen"; path != want {
+		t.Errorf("want import path %q, got %q", want, path)
+	}
+}
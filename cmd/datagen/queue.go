@@ -1,11 +1,8 @@
 package main
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"gopkg.in/urfave/cli.v1"
 )
@@ -23,34 +20,81 @@ func queue() cli.Command {
 		Usage:     "Create a queue (list) customized for your types.",
 		Description: `Create a queue customized for your types. The implementation
 is based on a ring buffer, which has good performance and is well tested.
-(the tests are not generated with the custom type)`,
-		Flags: []cli.Flag{keyTypeFlag},
-		Action: func(ctx *cli.Context) {
-			ktype := valOrDefault(ctx, keyTypeFlag)
-
-			kname := ktype
-			if len(kname) > 1 && []byte(kname)[0] == '*' {
-				kname = kname[1:]
-			}
-			if len(kname) > 2 && kname[:2] == "[]" {
-				kname = strings.Title(kname[2:]) + "s"
+The tests can be generated for your type with -tests, and benchmarks
+comparing it to the standard library with -bench.`,
+		Flags: []cli.Flag{keyTypeFlag, outputFlag, packageFlag, typeFlag, constructorFlag, unexportedFlag, genericFlag, testsFlag, benchFlag, keyGenFlag},
+		Action: func(ctx *cli.Context) error {
+			out, err := newOutput(ctx)
+			if err != nil {
+				return err
 			}
 
-			typeName := fmt.Sprintf("%sQueue", strings.Title(kname))
+			ktype, err := parseType(valOrDefault(ctx, keyTypeFlag), out)
+			if err != nil {
+				return err
+			}
 
-			cwd, _ := os.Getwd()
-			pkgname := fmt.Sprintf("package %s", filepath.Base(cwd))
+			kname := typeIdent(ktype.expr)
+			names, err := chooseNames(ctx, kname+"Queue", kname)
+			if err != nil {
+				return err
+			}
 
-			src := []byte(queueSrc)
-			src = bytes.Replace(src, []byte("package queue"), []byte(pkgname), 1)
+			if ctx.Bool("generic") {
+				if ctx.Bool("tests") || ctx.Bool("bench") {
+					return errors.New("-tests and -bench can't be used with -generic, the generic libraries have their own tests")
+				}
+				src, err := finish(out, genericQueue(out.pkgName, names, ktype))
+				if err != nil {
+					return fmt.Errorf("queue of %s: %v", ktype.name, err)
+				}
+				return out.write(src)
+			}
 
-			src = bytes.Replace(src, []byte("// GENERATED CODE!!!"), []byte(generatedCodeComment()), 1)
+			if err := names.avoidDeclared(ctx, out); err != nil {
+				return err
+			}
+			tmpl := newTemplate(queueSrc)
+			if err := tmpl.replaceComment("// GENERATED CODE!!!", ""); err != nil {
+				return err
+			}
+			if out.iterators() {
+				if err := tmpl.appendTemplate(queueIterSrc); err != nil {
+					return err
+				}
+			}
+			if err := tmpl.addImports(ktype); err != nil {
+				return err
+			}
+			idents := map[string]string{
+				"KType":    ktype.name,
+				"nilKType": names.nilValue,
+				"Queue":    names.typ,
+				"NewQueue": names.constructor,
+			}
+			src, err := tmpl.rewrite(out.pkgName, idents)
+			if err != nil {
+				return err
+			}
+			src, err = finish(out, src)
+			if err != nil {
+				return fmt.Errorf("queue of %s: %v", ktype.name, err)
+			}
 
-			src = bytes.Replace(src, []byte("nilKType"), []byte("nil"+kname), -1) // before KType's replace
-			src = bytes.Replace(src, []byte("KType"), []byte(ktype), -1)
-			src = bytes.Replace(src, []byte("Queue"), []byte(typeName), -1)
+			suites, err := generateSuites(ctx, out, idents, ktype, nil, false, queueTests, queueBenchmarks)
+			if err != nil {
+				return fmt.Errorf("queue of %s: %v", ktype.name, err)
+			}
 
-			fmt.Println(string(src))
+			if err := out.write(src); err != nil {
+				return err
+			}
+			for _, suite := range suites {
+				if err := suite.write(); err != nil {
+					return fmt.Errorf("queue of %s: %v", ktype.name, err)
+				}
+			}
+			return nil
 		},
 	}
 }
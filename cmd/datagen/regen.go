@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+func regenCommand() cli.Command {
+	dryRunFlag := cli.BoolFlag{
+		Name:  "n",
+		Usage: "print the commands that regenerate the files, without running them",
+	}
+
+	return cli.Command{
+		Name:      "regen",
+		Usage:     "Regenerate every file generated by datagen in a module.",
+		ArgsUsage: "[dir]",
+		Description: `Regenerate the files generated by datagen that are found in dir, or
+in the module of the current directory, with the command recorded in
+their header. The files are updated to the current version of datagen
+and of its templates.`,
+		Flags: []cli.Flag{dryRunFlag},
+		Action: func(ctx *cli.Context) error {
+			root, err := walkRoot(ctx.Args().First())
+			if err != nil {
+				return err
+			}
+			gens, err := findGenerated(root)
+			if err != nil {
+				return err
+			}
+			for _, gen := range gens {
+				if ctx.Bool("n") {
+					var args []string
+					for _, arg := range gen.commandLine() {
+						args = append(args, shellQuote(arg))
+					}
+					if gen.stdout() {
+						args = append(args, ">", shellQuote(gen.filename))
+					}
+					fmt.Println(strings.Join(args, " "))
+					continue
+				}
+				if err := gen.run(ctx.App); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// generatedFile is a file generated by datagen, along with the command
+// that generated it.
+type generatedFile struct {
+	filename string
+	args     []string
+}
+
+// commandLine is the command regenerating the file, with its output, or
+// its manifest, given by an absolute path since the command can run from
+// anywhere.
+func (g generatedFile) commandLine() []string {
+	paths := map[string]string{"o": "-o", "output": "-o", "f": "-f", "file": "-f"}
+
+	args := []string{"datagen"}
+	dir := filepath.Dir(g.filename)
+	for i := 0; i < len(g.args); i++ {
+		arg := g.args[i]
+		name, value := strings.TrimLeft(arg, "-"), ""
+		if eq := strings.Index(name, "="); eq >= 0 {
+			name, value = name[:eq], name[eq+1:]
+		} else if _, ok := paths[name]; ok && i+1 < len(g.args) {
+			i++
+			value = g.args[i]
+		}
+		flag, ok := paths[name]
+		if !strings.HasPrefix(arg, "-") || !ok {
+			args = append(args, arg)
+			continue
+		}
+		if !filepath.IsAbs(value) {
+			value = filepath.Join(dir, value)
+		}
+		args = append(args, flag, value)
+	}
+	return args
+}
+
+// stdout tells if the command wrote the code to stdout, from where it was
+// redirected to the file. The command isn't given the file with -o then,
+// which its header would record, but under stdoutKey in the app's
+// metadata.
+func (g generatedFile) stdout() bool {
+	if len(g.args) > 0 && g.args[0] == "gen" {
+		return false
+	}
+	for _, arg := range g.args {
+		name := strings.TrimLeft(arg, "-")
+		if eq := strings.Index(name, "="); eq >= 0 {
+			name = name[:eq]
+		}
+		if strings.HasPrefix(arg, "-") && (name == "o" || name == "output") {
+			return false
+		}
+	}
+	return true
+}
+
+func (g generatedFile) run(app *cli.App) error {
+	if g.stdout() {
+		if app.Metadata == nil {
+			app.Metadata = make(map[string]interface{})
+		}
+		app.Metadata[stdoutKey] = g.filename
+		defer delete(app.Metadata, stdoutKey)
+	}
+	if err := app.Run(g.commandLine()); err != nil {
+		return fmt.Errorf("regenerating %s: %v", g.filename, err)
+	}
+	return nil
+}
+
+// walkRoot returns the directory to look for generated files in: dir if
+// it's given, and otherwise the root of the current module, or the current
+// directory outside of a module.
+func walkRoot(dir string) (string, error) {
+	if dir != "" {
+		return filepath.Abs(dir)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("can't find current directory: %v", err)
+	}
+	for d := cwd; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			return d, nil
+		}
+		if filepath.Dir(d) == d {
+			return cwd, nil
+		}
+	}
+}
+
+// findGenerated walks root for the files generated by datagen, leaving
+// aside the directories that the go tool ignores and the other modules
+// nested in root. A command that generated many files, like a
+// datastructure and its tests, is only returned once.
+func findGenerated(root string) ([]generatedFile, error) {
+	var gens []generatedFile
+	seen := make(map[string]bool)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := info.Name()
+		if info.IsDir() {
+			if path == root {
+				return nil
+			}
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") {
+			return nil
+		}
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		args, ok, err := headerCommand(src)
+		if err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		if !ok {
+			return nil
+		}
+		gen := generatedFile{filename: path, args: args}
+		key := strings.Join(gen.commandLine(), "\x00")
+		if gen.stdout() {
+			// the same command can be redirected to many files
+			key += "\x00" + path
+		}
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+		gens = append(gens, gen)
+		return nil
+	})
+	return gens, err
+}
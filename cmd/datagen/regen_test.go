@@ -0,0 +1,195 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+func TestHeaderCommand(t *testing.T) {
+	tests := []struct {
+		src  string
+		want []string
+	}{
+		{
+			src:  generatedCodeHeader("datagen heap -key '[]byte' -o heap.go") + "package foo\n",
+			want: []string{"heap", "-key", "[]byte", "-o", "heap.go"},
+		},
+		{
+			// written by older versions
+			src:  "package foo\n\n// GENERATED CODE, DO NOT EDIT\n// This code was generated by a tool.\n//\n// \tgithub.com/aybabtme/datagen\n//\n// The command that generated this was:\n//\n//\t/tmp/go-build1/exe/datagen sset -key int\n\nfunc f() {}\n",
+			want: []string{"sset", "-key", "int"},
+		},
+		{src: "package foo\n\n// Code generated by stringer. DO NOT EDIT.\n"},
+		{src: "package foo\n\nfunc f() {}\n\n// Code generated by datagen master. DO NOT EDIT.\n"},
+	}
+	for _, tt := range tests {
+		args, ok, err := headerCommand([]byte(tt.src))
+		if err != nil {
+			t.Errorf("%q: %v", tt.src, err)
+			continue
+		}
+		if ok != (tt.want != nil) || !reflect.DeepEqual(args, tt.want) {
+			t.Errorf("%q: want %q, got %q (%v)", tt.src, tt.want, args, ok)
+		}
+	}
+}
+
+func TestSplitWordsReadsQuotedArgs(t *testing.T) {
+	args := []string{"datagen", "smap", "-key", "[]byte", "-val", "map[string]it's", "-o", "a b.go"}
+	var quoted []string
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	got, err := splitWords(strings.Join(quoted, " "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, args) {
+		t.Errorf("want %q, got %q", args, got)
+	}
+	if _, err := splitWords("datagen heap -key 'int"); err == nil {
+		t.Error("want an error for an unterminated quote")
+	}
+}
+
+func TestRegenRestoresGeneratedFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pkg := filepath.Join(dir, "foo")
+	if err := os.Mkdir(pkg, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// the files found in the ignored directories are left alone
+	ignored := filepath.Join(dir, "vendor", "bar")
+	if err := os.MkdirAll(ignored, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{heap(), queue(), sortedSet(), regenCommand()}
+	for _, args := range [][]string{
+		{"datagen", "heap", "-key", "int", "-package", "foo", "-tests", "-o", filepath.Join(pkg, "heap.go")},
+		{"datagen", "heap", "-key", "int", "-package", "bar", "-o", filepath.Join(ignored, "heap.go")},
+	} {
+		if err := app.Run(args); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+	}
+	runRedirected(t, app, []string{"datagen", "queue", "-key", "int", "-package", "foo"}, filepath.Join(pkg, "queue.go"))
+
+	want := make(map[string][]byte)
+	for _, name := range []string{"heap.go", "heap_test.go", "queue.go"} {
+		src, err := ioutil.ReadFile(filepath.Join(pkg, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want[name] = src
+	}
+	// a set written to stdout by an older version of datagen
+	old := "package foo\n\n// GENERATED CODE, DO NOT EDIT\n// This code was generated by a tool.\n//\n// \tgithub.com/aybabtme/datagen\n//\n// The command that generated this was:\n//\n//\t/tmp/go-build1/exe/datagen sset -key string\n\ntype SortedStringSet struct{}\n"
+	edits := map[string]string{
+		filepath.Join(pkg, "heap.go"):      strings.Replace(string(want["heap.go"]), "func ", "func\t", -1),
+		filepath.Join(pkg, "heap_test.go"): "",
+		filepath.Join(pkg, "queue.go"):     strings.Replace(string(want["queue.go"]), "func ", "func\t", -1),
+		filepath.Join(pkg, "set.go"):       old,
+		filepath.Join(ignored, "heap.go"):  "package bar\n",
+	}
+	for name, src := range edits {
+		if err := ioutil.WriteFile(name, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the test file no longer has a header, but the command that
+	// generated it is still found in the one of the heap
+	gens, err := findGenerated(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gens) != 3 {
+		t.Fatalf("want the heap, the queue and the set to be found, got %v", gens)
+	}
+
+	if err := app.Run([]string{"datagen", "regen", dir}); err != nil {
+		t.Fatal(err)
+	}
+	for name, src := range want {
+		got, err := ioutil.ReadFile(filepath.Join(pkg, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(src) {
+			t.Errorf("%s isn't regenerated:\n%s", name, got)
+		}
+	}
+	set, err := ioutil.ReadFile(filepath.Join(pkg, "set.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(set), "//\tdatagen sorted-set -key string\n") {
+		t.Errorf("want the set to be regenerated with a current header:\n%s", set)
+	}
+	if src, _ := ioutil.ReadFile(filepath.Join(ignored, "heap.go")); string(src) != "package bar\n" {
+		t.Errorf("want the vendored heap to be left alone, got:\n%s", src)
+	}
+}
+
+// runRedirected runs a datagen command with its stdout redirected to
+// filename, like a shell does for "datagen ... > filename".
+func runRedirected(t *testing.T, app *cli.App, args []string, filename string) {
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	stdout := os.Stdout
+	os.Stdout = f
+	err = app.Run(args)
+	os.Stdout = stdout
+	if err != nil {
+		t.Fatalf("%v: %v", args, err)
+	}
+}
+
+func TestVerifyReportsStaleFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "datagen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{queue(), verifyCommand()}
+	filename := filepath.Join(dir, "queue.go")
+	if err := app.Run([]string{"datagen", "queue", "-key", "int", "-package", "foo", "-o", filename}); err != nil {
+		t.Fatal(err)
+	}
+	// the header of the code written to stdout doesn't record its file
+	runRedirected(t, app, []string{"datagen", "queue", "-key", "string", "-package", "foo"}, filepath.Join(dir, "string_queue.go"))
+	if err := app.Run([]string{"datagen", "verify", dir}); err != nil {
+		t.Fatalf("want freshly generated files to be up to date, got %v", err)
+	}
+
+	src, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := strings.Replace(string(src), "func (q *IntQueue) Len() int {", "func (q *IntQueue) Len() int { // edited", 1)
+	if err := ioutil.WriteFile(filename, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Run([]string{"datagen", "verify", dir}); err == nil {
+		t.Fatal("want an edited file to be reported")
+	}
+	if src, _ := ioutil.ReadFile(filename); string(src) != edited {
+		t.Error("verify changed the file it checks")
+	}
+}
@@ -0,0 +1,469 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// template is one of the embedded datastructure sources. The sources are
+// regular Go files where placeholder identifiers (KType, VType, RedBlack,
+// ...) stand for the user's types. A template is rewritten by splicing
+// whole declarations in or out, and then renaming the placeholder
+// identifiers found in the syntax tree.
+type template struct {
+	src []byte
+	// typeParams of the placeholder types that are made generic, all of
+	// them constrained by `any`.
+	typeParams map[string][]string
+}
+
+func newTemplate(src string) *template {
+	return &template{src: []byte(src)}
+}
+
+func (t *template) parse() (*token.FileSet, *ast.File, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", t.src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing template: %v", err)
+	}
+	return fset, file, nil
+}
+
+// splice replaces the source found between the positions of a node with
+// repl.
+func (t *template) splice(fset *token.FileSet, from, to token.Pos, repl string) {
+	start := fset.Position(from).Offset
+	end := fset.Position(to).Offset
+
+	src := make([]byte, 0, len(t.src)+len(repl))
+	src = append(src, t.src[:start]...)
+	src = append(src, repl...)
+	src = append(src, t.src[end:]...)
+	t.src = src
+}
+
+// replaceComment replaces the comment that reads `text` with repl.
+func (t *template) replaceComment(text, repl string) error {
+	fset, file, err := t.parse()
+	if err != nil {
+		return err
+	}
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if c.Text == text {
+				t.splice(fset, c.Pos(), c.End(), repl)
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("template has no comment %q", text)
+}
+
+// renameCommentWords renames the words found in words in the comments of
+// the declarations named decls, their docs included, all at once so that
+// words can be swapped, like smallest and largest. The other comments are
+// left as they are.
+func (t *template) renameCommentWords(decls []string, words map[string]string) error {
+	fset, file, err := t.parse()
+	if err != nil {
+		return err
+	}
+	named := make(map[string]bool, len(decls))
+	for _, name := range decls {
+		named[name] = true
+	}
+	var within []ast.Node
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if named[d.Name.Name] {
+				within = append(within, d)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok && named[ts.Name.Name] {
+					within = append(within, d)
+				}
+			}
+		}
+	}
+	inDecl := func(c *ast.Comment) bool {
+		for _, decl := range within {
+			from := decl.Pos()
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Doc != nil {
+					from = d.Doc.Pos()
+				}
+			case *ast.GenDecl:
+				if d.Doc != nil {
+					from = d.Doc.Pos()
+				}
+			}
+			if from <= c.Pos() && c.End() <= decl.End() {
+				return true
+			}
+		}
+		return false
+	}
+
+	// from the last comment to the first, so that the positions of the
+	// comments left to rename stay valid
+	for i := len(file.Comments) - 1; i >= 0; i-- {
+		list := file.Comments[i].List
+		for j := len(list) - 1; j >= 0; j-- {
+			if c := list[j]; inDecl(c) {
+				t.splice(fset, c.Pos(), c.End(), renameWords(c.Text, words))
+			}
+		}
+	}
+	return nil
+}
+
+// replaceFunc replaces the declaration of the function or method `name`
+// with the declarations found in repl. The replacement is written in terms
+// of the template's placeholder identifiers.
+func (t *template) replaceFunc(name, repl string) error {
+	fset, file, err := t.parse()
+	if err != nil {
+		return err
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != name {
+			continue
+		}
+		repl = strings.TrimSpace(repl)
+		from := fn.Pos()
+		// a replacement that's documented replaces the documentation too
+		if fn.Doc != nil && strings.HasPrefix(repl, "//") {
+			from = fn.Doc.Pos()
+		}
+		t.splice(fset, from, fn.End(), repl)
+		return nil
+	}
+	return fmt.Errorf("template has no func %q", name)
+}
+
+// removeFunc removes the declaration of the function `name`, along with
+// its documentation.
+func (t *template) removeFunc(name string) error {
+	fset, file, err := t.parse()
+	if err != nil {
+		return err
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Name.Name != name {
+			continue
+		}
+		from := fn.Pos()
+		if fn.Doc != nil {
+			from = fn.Doc.Pos()
+		}
+		t.splice(fset, from, fn.End(), "")
+		return nil
+	}
+	return fmt.Errorf("template has no func %q", name)
+}
+
+// removeVar removes the declaration of the package variable `name`.
+func (t *template) removeVar(name string) error {
+	fset, file, err := t.parse()
+	if err != nil {
+		return err
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR || len(gen.Specs) != 1 {
+			continue
+		}
+		spec := gen.Specs[0].(*ast.ValueSpec)
+		if len(spec.Names) != 1 || spec.Names[0].Name != name {
+			continue
+		}
+		from := gen.Pos()
+		if gen.Doc != nil {
+			from = gen.Doc.Pos()
+		}
+		t.splice(fset, from, gen.End(), "")
+		return nil
+	}
+	return fmt.Errorf("template has no var %q", name)
+}
+
+// addField adds a field to the struct type `typeName`.
+func (t *template) addField(typeName, field string) error {
+	fset, file, err := t.parse()
+	if err != nil {
+		return err
+	}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok || ts.Name.Name != typeName {
+				continue
+			}
+			t.splice(fset, st.Fields.Closing, st.Fields.Closing, "\t"+field+"\n")
+			return nil
+		}
+	}
+	return fmt.Errorf("template has no struct %q", typeName)
+}
+
+// appendDecl adds the declarations found in decl at the end of the
+// template. They're written in terms of the template's placeholder
+// identifiers.
+func (t *template) appendDecl(decl string) {
+	t.src = append(t.src, "\n"+strings.TrimSpace(decl)+"\n"...)
+}
+
+// appendTemplate adds the declarations of src, another template of the
+// same package, at the end of the template, along with their imports. The
+// comments found before its first declaration, like its build constraints,
+// are left out.
+func (t *template) appendTemplate(src string) error {
+	other := newTemplate(src)
+	fset, file, err := other.parse()
+	if err != nil {
+		return err
+	}
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return err
+		}
+		var name string
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if err := t.addImport(name, path); err != nil {
+			return err
+		}
+	}
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			continue
+		}
+		from := decl.Pos()
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Doc != nil {
+				from = d.Doc.Pos()
+			}
+		case *ast.GenDecl:
+			if d.Doc != nil {
+				from = d.Doc.Pos()
+			}
+		}
+		t.appendDecl(string(other.src[fset.Position(from).Offset:]))
+		return nil
+	}
+	return nil
+}
+
+// declNames returns the names of the functions and types declared by the
+// template, leaving the methods aside.
+func (t *template) declNames() ([]string, error) {
+	_, file, err := t.parse()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				names = append(names, d.Name.Name)
+			}
+		case *ast.GenDecl:
+			if d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				names = append(names, spec.(*ast.TypeSpec).Name.Name)
+			}
+		}
+	}
+	return names, nil
+}
+
+// generic makes the placeholder type `name` generic over the given type
+// parameters. Its declaration gets the type parameters, and every use of
+// the type is instantiated with them.
+func (t *template) generic(name string, params ...string) {
+	if t.typeParams == nil {
+		t.typeParams = make(map[string][]string)
+	}
+	t.typeParams[name] = params
+}
+
+// addImport adds an import of path to the template, under the given name
+// if it's not empty.
+func (t *template) addImport(name, path string) error {
+	fset, file, err := t.parse()
+	if err != nil {
+		return err
+	}
+	for _, imp := range file.Imports {
+		if imp.Path.Value == strconv.Quote(path) {
+			return nil
+		}
+	}
+	spec := strconv.Quote(path)
+	if name != "" {
+		spec = name + " " + spec
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+		// add to the existing imports, grouping them if they aren't
+		var specs []string
+		for _, s := range gen.Specs {
+			start, end := fset.Position(s.Pos()).Offset, fset.Position(s.End()).Offset
+			specs = append(specs, string(t.src[start:end]))
+		}
+		specs = append(specs, spec)
+		t.splice(fset, gen.Pos(), gen.End(), fmt.Sprintf("import (\n\t%s\n)", strings.Join(specs, "\n\t")))
+		return nil
+	}
+
+	// otherwise, right before the first declaration
+	at, imp := file.Name.End(), fmt.Sprintf("\n\nimport %s", spec)
+	if len(file.Decls) > 0 {
+		imp = fmt.Sprintf("import %s\n\n", spec)
+		at = file.Decls[0].Pos()
+		if fn, ok := file.Decls[0].(*ast.FuncDecl); ok && fn.Doc != nil {
+			at = fn.Doc.Pos()
+		}
+		if gen, ok := file.Decls[0].(*ast.GenDecl); ok && gen.Doc != nil {
+			at = gen.Doc.Pos()
+		}
+	}
+	t.splice(fset, at, at, imp)
+	return nil
+}
+
+// addImports adds the imports needed to refer to the given types.
+func (t *template) addImports(types ...*goType) error {
+	for _, typ := range types {
+		for _, imp := range typ.imports {
+			if err := t.addImport(imp.name, imp.path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rewrite renames the package of the template and every placeholder
+// identifier listed in idents, both in code and in comments, and returns
+// the resulting source.
+func (t *template) rewrite(pkgname string, idents map[string]string) ([]byte, error) {
+	fset, file, err := t.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	file.Name.Name = pkgname
+
+	// generic types are declared with their type parameters, and
+	// instantiated everywhere else
+	declared := make(map[*ast.Ident]string)
+	code := make(map[string]string, len(idents))
+	for placeholder, repl := range idents {
+		code[placeholder] = repl
+	}
+	for placeholder, params := range t.typeParams {
+		name := placeholder
+		if repl, ok := idents[placeholder]; ok {
+			name = repl
+		}
+		code[placeholder] = name + "[" + strings.Join(params, ", ") + "]"
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch d := n.(type) {
+			case *ast.TypeSpec:
+				if d.Name.Name == placeholder {
+					declared[d.Name] = name + "[" + strings.Join(params, ", ") + " any]"
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil && d.Name.Name == placeholder {
+					declared[d.Name] = name + "[" + strings.Join(params, ", ") + " any]"
+				}
+			}
+			return true
+		})
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok || id == file.Name {
+			return true
+		}
+		if repl, ok := declared[id]; ok {
+			id.Name = repl
+		} else if repl, ok := code[id.Name]; ok {
+			id.Name = repl
+		}
+		return true
+	})
+
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			c.Text = renameWords(c.Text, idents)
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(buf, fset, file); err != nil {
+		return nil, fmt.Errorf("printing template: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renameWords replaces the words of text that are exactly one of the
+// identifiers in idents. Partial matches are left alone, so `Heap` is
+// renamed but `container/heap` and `Heaps` are not.
+func renameWords(text string, idents map[string]string) string {
+	isIdent := func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+
+	out := bytes.NewBuffer(nil)
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		if !isIdent(runes[i]) {
+			out.WriteRune(runes[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isIdent(runes[j]) {
+			j++
+		}
+		word := string(runes[i:j])
+		if repl, ok := idents[word]; ok {
+			word = repl
+		}
+		out.WriteString(word)
+		i = j
+	}
+	return out.String()
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRewriteOnlyRenamesPlaceholders(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		idents map[string]string
+		want   []string
+	}{
+		{
+			name: "heap of *HeapEntry",
+			src:  heapSrc,
+			idents: map[string]string{
+				"KType":   "*HeapEntry",
+				"Heap":    "HeapEntryHeap",
+				"NewHeap": "NewHeapEntryHeap",
+			},
+			want: []string{
+				"type HeapEntryHeap struct",
+				"pq []*HeapEntry",
+				"func NewHeapEntryHeap(keys ...*HeapEntry) *HeapEntryHeap",
+				"// HeapEntryHeap is a container of *HeapEntry",
+				"`container/heap`",
+			},
+		},
+		{
+			name: "queue of QueueItem",
+			src:  queueSrc,
+			idents: map[string]string{
+				"KType":    "QueueItem",
+				"nilKType": "nilQueueItem",
+				"Queue":    "QueueItemQueue",
+				"NewQueue": "NewQueueItemQueue",
+			},
+			want: []string{
+				"var nilQueueItem QueueItem",
+				"type QueueItemQueue struct",
+				"func NewQueueItemQueue(capacity int) *QueueItemQueue",
+				"// Implementation adapted from github.com/eapache/queue:",
+				`panic("queue: empty queue")`,
+			},
+		},
+		{
+			name: "sorted map of map[string]int",
+			src:  redblackbstMapSrc,
+			idents: map[string]string{
+				"KType":       "string",
+				"VType":       "map[string]int",
+				"RedBlack":    "SortedStringToStringToIntMapMap",
+				"NewRedBlack": "NewSortedStringToStringToIntMapMap",
+				"mapnode":     "nodeStringToStringToIntMap",
+			},
+			want: []string{
+				"val         map[string]int",
+				"func (r *SortedStringToStringToIntMapMap) Put(k string, v map[string]int) (old map[string]int, overwrite bool)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := newTemplate(tt.src).rewrite("foo", tt.idents)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), "", src, 0); err != nil {
+				t.Fatalf("generated code doesn't parse: %v\n%s", err, src)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(src), want) {
+					t.Errorf("want %q in generated code:\n%s", want, src)
+				}
+			}
+		})
+	}
+}
+
+func TestTypeIdent(t *testing.T) {
+	tests := map[string]string{
+		"int":                 "Int",
+		"*HeapEntry":          "HeapEntry",
+		"[]byte":              "Bytes",
+		"[]string":            "Strings",
+		"[4]int":              "IntArray",
+		"map[string]int":      "StringToIntMap",
+		"map[string][]*Thing": "StringToThingsMap",
+		"chan int":            "IntChan",
+		"interface{}":         "Interface",
+		"time.Time":           "Time",
+		"*mypkg.Thing":        "Thing",
+	}
+	for typ, want := range tests {
+		gotype, err := parseType(typ, output{})
+		if err != nil {
+			t.Errorf("%q: %v", typ, err)
+			continue
+		}
+		if got := typeIdent(gotype.expr); got != want {
+			t.Errorf("%q: want %q, got %q", typ, want, got)
+		}
+	}
+}
+
+func TestParseTypeRejectsExpressions(t *testing.T) {
+	for _, typ := range []string{"", "1 + 2", "foo()", "a.b.c.", "int int"} {
+		if _, err := parseType(typ, output{}); err == nil {
+			t.Errorf("%q: want an error", typ)
+		}
+	}
+}
+
+func TestResolveQualifiers(t *testing.T) {
+	out := output{dir: ".", pkgName: "pkg", pkgPath: "github.com/org/pkg"}
+	tests := []struct {
+		typ     string
+		want    string
+		imports []importSpec
+	}{
+		{typ: "int", want: "int"},
+		{
+			typ:     "time.Time",
+			want:    "time.Time",
+			imports: []importSpec{{path: "time"}},
+		},
+		{
+			typ:     "*github.com/org/thing/v2.Thing",
+			want:    "*thing.Thing",
+			imports: []importSpec{{name: "thing", path: "github.com/org/thing/v2"}},
+		},
+		{
+			typ:     "map[gopkg.in/yaml.v2.Node][]github.com/org/yaml.Node",
+			want:    "map[yaml.Node][]yaml2.Node",
+			imports: []importSpec{{name: "yaml", path: "gopkg.in/yaml.v2"}, {name: "yaml2", path: "github.com/org/yaml"}},
+		},
+		{typ: "*github.com/org/pkg.Local", want: "*Local"},
+	}
+	for _, tt := range tests {
+		got, imports := resolveQualifiers(tt.typ, out)
+		if got != tt.want {
+			t.Errorf("%q: want %q, got %q", tt.typ, tt.want, got)
+		}
+		if !reflect.DeepEqual(imports, tt.imports) {
+			t.Errorf("%q: want imports %#v, got %#v", tt.typ, tt.imports, imports)
+		}
+	}
+}
@@ -1,12 +1,8 @@
 package main
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
-	"log"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"gopkg.in/urfave/cli.v1"
 )
@@ -28,125 +24,98 @@ func sortedMap() cli.Command {
 		Usage:     "Create a sorted map customized for your types.",
 		Description: `Create a sorted map customized for your types. The map is built
 on a left leaning red black balanced search tree. The implementation has good
-performance and is well tested, with 100% test coverage. (the tests are not
-generated with the custom type)`,
-		Flags: []cli.Flag{keyTypeFlag, valTypeFlag},
-		Action: func(ctx *cli.Context) {
-			ktype := valOrDefault(ctx, keyTypeFlag)
-			vtype := valOrDefault(ctx, valTypeFlag)
+performance and is well tested, with 100% test coverage.
+The tests can be generated for your type with -tests, and benchmarks
+comparing it to the standard library with -bench.`,
+		Flags: []cli.Flag{keyTypeFlag, valTypeFlag, outputFlag, packageFlag, typeFlag, constructorFlag, nodeFlag, unexportedFlag, genericFlag, epsilonFlag, cmpFlag, lessFlag, keyFieldFlag, orderFlag, testsFlag, benchFlag, keyGenFlag, valGenFlag},
+		Action: func(ctx *cli.Context) error {
+			out, err := newOutput(ctx)
+			if err != nil {
+				return err
+			}
 
-			kname := ktype
-			vname := vtype
-			if len(kname) > 1 && []byte(kname)[0] == '*' {
-				kname = kname[1:]
+			ktype, composite, kname, err := parseKey(valOrDefault(ctx, keyTypeFlag), out)
+			if err != nil {
+				return err
+			}
+			vtype, err := parseType(valOrDefault(ctx, valTypeFlag), out)
+			if err != nil {
+				return err
 			}
-			if len(kname) > 2 && kname[:2] == "[]" {
-				kname = strings.Title(kname[2:]) + "s"
+
+			vname := typeIdent(vtype.expr)
+			names, err := chooseNames(ctx, fmt.Sprintf("Sorted%sTo%sMap", kname, vname), kname+"To"+vname)
+			if err != nil {
+				return err
 			}
-			if len(vname) > 1 && []byte(vname)[0] == '*' {
-				vname = vname[1:]
+			ktype, order, err := keyTypeOrder(ctx, out, names, ktype, composite)
+			if err != nil {
+				return err
 			}
-			if len(vname) > 2 && vname[:2] == "[]" {
-				vname = strings.Title(vname[2:]) + "s"
+			if order.reverse, err = reversedOrder(ctx, "asc"); err != nil {
+				return err
 			}
-			typeName := fmt.Sprintf("Sorted%sTo%sMap", strings.Title(kname), strings.Title(vname))
-			nodeName := fmt.Sprintf("node%sTo%s", strings.Title(kname), strings.Title(vname))
-
-			cwd, _ := os.Getwd()
-			pkgname := fmt.Sprintf("package %s", filepath.Base(cwd))
 
-			src := []byte(redblackbstMapSrc)
-			src = bytes.Replace(src, []byte("package redblackbst"), []byte(pkgname), 1)
+			if ctx.Bool("generic") {
+				if ctx.Bool("tests") || ctx.Bool("bench") {
+					return errors.New("-tests and -bench can't be used with -generic, the generic libraries have their own tests")
+				}
+				src, err := finish(out, genericSortedMap(out.pkgName, names, order, vtype))
+				if err != nil {
+					return fmt.Errorf("sorted map of %s to %s: %v", ktype.name, vtype.name, err)
+				}
+				return out.write(src)
+			}
 
-			src = bytes.Replace(src, []byte("// GENERATED CODE!!!"), []byte(generatedCodeComment()), 1)
+			if err := names.avoidDeclared(ctx, out); err != nil {
+				return err
+			}
+			tmpl := newTemplate(redblackbstMapSrc)
+			if err := tmpl.replaceComment("// GENERATED CODE!!!", ""); err != nil {
+				return err
+			}
+			if out.iterators() {
+				if err := tmpl.appendTemplate(redblackbstMapIterSrc); err != nil {
+					return err
+				}
+			}
+			if err := tmpl.addImports(ktype, vtype); err != nil {
+				return err
+			}
+			if err := replaceCompareFunc("r RedBlack", order, tmpl); err != nil {
+				return err
+			}
+			idents := map[string]string{
+				"KType":            ktype.name,
+				"VType":            vtype.name,
+				"RedBlack":         names.typ,
+				"RedBlackIterator": names.typ + "Iterator",
+				"NewRedBlack":      names.constructor,
+				"mapnode":          names.node,
+			}
+			src, err := tmpl.rewrite(out.pkgName, idents)
+			if err != nil {
+				return err
+			}
+			src, err = finish(out, src)
+			if err != nil {
+				return fmt.Errorf("sorted map of %s to %s: %v", ktype.name, vtype.name, err)
+			}
 
-			// need to replace Compare before replacing KType
-			src = replaceRbstCompareFunc(ktype, src)
-			src = bytes.Replace(src, []byte("KType"), []byte(ktype), -1)
-			src = bytes.Replace(src, []byte("VType"), []byte(vtype), -1)
-			src = bytes.Replace(src, []byte("RedBlack"), []byte(typeName), -1)
-			src = bytes.Replace(src, []byte("mapnode"), []byte(nodeName), -1)
+			suites, err := generateSuites(ctx, out, idents, ktype, vtype, order.reverse, sortedMapTests, sortedMapBenchmarks)
+			if err != nil {
+				return fmt.Errorf("sorted map of %s to %s: %v", ktype.name, vtype.name, err)
+			}
 
-			fmt.Println(string(src))
+			if err := out.write(src); err != nil {
+				return err
+			}
+			for _, suite := range suites {
+				if err := suite.write(); err != nil {
+					return fmt.Errorf("sorted map of %s to %s: %v", ktype.name, vtype.name, err)
+				}
+			}
+			return nil
 		},
 	}
 }
-
-func replaceRbstCompareFunc(ktype string, src []byte) []byte {
-	var tmpl string
-	orig := "func (r RedBlack) compare(a, b KType) int { return a.Compare(b) }"
-
-	switch ktype {
-
-	case "int", "int8", "int16", "int32", "int64",
-		"uint", "uint8", "uint16", "uint32", "uint64":
-		tmpl = "func (r RedBlack) compare(a, b KType) int { return int(a) - int(b) }"
-
-	case "float32", "float64":
-		tmpl = `
-func (r RedBlack) compare(a, b KType) int {
-	const e = 0.00000001
-
-    diff := (a-b)/a
-    if diff < -e {
-        return -1
-    } else if diff > e {
-        return 1
-    }
-    return 0
-}`
-
-	case "string":
-		tmpl = `
-func (r RedBlack) compare(a, b KType) int {
-    if a < b {
-        return -1
-    }
-    if a > b {
-        return 1
-    }
-    return 0
-}`
-
-	case "[]byte":
-		log.Printf("WARNING: using []byte as keys can lead to undefined behavior if the []byte are modified after insertion!!!")
-		tmpl = `import "bytes"
-
-// WARNING: using []byte as keys can lead to undefined behavior if the
-// []byte are modified after insertion!!!
-func (r RedBlack) compare(a, b KType) int { return bytes.Compare(a, b) }`
-
-	default:
-
-		// if storing slices, use `len()` for comparison
-		if len(ktype) > 2 && ktype[:2] == "[]" {
-			log.Printf("%s: order will be determined based on value of len(%s)", ktype, ktype)
-			tmpl = fmt.Sprintf(
-				"func (r RedBlack) compare(a, b %s) int { return len(a)-len(b) }",
-				ktype,
-			)
-		} else {
-			l := 0
-			if []rune(ktype)[0] == '*' {
-				l = 1
-			}
-			// otherwise don't change anything by default, let the user
-			// provide a `Compare` func
-			log.Printf("type %q will need to implement a Compare func: %s",
-				ktype,
-				fmt.Sprintf(`
-	func (%[1]s %s) Compare(other %s) int {
-		if %[1]s > other {
-			return 1
-		} else if %[1]s < other {
-			return -1
-		}
-		return 0
-	}`, strings.ToLower(ktype[l:l+1]), ktype, ktype))
-			return src
-		}
-
-	}
-
-	return bytes.Replace(src, []byte(orig), []byte(tmpl), -1)
-}
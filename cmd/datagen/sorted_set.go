@@ -1,11 +1,8 @@
 package main
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"gopkg.in/urfave/cli.v1"
 )
@@ -23,38 +20,90 @@ func sortedSet() cli.Command {
 		Usage:     "Create a sorted set customized for your types.",
 		Description: `Create a sorted set customized for your types. The set is built
 on a left leaning red black balanced search tree. The implementation has good
-performance and is well tested, with 100% test coverage. (the tests are not
-generated with the custom type)`,
-		Flags: []cli.Flag{keyTypeFlag},
-		Action: func(ctx *cli.Context) {
-			ktype := valOrDefault(ctx, keyTypeFlag)
+performance and is well tested, with 100% test coverage.
+The tests can be generated for your type with -tests, and benchmarks
+comparing it to the standard library with -bench.`,
+		Flags: []cli.Flag{keyTypeFlag, outputFlag, packageFlag, typeFlag, constructorFlag, nodeFlag, unexportedFlag, genericFlag, epsilonFlag, cmpFlag, lessFlag, keyFieldFlag, orderFlag, testsFlag, benchFlag, keyGenFlag},
+		Action: func(ctx *cli.Context) error {
+			out, err := newOutput(ctx)
+			if err != nil {
+				return err
+			}
 
-			kname := ktype
-			if len(kname) > 1 && []byte(kname)[0] == '*' {
-				kname = kname[1:]
+			ktype, composite, kname, err := parseKey(valOrDefault(ctx, keyTypeFlag), out)
+			if err != nil {
+				return err
 			}
-			if len(kname) > 2 && kname[:2] == "[]" {
-				kname = strings.Title(kname[2:]) + "s"
+			names, err := chooseNames(ctx, "Sorted"+kname+"Set", kname)
+			if err != nil {
+				return err
+			}
+			ktype, order, err := keyTypeOrder(ctx, out, names, ktype, composite)
+			if err != nil {
+				return err
+			}
+			if order.reverse, err = reversedOrder(ctx, "asc"); err != nil {
+				return err
 			}
 
-			typeName := fmt.Sprintf("Sorted%sSet", strings.Title(kname))
-			nodeName := fmt.Sprintf("node%s", strings.Title(kname))
-
-			cwd, _ := os.Getwd()
-			pkgname := fmt.Sprintf("package %s", filepath.Base(cwd))
-
-			src := []byte(redblackbstSetSrc)
-			src = bytes.Replace(src, []byte("package redblackbst"), []byte(pkgname), 1)
+			if ctx.Bool("generic") {
+				if ctx.Bool("tests") || ctx.Bool("bench") {
+					return errors.New("-tests and -bench can't be used with -generic, the generic libraries have their own tests")
+				}
+				src, err := finish(out, genericSortedSet(out.pkgName, names, order))
+				if err != nil {
+					return fmt.Errorf("sorted set of %s: %v", ktype.name, err)
+				}
+				return out.write(src)
+			}
 
-			src = bytes.Replace(src, []byte("// GENERATED CODE!!!"), []byte(generatedCodeComment()), 1)
+			if err := names.avoidDeclared(ctx, out); err != nil {
+				return err
+			}
+			tmpl := newTemplate(redblackbstSetSrc)
+			if err := tmpl.replaceComment("// GENERATED CODE!!!", ""); err != nil {
+				return err
+			}
+			if out.iterators() {
+				if err := tmpl.appendTemplate(redblackbstSetIterSrc); err != nil {
+					return err
+				}
+			}
+			if err := tmpl.addImports(ktype); err != nil {
+				return err
+			}
+			if err := replaceCompareFunc("r RedBlack", order, tmpl); err != nil {
+				return err
+			}
+			idents := map[string]string{
+				"KType":       ktype.name,
+				"RedBlack":    names.typ,
+				"NewRedBlack": names.constructor,
+				"treenode":    names.node,
+			}
+			src, err := tmpl.rewrite(out.pkgName, idents)
+			if err != nil {
+				return err
+			}
+			src, err = finish(out, src)
+			if err != nil {
+				return fmt.Errorf("sorted set of %s: %v", ktype.name, err)
+			}
 
-			// need to replace Compare before replacing KType
-			src = replaceRbstCompareFunc(ktype, src)
-			src = bytes.Replace(src, []byte("KType"), []byte(ktype), -1)
-			src = bytes.Replace(src, []byte("RedBlack"), []byte(typeName), -1)
-			src = bytes.Replace(src, []byte("treenode"), []byte(nodeName), -1)
+			suites, err := generateSuites(ctx, out, idents, ktype, nil, order.reverse, sortedSetTests, sortedSetBenchmarks)
+			if err != nil {
+				return fmt.Errorf("sorted set of %s: %v", ktype.name, err)
+			}
 
-			fmt.Println(string(src))
+			if err := out.write(src); err != nil {
+				return err
+			}
+			for _, suite := range suites {
+				if err := suite.write(); err != nil {
+					return fmt.Errorf("sorted set of %s: %v", ktype.name, err)
+				}
+			}
+			return nil
 		},
 	}
 }
@@ -4,10 +4,42 @@ package main
 //go:generate embed file --var redblackbstSetSrc --source ../../set/redblackbst/rbbst.go
 //go:generate embed file --var heapSrc --source ../../heap/heap.go
 //go:generate embed file --var queueSrc --source ../../queue/queue.go
+//go:generate embed file --var redblackbstMapTestSrc --source ../../map/redblackbst/template_test.go
+//go:generate embed file --var redblackbstSetTestSrc --source ../../set/redblackbst/template_test.go
+//go:generate embed file --var heapTestSrc --source ../../heap/template_test.go
+//go:generate embed file --var queueTestSrc --source ../../queue/template_test.go
+//go:generate embed file --var redblackbstMapBenchSrc --source ../../map/redblackbst/template_bench_test.go
+//go:generate embed file --var redblackbstSetBenchSrc --source ../../set/redblackbst/template_bench_test.go
+//go:generate embed file --var heapBenchSrc --source ../../heap/template_bench_test.go
+//go:generate embed file --var queueBenchSrc --source ../../queue/template_bench_test.go
+//go:generate embed file --var redblackbstMapIterSrc --source ../../map/redblackbst/iter.go
+//go:generate embed file --var redblackbstSetIterSrc --source ../../set/redblackbst/iter.go
+//go:generate embed file --var heapIterSrc --source ../../heap/iter.go
+//go:generate embed file --var queueIterSrc --source ../../queue/iter.go
+//go:generate embed file --var redblackbstMapIterTestSrc --source ../../map/redblackbst/template_iter_test.go
+//go:generate embed file --var redblackbstSetIterTestSrc --source ../../set/redblackbst/template_iter_test.go
+//go:generate embed file --var heapIterTestSrc --source ../../heap/template_iter_test.go
+//go:generate embed file --var queueIterTestSrc --source ../../queue/template_iter_test.go
 
 const (
-	redblackbstMapSrc = "package redblackbst\n\n// GENERATED CODE!!!\n\nfunc (r RedBlack) compare(a, b KType) int { return a.Compare(b) }\n\n// RedBlack is a sorted map built on a left leaning red black balanced\n// search sorted map. It stores VType values, keyed by KType.\ntype RedBlack struct {\n\troot *mapnode\n}\n\n// NewRedBlack creates a sorted map.\nfunc NewRedBlack() *RedBlack { return &RedBlack{} }\n\n// IsEmpty tells if the sorted map contains no key/value.\nfunc (r RedBlack) IsEmpty() bool {\n\treturn r.root == nil\n}\n\n// Size of the sorted map.\nfunc (r RedBlack) Size() int { return r.root.size() }\n\n// Clear all the values in the sorted map.\nfunc (r *RedBlack) Clear() { r.root = nil }\n\n// Put a value in the sorted map at key `k`. The old value at `k` is returned\n// if the key was already present.\nfunc (r *RedBlack) Put(k KType, v VType) (old VType, overwrite bool) {\n\tr.root, old, overwrite = r.put(r.root, k, func() VType { return v }, func(_ VType) VType { return v })\n\treturn\n}\n\n// Mutate is like a Put when `k` isn't defined, but allows you to create or mutate the value found at the location of `k`.\nfunc (r *RedBlack) Mutate(k KType, creator func() VType, mutator func(old VType) VType) {\n\tr.root, _, _ = r.put(r.root, k, creator, mutator)\n}\n\nfunc (r *RedBlack) put(h *mapnode, k KType, create func() VType, mutate func(old VType) VType) (_ *mapnode, old VType, overwrite bool) {\n\tif h == nil {\n\t\tn := &mapnode{key: k, val: create(), n: 1, colorRed: true}\n\t\treturn n, old, overwrite\n\t}\n\n\tcmp := r.compare(k, h.key)\n\tif cmp < 0 {\n\t\th.left, old, overwrite = r.put(h.left, k, create, mutate)\n\t} else if cmp > 0 {\n\t\th.right, old, overwrite = r.put(h.right, k, create, mutate)\n\t} else {\n\t\toverwrite = true\n\t\told = h.val\n\t\th.val = mutate(old)\n\t}\n\n\tif h.right.isRed() && !h.left.isRed() {\n\t\th = r.rotateLeft(h)\n\t}\n\tif h.left.isRed() && h.left.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\tif h.left.isRed() && h.right.isRed() {\n\t\tr.flipColors(h)\n\t}\n\th.n = h.left.size() + h.right.size() + 1\n\treturn h, old, overwrite\n}\n\n// Get a value from the sorted map at key `k`. Returns false\n// if the key doesn't exist.\nfunc (r RedBlack) Get(k KType) (VType, bool) {\n\treturn r.loopGet(r.root, k)\n}\n\nfunc (r RedBlack) loopGet(h *mapnode, k KType) (v VType, ok bool) {\n\tfor h != nil {\n\t\tcmp := r.compare(k, h.key)\n\t\tif cmp == 0 {\n\t\t\treturn h.val, true\n\t\t} else if cmp < 0 {\n\t\t\th = h.left\n\t\t} else if cmp > 0 {\n\t\t\th = h.right\n\t\t}\n\t}\n\treturn\n}\n\n// Has tells if a value exists at key `k`. This is short hand for `Get.\nfunc (r RedBlack) Has(k KType) bool {\n\t_, ok := r.loopGet(r.root, k)\n\treturn ok\n}\n\n// Min returns the smallest key/value in the sorted map, if it exists.\nfunc (r RedBlack) Min() (k KType, v VType, ok bool) {\n\tif r.root == nil {\n\t\treturn\n\t}\n\th := r.min(r.root)\n\treturn h.key, h.val, true\n}\n\nfunc (r RedBlack) min(x *mapnode) *mapnode {\n\tif x.left == nil {\n\t\treturn x\n\t}\n\treturn r.min(x.left)\n}\n\n// Max returns the largest key/value in the sorted map, if it exists.\nfunc (r RedBlack) Max() (k KType, v VType, ok bool) {\n\tif r.root == nil {\n\t\treturn\n\t}\n\th := r.max(r.root)\n\treturn h.key, h.val, true\n}\n\nfunc (r RedBlack) max(x *mapnode) *mapnode {\n\tif x.right == nil {\n\t\treturn x\n\t}\n\treturn r.max(x.right)\n}\n\n// Floor returns the largest key/value in the sorted map that is smaller than\n// `k`.\nfunc (r RedBlack) Floor(key KType) (k KType, v VType, ok bool) {\n\tx := r.floor(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, x.val, true\n}\n\nfunc (r RedBlack) floor(h *mapnode, k KType) *mapnode {\n\tif h == nil {\n\t\treturn nil\n\t}\n\tcmp := r.compare(k, h.key)\n\tif cmp == 0 {\n\t\treturn h\n\t}\n\tif cmp < 0 {\n\t\treturn r.floor(h.left, k)\n\t}\n\tt := r.floor(h.right, k)\n\tif t != nil {\n\t\treturn t\n\t}\n\treturn h\n}\n\n// Ceiling returns the smallest key/value in the sorted map that is larger than\n// `k`.\nfunc (r RedBlack) Ceiling(key KType) (k KType, v VType, ok bool) {\n\tx := r.ceiling(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, x.val, true\n}\n\nfunc (r RedBlack) ceiling(h *mapnode, k KType) *mapnode {\n\tif h == nil {\n\t\treturn nil\n\t}\n\tcmp := r.compare(k, h.key)\n\tif cmp == 0 {\n\t\treturn h\n\t}\n\tif cmp > 0 {\n\t\treturn r.ceiling(h.right, k)\n\t}\n\tt := r.ceiling(h.left, k)\n\tif t != nil {\n\t\treturn t\n\t}\n\treturn h\n}\n\n// Select key of rank k, meaning the k-th biggest KType in the sorted map.\nfunc (r RedBlack) Select(key int) (k KType, v VType, ok bool) {\n\tx := r.nodeselect(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, x.val, true\n}\n\nfunc (r RedBlack) nodeselect(x *mapnode, k int) *mapnode {\n\tif x == nil {\n\t\treturn nil\n\t}\n\tt := x.left.size()\n\tif t > k {\n\t\treturn r.nodeselect(x.left, k)\n\t} else if t < k {\n\t\treturn r.nodeselect(x.right, k-t-1)\n\t} else {\n\t\treturn x\n\t}\n}\n\n// Rank is the number of keys less than `k`.\nfunc (r RedBlack) Rank(k KType) int {\n\treturn r.keyrank(k, r.root)\n}\n\nfunc (r RedBlack) keyrank(k KType, h *mapnode) int {\n\tif h == nil {\n\t\treturn 0\n\t}\n\tcmp := r.compare(k, h.key)\n\tif cmp < 0 {\n\t\treturn r.keyrank(k, h.left)\n\t} else if cmp > 0 {\n\t\treturn 1 + h.left.size() + r.keyrank(k, h.right)\n\t} else {\n\t\treturn h.left.size()\n\t}\n}\n\n// Keys visit each keys in the sorted map, in order.\n// It stops when visit returns false.\nfunc (r RedBlack) Keys(visit func(KType, VType) bool) {\n\tmin, _, ok := r.Min()\n\tif !ok {\n\t\treturn\n\t}\n\t// if the min exists, then the max must exist\n\tmax, _, _ := r.Max()\n\tr.RangedKeys(min, max, visit)\n}\n\n// RangedKeys visit each keys between lo and hi in the sorted map, in order.\n// It stops when visit returns false.\nfunc (r RedBlack) RangedKeys(lo, hi KType, visit func(KType, VType) bool) {\n\tr.keys(r.root, visit, lo, hi)\n}\n\nfunc (r RedBlack) keys(h *mapnode, visit func(KType, VType) bool, lo, hi KType) bool {\n\tif h == nil {\n\t\treturn true\n\t}\n\tcmplo := r.compare(lo, h.key)\n\tcmphi := r.compare(hi, h.key)\n\tif cmplo < 0 {\n\t\tif !r.keys(h.left, visit, lo, hi) {\n\t\t\treturn false\n\t\t}\n\t}\n\tif cmplo <= 0 && cmphi >= 0 {\n\t\tif !visit(h.key, h.val) {\n\t\t\treturn false\n\t\t}\n\t}\n\tif cmphi > 0 {\n\t\tif !r.keys(h.right, visit, lo, hi) {\n\t\t\treturn false\n\t\t}\n\t}\n\treturn true\n}\n\n// DeleteMin removes the smallest key and its value from the sorted map.\nfunc (r *RedBlack) DeleteMin() (oldk KType, oldv VType, ok bool) {\n\tr.root, oldk, oldv, ok = r.deleteMin(r.root)\n\tif !r.IsEmpty() {\n\t\tr.root.colorRed = false\n\t}\n\treturn\n}\n\nfunc (r *RedBlack) deleteMin(h *mapnode) (_ *mapnode, oldk KType, oldv VType, ok bool) {\n\tif h == nil {\n\t\treturn nil, oldk, oldv, false\n\t}\n\n\tif h.left == nil {\n\t\treturn nil, h.key, h.val, true\n\t}\n\tif !h.left.isRed() && !h.left.left.isRed() {\n\t\th = r.moveRedLeft(h)\n\t}\n\th.left, oldk, oldv, ok = r.deleteMin(h.left)\n\treturn r.balance(h), oldk, oldv, ok\n}\n\n// DeleteMax removes the largest key and its value from the sorted map.\nfunc (r *RedBlack) DeleteMax() (oldk KType, oldv VType, ok bool) {\n\tr.root, oldk, oldv, ok = r.deleteMax(r.root)\n\tif !r.IsEmpty() {\n\t\tr.root.colorRed = false\n\t}\n\treturn\n}\n\nfunc (r *RedBlack) deleteMax(h *mapnode) (_ *mapnode, oldk KType, oldv VType, ok bool) {\n\tif h == nil {\n\t\treturn nil, oldk, oldv, ok\n\t}\n\tif h.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\tif h.right == nil {\n\t\treturn nil, h.key, h.val, true\n\t}\n\tif !h.right.isRed() && !h.right.left.isRed() {\n\t\th = r.moveRedRight(h)\n\t}\n\th.right, oldk, oldv, ok = r.deleteMax(h.right)\n\treturn r.balance(h), oldk, oldv, ok\n}\n\n// Delete key `k` from sorted map, if it exists.\nfunc (r *RedBlack) Delete(k KType) (old VType, ok bool) {\n\tif r.root == nil {\n\t\treturn\n\t}\n\tr.root, old, ok = r.delete(r.root, k)\n\tif !r.IsEmpty() {\n\t\tr.root.colorRed = false\n\t}\n\treturn\n}\n\nfunc (r *RedBlack) delete(h *mapnode, k KType) (_ *mapnode, old VType, ok bool) {\n\n\tif h == nil {\n\t\treturn h, old, false\n\t}\n\n\tif r.compare(k, h.key) < 0 {\n\t\tif h.left == nil {\n\t\t\treturn h, old, false\n\t\t}\n\n\t\tif !h.left.isRed() && !h.left.left.isRed() {\n\t\t\th = r.moveRedLeft(h)\n\t\t}\n\n\t\th.left, old, ok = r.delete(h.left, k)\n\t\th = r.balance(h)\n\t\treturn h, old, ok\n\t}\n\n\tif h.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\n\tif r.compare(k, h.key) == 0 && h.right == nil {\n\t\treturn nil, h.val, true\n\t}\n\n\tif h.right != nil && !h.right.isRed() && !h.right.left.isRed() {\n\t\th = r.moveRedRight(h)\n\t}\n\n\tif r.compare(k, h.key) == 0 {\n\n\t\tvar subk KType\n\t\tvar subv VType\n\t\th.right, subk, subv, ok = r.deleteMin(h.right)\n\n\t\told, h.key, h.val = h.val, subk, subv\n\t\tok = true\n\t} else {\n\t\th.right, old, ok = r.delete(h.right, k)\n\t}\n\n\th = r.balance(h)\n\treturn h, old, ok\n}\n\n// deletions\n\nfunc (r *RedBlack) moveRedLeft(h *mapnode) *mapnode {\n\tr.flipColors(h)\n\tif h.right.left.isRed() {\n\t\th.right = r.rotateRight(h.right)\n\t\th = r.rotateLeft(h)\n\t\tr.flipColors(h)\n\t}\n\treturn h\n}\n\nfunc (r *RedBlack) moveRedRight(h *mapnode) *mapnode {\n\tr.flipColors(h)\n\tif h.left.left.isRed() {\n\t\th = r.rotateRight(h)\n\t\tr.flipColors(h)\n\t}\n\treturn h\n}\n\nfunc (r *RedBlack) balance(h *mapnode) *mapnode {\n\tif h.right.isRed() {\n\t\th = r.rotateLeft(h)\n\t}\n\tif h.left.isRed() && h.left.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\tif h.left.isRed() && h.right.isRed() {\n\t\tr.flipColors(h)\n\t}\n\th.n = h.left.size() + h.right.size() + 1\n\treturn h\n}\n\nfunc (r *RedBlack) rotateLeft(h *mapnode) *mapnode {\n\tx := h.right\n\th.right = x.left\n\tx.left = h\n\tx.colorRed = h.colorRed\n\th.colorRed = true\n\tx.n = h.n\n\th.n = 1 + h.left.size() + h.right.size()\n\treturn x\n}\n\nfunc (r *RedBlack) rotateRight(h *mapnode) *mapnode {\n\tx := h.left\n\th.left = x.right\n\tx.right = h\n\tx.colorRed = h.colorRed\n\th.colorRed = true\n\tx.n = h.n\n\th.n = 1 + h.left.size() + h.right.size()\n\treturn x\n}\n\nfunc (r *RedBlack) flipColors(h *mapnode) {\n\th.colorRed = !h.colorRed\n\th.left.colorRed = !h.left.colorRed\n\th.right.colorRed = !h.right.colorRed\n}\n\n// nodes\n\ntype mapnode struct {\n\tkey         KType\n\tval         VType\n\tleft, right *mapnode\n\tn           int\n\tcolorRed    bool\n}\n\nfunc (x *mapnode) isRed() bool { return (x != nil) && (x.colorRed == true) }\n\nfunc (x *mapnode) size() int {\n\tif x == nil {\n\t\treturn 0\n\t}\n\treturn x.n\n}\n"
-	redblackbstSetSrc = "package redblackbst\n\n// GENERATED CODE!!!\n\nfunc (r RedBlack) compare(a, b KType) int { return a.Compare(b) }\n\n// RedBlack is a sorted set built on a left leaning red black balanced\n// search sorted set. It stores unique KType values.\ntype RedBlack struct {\n\troot *treenode\n}\n\n// NewRedBlack creates a sorted set.\nfunc NewRedBlack() *RedBlack { return &RedBlack{} }\n\n// IsEmpty tells if the sorted set contains no key.\nfunc (r RedBlack) IsEmpty() bool {\n\treturn r.root == nil\n}\n\n// Size of the sorted set.\nfunc (r RedBlack) Size() int { return r.root.size() }\n\n// Clear all the values in the sorted set.\nfunc (r *RedBlack) Clear() { r.root = nil }\n\n// Put the key `k` in the sorted set. If the value was already there,\n// true is returned.\nfunc (r *RedBlack) Put(k KType) (already bool) {\n\tr.root, already = r.put(r.root, k)\n\treturn\n}\n\nfunc (r *RedBlack) put(h *treenode, k KType) (_ *treenode, already bool) {\n\tif h == nil {\n\t\tn := &treenode{key: k, n: 1, colorRed: true}\n\t\treturn n, already\n\t}\n\n\tcmp := r.compare(k, h.key)\n\tif cmp < 0 {\n\t\th.left, already = r.put(h.left, k)\n\t} else if cmp > 0 {\n\t\th.right, already = r.put(h.right, k)\n\t} else {\n\t\talready = true\n\t}\n\n\tif h.right.isRed() && !h.left.isRed() {\n\t\th = r.rotateLeft(h)\n\t}\n\tif h.left.isRed() && h.left.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\tif h.left.isRed() && h.right.isRed() {\n\t\tr.flipColors(h)\n\t}\n\th.n = h.left.size() + h.right.size() + 1\n\treturn h, already\n}\n\n// Contains tells if `k` is a member of the set.\nfunc (r RedBlack) Contains(k KType) bool {\n\treturn r.loopContains(r.root, k)\n}\n\nfunc (r RedBlack) loopContains(h *treenode, k KType) (ok bool) {\n\tfor h != nil {\n\t\tcmp := r.compare(k, h.key)\n\t\tif cmp == 0 {\n\t\t\treturn true\n\t\t} else if cmp < 0 {\n\t\t\th = h.left\n\t\t} else if cmp > 0 {\n\t\t\th = h.right\n\t\t}\n\t}\n\treturn\n}\n\n// Min returns the smallest key in the sorted set, if it exists.\nfunc (r RedBlack) Min() (k KType, ok bool) {\n\tif r.root == nil {\n\t\treturn\n\t}\n\th := r.min(r.root)\n\treturn h.key, true\n}\n\nfunc (r RedBlack) min(x *treenode) *treenode {\n\tif x.left == nil {\n\t\treturn x\n\t}\n\treturn r.min(x.left)\n}\n\n// Max returns the largest key in the sorted set, if it exists.\nfunc (r RedBlack) Max() (k KType, ok bool) {\n\tif r.root == nil {\n\t\treturn\n\t}\n\th := r.max(r.root)\n\treturn h.key, true\n}\n\nfunc (r RedBlack) max(x *treenode) *treenode {\n\tif x.right == nil {\n\t\treturn x\n\t}\n\treturn r.max(x.right)\n}\n\n// Floor returns the largest key in the sorted set that is smaller than\n// `k`.\nfunc (r RedBlack) Floor(key KType) (k KType, ok bool) {\n\tx := r.floor(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, true\n}\n\nfunc (r RedBlack) floor(h *treenode, k KType) *treenode {\n\tif h == nil {\n\t\treturn nil\n\t}\n\tcmp := r.compare(k, h.key)\n\tif cmp == 0 {\n\t\treturn h\n\t}\n\tif cmp < 0 {\n\t\treturn r.floor(h.left, k)\n\t}\n\tt := r.floor(h.right, k)\n\tif t != nil {\n\t\treturn t\n\t}\n\treturn h\n}\n\n// Ceiling returns the smallest key in the sorted set that is larger than\n// `k`.\nfunc (r RedBlack) Ceiling(key KType) (k KType, ok bool) {\n\tx := r.ceiling(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, true\n}\n\nfunc (r RedBlack) ceiling(h *treenode, k KType) *treenode {\n\tif h == nil {\n\t\treturn nil\n\t}\n\tcmp := r.compare(k, h.key)\n\tif cmp == 0 {\n\t\treturn h\n\t}\n\tif cmp > 0 {\n\t\treturn r.ceiling(h.right, k)\n\t}\n\tt := r.ceiling(h.left, k)\n\tif t != nil {\n\t\treturn t\n\t}\n\treturn h\n}\n\n// Select key of rank k, meaning the k-th biggest KType in the sorted set.\nfunc (r RedBlack) Select(key int) (k KType, ok bool) {\n\tx := r.nodeselect(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, true\n}\n\nfunc (r RedBlack) nodeselect(x *treenode, k int) *treenode {\n\tif x == nil {\n\t\treturn nil\n\t}\n\tt := x.left.size()\n\tif t > k {\n\t\treturn r.nodeselect(x.left, k)\n\t} else if t < k {\n\t\treturn r.nodeselect(x.right, k-t-1)\n\t} else {\n\t\treturn x\n\t}\n}\n\n// Rank is the number of keys less than `k`.\nfunc (r RedBlack) Rank(k KType) int {\n\treturn r.keyrank(k, r.root)\n}\n\nfunc (r RedBlack) keyrank(k KType, h *treenode) int {\n\tif h == nil {\n\t\treturn 0\n\t}\n\tcmp := r.compare(k, h.key)\n\tif cmp < 0 {\n\t\treturn r.keyrank(k, h.left)\n\t} else if cmp > 0 {\n\t\treturn 1 + h.left.size() + r.keyrank(k, h.right)\n\t} else {\n\t\treturn h.left.size()\n\t}\n}\n\n// Keys visit each keys in the sorted set, in order.\n// It stops when visit returns false.\nfunc (r RedBlack) Keys(visit func(KType) bool) {\n\tmin, ok := r.Min()\n\tif !ok {\n\t\treturn\n\t}\n\t// if the min exists, then the max must exist\n\tmax, _ := r.Max()\n\tr.RangedKeys(min, max, visit)\n}\n\n// RangedKeys visit each keys between lo and hi in the sorted set, in order.\n// It stops when visit returns false.\nfunc (r RedBlack) RangedKeys(lo, hi KType, visit func(KType) bool) {\n\tr.keys(r.root, visit, lo, hi)\n}\n\nfunc (r RedBlack) keys(h *treenode, visit func(KType) bool, lo, hi KType) bool {\n\tif h == nil {\n\t\treturn true\n\t}\n\tcmplo := r.compare(lo, h.key)\n\tcmphi := r.compare(hi, h.key)\n\tif cmplo < 0 {\n\t\tif !r.keys(h.left, visit, lo, hi) {\n\t\t\treturn false\n\t\t}\n\t}\n\tif cmplo <= 0 && cmphi >= 0 {\n\t\tif !visit(h.key) {\n\t\t\treturn false\n\t\t}\n\t}\n\tif cmphi > 0 {\n\t\tif !r.keys(h.right, visit, lo, hi) {\n\t\t\treturn false\n\t\t}\n\t}\n\treturn true\n}\n\n// DeleteMin removes the smallest key from the sorted set.\nfunc (r *RedBlack) DeleteMin() (oldk KType, ok bool) {\n\tr.root, oldk, ok = r.deleteMin(r.root)\n\tif !r.IsEmpty() {\n\t\tr.root.colorRed = false\n\t}\n\treturn\n}\n\nfunc (r *RedBlack) deleteMin(h *treenode) (_ *treenode, oldk KType, ok bool) {\n\tif h == nil {\n\t\treturn nil, oldk, false\n\t}\n\n\tif h.left == nil {\n\t\treturn nil, h.key, true\n\t}\n\tif !h.left.isRed() && !h.left.left.isRed() {\n\t\th = r.moveRedLeft(h)\n\t}\n\th.left, oldk, ok = r.deleteMin(h.left)\n\treturn r.balance(h), oldk, ok\n}\n\n// DeleteMax removes the largest key from the sorted set.\nfunc (r *RedBlack) DeleteMax() (oldk KType, ok bool) {\n\tr.root, oldk, ok = r.deleteMax(r.root)\n\tif !r.IsEmpty() {\n\t\tr.root.colorRed = false\n\t}\n\treturn\n}\n\nfunc (r *RedBlack) deleteMax(h *treenode) (_ *treenode, oldk KType, ok bool) {\n\tif h == nil {\n\t\treturn nil, oldk, ok\n\t}\n\tif h.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\tif h.right == nil {\n\t\treturn nil, h.key, true\n\t}\n\tif !h.right.isRed() && !h.right.left.isRed() {\n\t\th = r.moveRedRight(h)\n\t}\n\th.right, oldk, ok = r.deleteMax(h.right)\n\treturn r.balance(h), oldk, ok\n}\n\n// Delete key `k` from sorted set, if it exists.\nfunc (r *RedBlack) Delete(k KType) (ok bool) {\n\tif r.root == nil {\n\t\treturn\n\t}\n\tr.root, ok = r.delete(r.root, k)\n\tif !r.IsEmpty() {\n\t\tr.root.colorRed = false\n\t}\n\treturn\n}\n\nfunc (r *RedBlack) delete(h *treenode, k KType) (_ *treenode, ok bool) {\n\n\tif h == nil {\n\t\treturn h, false\n\t}\n\n\tif r.compare(k, h.key) < 0 {\n\t\tif h.left == nil {\n\t\t\treturn h, false\n\t\t}\n\n\t\tif !h.left.isRed() && !h.left.left.isRed() {\n\t\t\th = r.moveRedLeft(h)\n\t\t}\n\n\t\th.left, ok = r.delete(h.left, k)\n\t\th = r.balance(h)\n\t\treturn h, ok\n\t}\n\n\tif h.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\n\tif r.compare(k, h.key) == 0 && h.right == nil {\n\t\treturn nil, true\n\t}\n\n\tif h.right != nil && !h.right.isRed() && !h.right.left.isRed() {\n\t\th = r.moveRedRight(h)\n\t}\n\n\tif r.compare(k, h.key) == 0 {\n\n\t\tvar subk KType\n\t\th.right, subk, ok = r.deleteMin(h.right)\n\t\th.key = subk\n\t\tok = true\n\t} else {\n\t\th.right, ok = r.delete(h.right, k)\n\t}\n\n\th = r.balance(h)\n\treturn h, ok\n}\n\n// deletions\n\nfunc (r *RedBlack) moveRedLeft(h *treenode) *treenode {\n\tr.flipColors(h)\n\tif h.right.left.isRed() {\n\t\th.right = r.rotateRight(h.right)\n\t\th = r.rotateLeft(h)\n\t\tr.flipColors(h)\n\t}\n\treturn h\n}\n\nfunc (r *RedBlack) moveRedRight(h *treenode) *treenode {\n\tr.flipColors(h)\n\tif h.left.left.isRed() {\n\t\th = r.rotateRight(h)\n\t\tr.flipColors(h)\n\t}\n\treturn h\n}\n\nfunc (r *RedBlack) balance(h *treenode) *treenode {\n\tif h.right.isRed() {\n\t\th = r.rotateLeft(h)\n\t}\n\tif h.left.isRed() && h.left.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\tif h.left.isRed() && h.right.isRed() {\n\t\tr.flipColors(h)\n\t}\n\th.n = h.left.size() + h.right.size() + 1\n\treturn h\n}\n\nfunc (r *RedBlack) rotateLeft(h *treenode) *treenode {\n\tx := h.right\n\th.right = x.left\n\tx.left = h\n\tx.colorRed = h.colorRed\n\th.colorRed = true\n\tx.n = h.n\n\th.n = 1 + h.left.size() + h.right.size()\n\treturn x\n}\n\nfunc (r *RedBlack) rotateRight(h *treenode) *treenode {\n\tx := h.left\n\th.left = x.right\n\tx.right = h\n\tx.colorRed = h.colorRed\n\th.colorRed = true\n\tx.n = h.n\n\th.n = 1 + h.left.size() + h.right.size()\n\treturn x\n}\n\nfunc (r *RedBlack) flipColors(h *treenode) {\n\th.colorRed = !h.colorRed\n\th.left.colorRed = !h.left.colorRed\n\th.right.colorRed = !h.right.colorRed\n}\n\n// nodes\n\ntype treenode struct {\n\tkey         KType\n\tleft, right *treenode\n\tn           int\n\tcolorRed    bool\n}\n\nfunc (x *treenode) isRed() bool { return (x != nil) && (x.colorRed == true) }\n\nfunc (x *treenode) size() int {\n\tif x == nil {\n\t\treturn 0\n\t}\n\treturn x.n\n}\n"
-	heapSrc           = "package heap\n\n// GENERATED CODE!!!\n\n// Most of the implementation is adapted from Algorithms 4ed by Sedgewick\n// and Wayne.\n\n// Comments are adapted from `container/heap`.\n// \t Copyright 2009 The Go Authors. All rights reserved.\n// \t Use of this source code is governed by a BSD-style\n// \t license that can be found in the LICENSE file.\n\nfunc (h Heap) compare(a, b KType) int { return a.Compare(b) }\n\n// Heap is a container of KType, where the elements can be efficiently\n// retrieved in their decreasing order (according to their comparison\n// rules).\ntype Heap struct {\n\tn  int\n\tpq []KType\n}\n\n// NewHeap creates a heap, optionaly with keys already populating\n// it. The complexity is O(n) where n = len(keys).\nfunc NewHeap(keys ...KType) *Heap {\n\th := &Heap{\n\t\tn:  len(keys),\n\t\tpq: append(make([]KType, 1), keys...),\n\t}\n\th.Fix()\n\treturn h\n}\n\n// Len is the number of elements stored in the heap.\nfunc (h *Heap) Len() int { return h.n }\n\n// Peek at the largest element (according to their comparison rules), without\n// removing it from the heap.\nfunc (h *Heap) Peek() KType { return h.pq[1] }\n\n// Fix re-establishes the heap ordering. This is useful if elements\n// of the heap have had their comparison value changed. It is equivalent to,\n// but less expenasive than, Pop'ing all the elements and Push'ing them\n// again.\n// The complexity is O(n).\nfunc (h *Heap) Fix() {\n\tfor i := (h.n) / 2; i > 0; i-- {\n\t\th.sink(i, h.n)\n\t}\n}\n\n// Push pushes the element k onto the heap. The complexity is\n// O(log(n)) where n == h.Len().\nfunc (h *Heap) Push(k KType) {\n\th.n++\n\th.pq = append(h.pq, k)\n\th.swim(h.n)\n}\n\n// Pop removes the largest element (according to their comparison rules) from\n// the heap and returns it. The complexity is O(log(n)) where n == h.Len().\nfunc (h *Heap) Pop() KType {\n\tval := h.pq[1]\n\th.swap(1, h.n)\n\th.pq = h.pq[:h.n]\n\th.n--\n\th.sink(1, h.n)\n\n\treturn val\n}\n\n// Remove removes k from the heap, if it exists. Equality is defined by\n// Compare == 0.\n// The complexity is O(n+log(n)) where n == h.Len().\nfunc (h *Heap) Remove(k KType) bool {\n\n\tcmp := h.compare(h.pq[1], k)\n\tif cmp == 0 {\n\t\t_ = h.Pop()\n\t\treturn true\n\t}\n\tif cmp < 0 {\n\t\t// larger than largest, don't try to find it\n\t\treturn false\n\t}\n\n\ti := 0\n\tfor _, j := range h.pq[1:] {\n\t\ti++\n\t\tif h.compare(j, k) != 0 {\n\t\t\tcontinue\n\t\t}\n\t\th.swap(i, 1)\n\t\th.sink(i, h.n)\n\t\t_ = h.Pop()\n\t\treturn true\n\t}\n\t// not in the heap\n\treturn false\n}\n\nfunc (h *Heap) swap(i, j int)      { h.pq[i], h.pq[j] = h.pq[j], h.pq[i] }\nfunc (h *Heap) less(i, j int) bool { return h.compare(h.pq[i], h.pq[j]) < 0 }\n\nfunc (h *Heap) swim(k int) {\n\tfor k > 1 && h.less(k/2, k) {\n\t\th.swap(k/2, k)\n\t\tk = k / 2\n\t}\n}\n\nfunc (h *Heap) sink(k, n int) {\n\n\tfor k*2 <= n {\n\t\tj := 2 * k\n\t\tif j < n && h.less(j, j+1) {\n\t\t\tj++\n\t\t}\n\t\tif !h.less(k, j) {\n\t\t\tbreak\n\t\t}\n\t\th.swap(k, j)\n\t\tk = j\n\t}\n}\n"
-	queueSrc          = "package queue\n\n// GENERATED CODE!!!\n\n// Implementation adapted from github.com/eapache/queue:\n//    The MIT License (MIT)\n//    Copyright (c) 2014 Evan Huus\n\nvar nilKType KType\n\n// Queue represents a single instance of the queue data structure.\ntype Queue struct {\n\tbuf               []KType\n\thead, tail, count int\n\tminlen            int\n}\n\n// NewQueue constructs and returns a new Queue with an initial capacity.\nfunc NewQueue(capacity int) *Queue {\n\t// min capacity of 16\n\tif capacity < 16 {\n\t\tcapacity = 16\n\t}\n\treturn &Queue{buf: make([]KType, capacity), minlen: capacity}\n}\n\n// Len returns the number of elements currently stored in the queue.\nfunc (q *Queue) Len() int {\n\treturn q.count\n}\n\n// Push puts an element on the end of the queue.\nfunc (q *Queue) Push(elem KType) {\n\tif q.count == len(q.buf) {\n\t\tq.resize()\n\t}\n\n\tq.buf[q.tail] = elem\n\tq.tail = (q.tail + 1) % len(q.buf)\n\tq.count++\n}\n\n// Peek returns the element at the head of the queue. This call panics\n// if the queue is empty.\nfunc (q *Queue) Peek() KType {\n\tif q.Len() <= 0 {\n\t\tpanic(\"queue: empty queue\")\n\t}\n\treturn q.buf[q.head]\n}\n\n// Get returns the element at index i in the queue. If the index is\n// invalid, the call will panic.\nfunc (q *Queue) Get(i int) KType {\n\tif i >= q.Len() || i < 0 {\n\t\tpanic(\"queue: index out of range\")\n\t}\n\tmodi := (q.head + i) % len(q.buf)\n\treturn q.buf[modi]\n}\n\n// Pop removes the element from the front of the queue.\n// This call panics if the queue is empty.\nfunc (q *Queue) Pop() KType {\n\tif q.Len() <= 0 {\n\t\tpanic(\"queue: empty queue\")\n\t}\n\tv := q.buf[q.head]\n\t// set to nil to avoid keeping reference to objects\n\t// that would otherwise be garbage collected\n\tq.buf[q.head] = nilKType\n\tq.head = (q.head + 1) % len(q.buf)\n\tq.count--\n\tif len(q.buf) > q.minlen && q.count*4 <= len(q.buf) {\n\t\tq.resize()\n\t}\n\treturn v\n}\n\nfunc (q *Queue) resize() {\n\tnewBuf := make([]KType, q.count*2)\n\n\tif q.tail > q.head {\n\t\tcopy(newBuf, q.buf[q.head:q.tail])\n\t} else {\n\t\tcopy(newBuf, q.buf[q.head:len(q.buf)])\n\t\tcopy(newBuf[len(q.buf)-q.head:], q.buf[:q.tail])\n\t}\n\n\tq.head = 0\n\tq.tail = q.count\n\tq.buf = newBuf\n}\n"
+	redblackbstMapSrc         = "package redblackbst\n\n// GENERATED CODE!!!\n\nimport \"math/bits\"\n\nfunc (r RedBlack) compare(a, b KType) int { return a.Compare(b) }\n\n// RedBlack is a sorted map built on a left leaning red black balanced\n// search sorted map. It stores VType values, keyed by KType.\ntype RedBlack struct {\n\troot *mapnode\n}\n\n// NewRedBlack creates a sorted map.\nfunc NewRedBlack() *RedBlack { return &RedBlack{} }\n\n// IsEmpty tells if the sorted map contains no key/value.\nfunc (r RedBlack) IsEmpty() bool {\n\treturn r.root == nil\n}\n\n// Size of the sorted map.\nfunc (r RedBlack) Size() int { return r.root.size() }\n\n// Clear all the values in the sorted map.\nfunc (r *RedBlack) Clear() { r.root = nil }\n\n// Put a value in the sorted map at key `k`. The old value at `k` is returned\n// if the key was already present.\nfunc (r *RedBlack) Put(k KType, v VType) (old VType, overwrite bool) {\n\tr.root, old, overwrite = r.put(r.root, k, func() VType { return v }, func(_ VType) VType { return v })\n\treturn\n}\n\n// Mutate is like a Put when `k` isn't defined, but allows you to create or mutate the value found at the location of `k`.\nfunc (r *RedBlack) Mutate(k KType, creator func() VType, mutator func(old VType) VType) {\n\tr.root, _, _ = r.put(r.root, k, creator, mutator)\n}\n\nfunc (r *RedBlack) put(h *mapnode, k KType, create func() VType, mutate func(old VType) VType) (_ *mapnode, old VType, overwrite bool) {\n\tif h == nil {\n\t\tn := &mapnode{key: k, val: create(), n: 1, colorRed: true}\n\t\treturn n, old, overwrite\n\t}\n\n\tcmp := r.compare(k, h.key)\n\tif cmp < 0 {\n\t\th.left, old, overwrite = r.put(h.left, k, create, mutate)\n\t} else if cmp > 0 {\n\t\th.right, old, overwrite = r.put(h.right, k, create, mutate)\n\t} else {\n\t\toverwrite = true\n\t\told = h.val\n\t\th.val = mutate(old)\n\t}\n\n\tif h.right.isRed() && !h.left.isRed() {\n\t\th = r.rotateLeft(h)\n\t}\n\tif h.left.isRed() && h.left.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\tif h.left.isRed() && h.right.isRed() {\n\t\tr.flipColors(h)\n\t}\n\th.n = h.left.size() + h.right.size() + 1\n\treturn h, old, overwrite\n}\n\n// Get a value from the sorted map at key `k`. Returns false\n// if the key doesn't exist.\nfunc (r RedBlack) Get(k KType) (VType, bool) {\n\treturn r.loopGet(r.root, k)\n}\n\nfunc (r RedBlack) loopGet(h *mapnode, k KType) (v VType, ok bool) {\n\tfor h != nil {\n\t\tcmp := r.compare(k, h.key)\n\t\tif cmp == 0 {\n\t\t\treturn h.val, true\n\t\t} else if cmp < 0 {\n\t\t\th = h.left\n\t\t} else if cmp > 0 {\n\t\t\th = h.right\n\t\t}\n\t}\n\treturn\n}\n\n// Has tells if a value exists at key `k`. This is short hand for `Get.\nfunc (r RedBlack) Has(k KType) bool {\n\t_, ok := r.loopGet(r.root, k)\n\treturn ok\n}\n\n// Min returns the smallest key/value in the sorted map, if it exists.\nfunc (r RedBlack) Min() (k KType, v VType, ok bool) {\n\tif r.root == nil {\n\t\treturn\n\t}\n\th := r.min(r.root)\n\treturn h.key, h.val, true\n}\n\nfunc (r RedBlack) min(x *mapnode) *mapnode {\n\tif x.left == nil {\n\t\treturn x\n\t}\n\treturn r.min(x.left)\n}\n\n// Max returns the largest key/value in the sorted map, if it exists.\nfunc (r RedBlack) Max() (k KType, v VType, ok bool) {\n\tif r.root == nil {\n\t\treturn\n\t}\n\th := r.max(r.root)\n\treturn h.key, h.val, true\n}\n\nfunc (r RedBlack) max(x *mapnode) *mapnode {\n\tif x.right == nil {\n\t\treturn x\n\t}\n\treturn r.max(x.right)\n}\n\n// Floor returns the largest key/value in the sorted map that is smaller than\n// or equal to `k`.\nfunc (r RedBlack) Floor(key KType) (k KType, v VType, ok bool) {\n\tx := r.floor(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, x.val, true\n}\n\nfunc (r RedBlack) floor(h *mapnode, k KType) *mapnode {\n\tif h == nil {\n\t\treturn nil\n\t}\n\tcmp := r.compare(k, h.key)\n\tif cmp == 0 {\n\t\treturn h\n\t}\n\tif cmp < 0 {\n\t\treturn r.floor(h.left, k)\n\t}\n\tt := r.floor(h.right, k)\n\tif t != nil {\n\t\treturn t\n\t}\n\treturn h\n}\n\n// Ceiling returns the smallest key/value in the sorted map that is larger than\n// or equal to `k`.\nfunc (r RedBlack) Ceiling(key KType) (k KType, v VType, ok bool) {\n\tx := r.ceiling(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, x.val, true\n}\n\nfunc (r RedBlack) ceiling(h *mapnode, k KType) *mapnode {\n\tif h == nil {\n\t\treturn nil\n\t}\n\tcmp := r.compare(k, h.key)\n\tif cmp == 0 {\n\t\treturn h\n\t}\n\tif cmp > 0 {\n\t\treturn r.ceiling(h.right, k)\n\t}\n\tt := r.ceiling(h.left, k)\n\tif t != nil {\n\t\treturn t\n\t}\n\treturn h\n}\n\n// Lower returns the largest key/value in the sorted map that is strictly\n// smaller than `k`.\nfunc (r RedBlack) Lower(key KType) (k KType, v VType, ok bool) {\n\tx := r.lower(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, x.val, true\n}\n\nfunc (r RedBlack) lower(h *mapnode, k KType) *mapnode {\n\tif h == nil {\n\t\treturn nil\n\t}\n\tif r.compare(k, h.key) <= 0 {\n\t\treturn r.lower(h.left, k)\n\t}\n\tt := r.lower(h.right, k)\n\tif t != nil {\n\t\treturn t\n\t}\n\treturn h\n}\n\n// Higher returns the smallest key/value in the sorted map that is strictly\n// larger than `k`.\nfunc (r RedBlack) Higher(key KType) (k KType, v VType, ok bool) {\n\tx := r.higher(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, x.val, true\n}\n\nfunc (r RedBlack) higher(h *mapnode, k KType) *mapnode {\n\tif h == nil {\n\t\treturn nil\n\t}\n\tif r.compare(k, h.key) >= 0 {\n\t\treturn r.higher(h.right, k)\n\t}\n\tt := r.higher(h.left, k)\n\tif t != nil {\n\t\treturn t\n\t}\n\treturn h\n}\n\n// FloorRank returns the rank of the floor of `k`, if there's one. Its\n// neighbours in the sorted map are at the next and previous ranks, which\n// Select finds.\nfunc (r RedBlack) FloorRank(key KType) (rank int, ok bool) {\n\tx := r.floor(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn r.keyrank(x.key, r.root), true\n}\n\n// CeilingRank returns the rank of the ceiling of `k`, if there's one. Its\n// neighbours in the sorted map are at the next and previous ranks, which\n// Select finds.\nfunc (r RedBlack) CeilingRank(key KType) (rank int, ok bool) {\n\trank = r.keyrank(key, r.root)\n\treturn rank, rank < r.Size()\n}\n\n// Select key of rank k, meaning the k-th biggest KType in the sorted map.\nfunc (r RedBlack) Select(key int) (k KType, v VType, ok bool) {\n\tx := r.nodeselect(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, x.val, true\n}\n\nfunc (r RedBlack) nodeselect(x *mapnode, k int) *mapnode {\n\tif x == nil {\n\t\treturn nil\n\t}\n\tt := x.left.size()\n\tif t > k {\n\t\treturn r.nodeselect(x.left, k)\n\t} else if t < k {\n\t\treturn r.nodeselect(x.right, k-t-1)\n\t} else {\n\t\treturn x\n\t}\n}\n\n// Rank is the number of keys less than `k`.\nfunc (r RedBlack) Rank(k KType) int {\n\treturn r.keyrank(k, r.root)\n}\n\nfunc (r RedBlack) keyrank(k KType, h *mapnode) int {\n\tif h == nil {\n\t\treturn 0\n\t}\n\tcmp := r.compare(k, h.key)\n\tif cmp < 0 {\n\t\treturn r.keyrank(k, h.left)\n\t} else if cmp > 0 {\n\t\treturn 1 + h.left.size() + r.keyrank(k, h.right)\n\t} else {\n\t\treturn h.left.size()\n\t}\n}\n\n// CountRange returns the number of keys between lo and hi in the sorted\n// map, which it counts from their ranks in O(log n).\nfunc (r RedBlack) CountRange(lo, hi KType) int {\n\tif r.compare(lo, hi) > 0 {\n\t\treturn 0\n\t}\n\tcount := r.keyrank(hi, r.root) - r.keyrank(lo, r.root)\n\tif r.Has(hi) {\n\t\tcount++\n\t}\n\treturn count\n}\n\n// Keys visit each keys in the sorted map, in order.\n// It stops when visit returns false.\nfunc (r RedBlack) Keys(visit func(KType, VType) bool) {\n\tmin, _, ok := r.Min()\n\tif !ok {\n\t\treturn\n\t}\n\t// if the min exists, then the max must exist\n\tmax, _, _ := r.Max()\n\tr.RangedKeys(min, max, visit)\n}\n\n// RangedKeys visit each keys between lo and hi in the sorted map, in order.\n// It stops when visit returns false.\nfunc (r RedBlack) RangedKeys(lo, hi KType, visit func(KType, VType) bool) {\n\tr.keys(r.root, visit, lo, hi)\n}\n\nfunc (r RedBlack) keys(h *mapnode, visit func(KType, VType) bool, lo, hi KType) bool {\n\tif h == nil {\n\t\treturn true\n\t}\n\tcmplo := r.compare(lo, h.key)\n\tcmphi := r.compare(hi, h.key)\n\tif cmplo < 0 {\n\t\tif !r.keys(h.left, visit, lo, hi) {\n\t\t\treturn false\n\t\t}\n\t}\n\tif cmplo <= 0 && cmphi >= 0 {\n\t\tif !visit(h.key, h.val) {\n\t\t\treturn false\n\t\t}\n\t}\n\tif cmphi > 0 {\n\t\tif !r.keys(h.right, visit, lo, hi) {\n\t\t\treturn false\n\t\t}\n\t}\n\treturn true\n}\n\n// KeysBetween visit each keys between lo and hi in the sorted map, in\n// order, like RangedKeys, but leaves lo out unless loInclusive and hi out\n// unless hiInclusive: `KeysBetween(lo, true, hi, false, visit)` visits the\n// keys of [lo, hi). It stops when visit returns false.\nfunc (r RedBlack) KeysBetween(lo KType, loInclusive bool, hi KType, hiInclusive bool, visit func(KType, VType) bool) {\n\tr.bounded(r.root, visit, r.above(lo, loInclusive), r.below(hi, hiInclusive), false)\n}\n\n// KeysAbove visit each keys larger than lo in the sorted map, or equal to\n// it if inclusive, in order. It stops when visit returns false.\nfunc (r RedBlack) KeysAbove(lo KType, inclusive bool, visit func(KType, VType) bool) {\n\tr.bounded(r.root, visit, r.above(lo, inclusive), r.unbounded, false)\n}\n\n// KeysBelow visit each keys smaller than hi in the sorted map, or equal to\n// it if inclusive, in order. It stops when visit returns false.\nfunc (r RedBlack) KeysBelow(hi KType, inclusive bool, visit func(KType, VType) bool) {\n\tr.bounded(r.root, visit, r.unbounded, r.below(hi, inclusive), false)\n}\n\n// ReverseKeys visit each keys in the sorted map, in reverse order.\n// It stops when visit returns false.\nfunc (r RedBlack) ReverseKeys(visit func(KType, VType) bool) {\n\tr.bounded(r.root, visit, r.unbounded, r.unbounded, true)\n}\n\n// ReverseRangedKeys visit each keys between lo and hi in the sorted map,\n// in reverse order, from hi down to lo. It stops when visit returns false.\nfunc (r RedBlack) ReverseRangedKeys(lo, hi KType, visit func(KType, VType) bool) {\n\tr.bounded(r.root, visit, r.above(lo, true), r.below(hi, true), true)\n}\n\n// ReverseKeysBetween is KeysBetween, visiting the keys in reverse order.\nfunc (r RedBlack) ReverseKeysBetween(lo KType, loInclusive bool, hi KType, hiInclusive bool, visit func(KType, VType) bool) {\n\tr.bounded(r.root, visit, r.above(lo, loInclusive), r.below(hi, hiInclusive), true)\n}\n\n// ReverseKeysAbove is KeysAbove, visiting the keys in reverse order.\nfunc (r RedBlack) ReverseKeysAbove(lo KType, inclusive bool, visit func(KType, VType) bool) {\n\tr.bounded(r.root, visit, r.above(lo, inclusive), r.unbounded, true)\n}\n\n// ReverseKeysBelow is KeysBelow, visiting the keys in reverse order, like\n// the latest keys before hi.\nfunc (r RedBlack) ReverseKeysBelow(hi KType, inclusive bool, visit func(KType, VType) bool) {\n\tr.bounded(r.root, visit, r.unbounded, r.below(hi, inclusive), true)\n}\n\n// bounded visits the keys of h within bounds, in order or in reverse.\n// aboveLo and belowHi tell if a key is within the lower and the upper bound,\n// which are only crossed once going through the keys in order.\nfunc (r RedBlack) bounded(h *mapnode, visit func(KType, VType) bool, aboveLo, belowHi func(KType) bool, reverse bool) bool {\n\tif h == nil {\n\t\treturn true\n\t}\n\tinLo, inHi := aboveLo(h.key), belowHi(h.key)\n\t// the keys of the left subtree are all out of bounds when h is below\n\t// lo, and those of the right subtree when h is above hi\n\tfirst, last := h.left, h.right\n\tinFirst, inLast := inLo, inHi\n\tif reverse {\n\t\tfirst, last = last, first\n\t\tinFirst, inLast = inLast, inFirst\n\t}\n\tif inFirst && !r.bounded(first, visit, aboveLo, belowHi, reverse) {\n\t\treturn false\n\t}\n\tif inLo && inHi && !visit(h.key, h.val) {\n\t\treturn false\n\t}\n\tif inLast && !r.bounded(last, visit, aboveLo, belowHi, reverse) {\n\t\treturn false\n\t}\n\treturn true\n}\n\n// above returns the lower bound lo, which keys are within when they're\n// larger than lo, or equal to it if inclusive.\nfunc (r RedBlack) above(lo KType, inclusive bool) func(KType) bool {\n\treturn func(k KType) bool {\n\t\tcmp := r.compare(k, lo)\n\t\treturn cmp > 0 || inclusive && cmp == 0\n\t}\n}\n\n// below returns the upper bound hi, which keys are within when they're\n// smaller than hi, or equal to it if inclusive.\nfunc (r RedBlack) below(hi KType, inclusive bool) func(KType) bool {\n\treturn func(k KType) bool {\n\t\tcmp := r.compare(k, hi)\n\t\treturn cmp < 0 || inclusive && cmp == 0\n\t}\n}\n\n// unbounded is the bound of an open end, which all keys are within.\nfunc (r RedBlack) unbounded(KType) bool { return true }\n\n// Iterator returns an iterator over the keys/values of the sorted map, in\n// order. It isn't positioned yet: Next moves it to the smallest key, and\n// Prev to the largest one.\nfunc (r *RedBlack) Iterator() *RedBlackIterator {\n\treturn &RedBlackIterator{tree: r}\n}\n\n// RedBlackIterator is a cursor over the keys/values of a sorted map, which\n// moves in either direction. Putting a new key in the sorted map or\n// deleting one invalidates its iterators: seek the last key seen to\n// resume.\ntype RedBlackIterator struct {\n\ttree *RedBlack\n\t// path from the root to the node of the current key, empty when the\n\t// iterator isn't positioned at a key.\n\tpath []*mapnode\n\t// done once the iterator moved past either end of the sorted map. It\n\t// stays there until it's positioned again.\n\tdone bool\n}\n\n// First moves the iterator to the smallest key, and tells if there's one.\nfunc (it *RedBlackIterator) First() bool {\n\tit.path, it.done = it.path[:0], false\n\tit.pushLeft(it.tree.root)\n\treturn it.Valid()\n}\n\n// Last moves the iterator to the largest key, and tells if there's one.\nfunc (it *RedBlackIterator) Last() bool {\n\tit.path, it.done = it.path[:0], false\n\tit.pushRight(it.tree.root)\n\treturn it.Valid()\n}\n\n// SeekCeiling moves the iterator to the smallest key larger than or equal\n// to `k`, and tells if there's one.\nfunc (it *RedBlackIterator) SeekCeiling(k KType) bool {\n\tit.path, it.done = it.path[:0], false\n\t// the key sought is the last one of the path to `k` that's larger\n\tceiling := 0\n\tfor h := it.tree.root; h != nil; {\n\t\tit.path = append(it.path, h)\n\t\tcmp := it.tree.compare(k, h.key)\n\t\tif cmp == 0 {\n\t\t\treturn true\n\t\t} else if cmp < 0 {\n\t\t\tceiling = len(it.path)\n\t\t\th = h.left\n\t\t} else {\n\t\t\th = h.right\n\t\t}\n\t}\n\tit.path, it.done = it.path[:ceiling], ceiling == 0\n\treturn it.Valid()\n}\n\n// SeekFloor moves the iterator to the largest key smaller than or equal\n// to `k`, and tells if there's one.\nfunc (it *RedBlackIterator) SeekFloor(k KType) bool {\n\tit.path, it.done = it.path[:0], false\n\t// the key sought is the last one of the path to `k` that's smaller\n\tfloor := 0\n\tfor h := it.tree.root; h != nil; {\n\t\tit.path = append(it.path, h)\n\t\tcmp := it.tree.compare(k, h.key)\n\t\tif cmp == 0 {\n\t\t\treturn true\n\t\t} else if cmp > 0 {\n\t\t\tfloor = len(it.path)\n\t\t\th = h.right\n\t\t} else {\n\t\t\th = h.left\n\t\t}\n\t}\n\tit.path, it.done = it.path[:floor], floor == 0\n\treturn it.Valid()\n}\n\n// Next moves the iterator to the next key, and tells if there's one.\nfunc (it *RedBlackIterator) Next() bool {\n\tif it.done {\n\t\treturn false\n\t}\n\tif !it.Valid() {\n\t\treturn it.First()\n\t}\n\tif h := it.path[len(it.path)-1]; h.right != nil {\n\t\tit.pushLeft(h.right)\n\t\treturn true\n\t}\n\t// the next key is the first ancestor found on the right\n\tfor len(it.path) > 1 {\n\t\tchild := it.path[len(it.path)-1]\n\t\tit.path = it.path[:len(it.path)-1]\n\t\tif it.path[len(it.path)-1].left == child {\n\t\t\treturn true\n\t\t}\n\t}\n\tit.path, it.done = it.path[:0], true\n\treturn false\n}\n\n// Prev moves the iterator to the previous key, and tells if there's one.\nfunc (it *RedBlackIterator) Prev() bool {\n\tif it.done {\n\t\treturn false\n\t}\n\tif !it.Valid() {\n\t\treturn it.Last()\n\t}\n\tif h := it.path[len(it.path)-1]; h.left != nil {\n\t\tit.pushRight(h.left)\n\t\treturn true\n\t}\n\t// the previous key is the first ancestor found on the left\n\tfor len(it.path) > 1 {\n\t\tchild := it.path[len(it.path)-1]\n\t\tit.path = it.path[:len(it.path)-1]\n\t\tif it.path[len(it.path)-1].right == child {\n\t\t\treturn true\n\t\t}\n\t}\n\tit.path, it.done = it.path[:0], true\n\treturn false\n}\n\n// Valid tells if the iterator is positioned at a key.\nfunc (it *RedBlackIterator) Valid() bool { return len(it.path) > 0 }\n\n// Key the iterator is positioned at. It panics if the iterator isn't\n// valid.\nfunc (it *RedBlackIterator) Key() KType { return it.path[len(it.path)-1].key }\n\n// Value of the key the iterator is positioned at. It panics if the\n// iterator isn't valid.\nfunc (it *RedBlackIterator) Value() VType { return it.path[len(it.path)-1].val }\n\nfunc (it *RedBlackIterator) pushLeft(h *mapnode) {\n\tfor ; h != nil; h = h.left {\n\t\tit.path = append(it.path, h)\n\t}\n}\n\nfunc (it *RedBlackIterator) pushRight(h *mapnode) {\n\tfor ; h != nil; h = h.right {\n\t\tit.path = append(it.path, h)\n\t}\n}\n\n// DeleteMin removes the smallest key and its value from the sorted map.\nfunc (r *RedBlack) DeleteMin() (oldk KType, oldv VType, ok bool) {\n\tr.root, oldk, oldv, ok = r.deleteMin(r.root)\n\tif !r.IsEmpty() {\n\t\tr.root.colorRed = false\n\t}\n\treturn\n}\n\nfunc (r *RedBlack) deleteMin(h *mapnode) (_ *mapnode, oldk KType, oldv VType, ok bool) {\n\tif h == nil {\n\t\treturn nil, oldk, oldv, false\n\t}\n\n\tif h.left == nil {\n\t\treturn nil, h.key, h.val, true\n\t}\n\tif !h.left.isRed() && !h.left.left.isRed() {\n\t\th = r.moveRedLeft(h)\n\t}\n\th.left, oldk, oldv, ok = r.deleteMin(h.left)\n\treturn r.balance(h), oldk, oldv, ok\n}\n\n// DeleteMax removes the largest key and its value from the sorted map.\nfunc (r *RedBlack) DeleteMax() (oldk KType, oldv VType, ok bool) {\n\tr.root, oldk, oldv, ok = r.deleteMax(r.root)\n\tif !r.IsEmpty() {\n\t\tr.root.colorRed = false\n\t}\n\treturn\n}\n\nfunc (r *RedBlack) deleteMax(h *mapnode) (_ *mapnode, oldk KType, oldv VType, ok bool) {\n\tif h == nil {\n\t\treturn nil, oldk, oldv, ok\n\t}\n\tif h.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\tif h.right == nil {\n\t\treturn nil, h.key, h.val, true\n\t}\n\tif !h.right.isRed() && !h.right.left.isRed() {\n\t\th = r.moveRedRight(h)\n\t}\n\th.right, oldk, oldv, ok = r.deleteMax(h.right)\n\treturn r.balance(h), oldk, oldv, ok\n}\n\n// Delete key `k` from sorted map, if it exists.\nfunc (r *RedBlack) Delete(k KType) (old VType, ok bool) {\n\tif r.root == nil {\n\t\treturn\n\t}\n\tr.root, old, ok = r.delete(r.root, k)\n\tif !r.IsEmpty() {\n\t\tr.root.colorRed = false\n\t}\n\treturn\n}\n\nfunc (r *RedBlack) delete(h *mapnode, k KType) (_ *mapnode, old VType, ok bool) {\n\n\tif h == nil {\n\t\treturn h, old, false\n\t}\n\n\tif r.compare(k, h.key) < 0 {\n\t\tif h.left == nil {\n\t\t\treturn h, old, false\n\t\t}\n\n\t\tif !h.left.isRed() && !h.left.left.isRed() {\n\t\t\th = r.moveRedLeft(h)\n\t\t}\n\n\t\th.left, old, ok = r.delete(h.left, k)\n\t\th = r.balance(h)\n\t\treturn h, old, ok\n\t}\n\n\tif h.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\n\tif r.compare(k, h.key) == 0 && h.right == nil {\n\t\treturn nil, h.val, true\n\t}\n\n\tif h.right != nil && !h.right.isRed() && !h.right.left.isRed() {\n\t\th = r.moveRedRight(h)\n\t}\n\n\tif r.compare(k, h.key) == 0 {\n\n\t\tvar subk KType\n\t\tvar subv VType\n\t\th.right, subk, subv, ok = r.deleteMin(h.right)\n\n\t\told, h.key, h.val = h.val, subk, subv\n\t\tok = true\n\t} else {\n\t\th.right, old, ok = r.delete(h.right, k)\n\t}\n\n\th = r.balance(h)\n\treturn h, old, ok\n}\n\n// DeleteRange removes the keys between lo and hi from the sorted map, and\n// returns how many there were. A few keys are deleted one by one, in\n// O(log n) each, and otherwise the sorted map is rebuilt from the keys\n// left in O(n).\nfunc (r *RedBlack) DeleteRange(lo, hi KType) int {\n\tcount := r.CountRange(lo, hi)\n\tsize := r.Size()\n\tif count*bits.Len(uint(size)) < size {\n\t\tfor i := 0; i < count; i++ {\n\t\t\tr.Delete(r.ceiling(r.root, lo).key)\n\t\t}\n\t\treturn count\n\t}\n\tkept := r.outside(r.root, lo, hi, make([]*mapnode, 0, size-count))\n\t// the smallest height of a 2-3 tree holding the keys left, which holds\n\t// up to 3^(height+1)-1 keys\n\theight := 0\n\tfor max := 2; max < len(kept); max = 3*max + 2 {\n\t\theight++\n\t}\n\tr.root = r.build(kept, height)\n\treturn count\n}\n\n// outside appends the nodes of h whose keys aren't between lo and hi to\n// nodes, in order.\nfunc (r *RedBlack) outside(h *mapnode, lo, hi KType, nodes []*mapnode) []*mapnode {\n\tif h == nil {\n\t\treturn nodes\n\t}\n\tnodes = r.outside(h.left, lo, hi, nodes)\n\tif r.compare(h.key, lo) < 0 || r.compare(h.key, hi) > 0 {\n\t\tnodes = append(nodes, h)\n\t}\n\treturn r.outside(h.right, lo, hi, nodes)\n}\n\n// build links nodes, in order, into a 2-3 tree of the given height, whose\n// 3-nodes are a black node leaning on a red left child. A 2-3 tree of that\n// height holds between 2^(height+1)-1 and 3^(height+1)-1 keys, which nodes\n// must be within.\nfunc (r *RedBlack) build(nodes []*mapnode, height int) *mapnode {\n\tif len(nodes) == 0 {\n\t\treturn nil\n\t}\n\t// the subtrees hold up to 3^height-1 keys\n\tmax := 1\n\tfor i := 0; i < height; i++ {\n\t\tmax *= 3\n\t}\n\tmax--\n\n\tif len(nodes) <= 2*max+1 {\n\t\tmid := len(nodes) / 2\n\t\th := nodes[mid]\n\t\th.left = r.build(nodes[:mid], height-1)\n\t\th.right = r.build(nodes[mid+1:], height-1)\n\t\th.n, h.colorRed = len(nodes), false\n\t\treturn h\n\t}\n\ta := (len(nodes) - 2) / 3\n\tb := (len(nodes) - 2 - a) / 2\n\tleft, h := nodes[a], nodes[a+b+1]\n\tleft.left = r.build(nodes[:a], height-1)\n\tleft.right = r.build(nodes[a+1:a+b+1], height-1)\n\tleft.n, left.colorRed = a+b+1, true\n\th.left = left\n\th.right = r.build(nodes[a+b+2:], height-1)\n\th.n, h.colorRed = len(nodes), false\n\treturn h\n}\n\n// deletions\n\nfunc (r *RedBlack) moveRedLeft(h *mapnode) *mapnode {\n\tr.flipColors(h)\n\tif h.right.left.isRed() {\n\t\th.right = r.rotateRight(h.right)\n\t\th = r.rotateLeft(h)\n\t\tr.flipColors(h)\n\t}\n\treturn h\n}\n\nfunc (r *RedBlack) moveRedRight(h *mapnode) *mapnode {\n\tr.flipColors(h)\n\tif h.left.left.isRed() {\n\t\th = r.rotateRight(h)\n\t\tr.flipColors(h)\n\t}\n\treturn h\n}\n\nfunc (r *RedBlack) balance(h *mapnode) *mapnode {\n\tif h.right.isRed() {\n\t\th = r.rotateLeft(h)\n\t}\n\tif h.left.isRed() && h.left.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\tif h.left.isRed() && h.right.isRed() {\n\t\tr.flipColors(h)\n\t}\n\th.n = h.left.size() + h.right.size() + 1\n\treturn h\n}\n\nfunc (r *RedBlack) rotateLeft(h *mapnode) *mapnode {\n\tx := h.right\n\th.right = x.left\n\tx.left = h\n\tx.colorRed = h.colorRed\n\th.colorRed = true\n\tx.n = h.n\n\th.n = 1 + h.left.size() + h.right.size()\n\treturn x\n}\n\nfunc (r *RedBlack) rotateRight(h *mapnode) *mapnode {\n\tx := h.left\n\th.left = x.right\n\tx.right = h\n\tx.colorRed = h.colorRed\n\th.colorRed = true\n\tx.n = h.n\n\th.n = 1 + h.left.size() + h.right.size()\n\treturn x\n}\n\nfunc (r *RedBlack) flipColors(h *mapnode) {\n\th.colorRed = !h.colorRed\n\th.left.colorRed = !h.left.colorRed\n\th.right.colorRed = !h.right.colorRed\n}\n\n// nodes\n\ntype mapnode struct {\n\tkey         KType\n\tval         VType\n\tleft, right *mapnode\n\tn           int\n\tcolorRed    bool\n}\n\nfunc (x *mapnode) isRed() bool { return (x != nil) && (x.colorRed == true) }\n\nfunc (x *mapnode) size() int {\n\tif x == nil {\n\t\treturn 0\n\t}\n\treturn x.n\n}\n"
+	redblackbstSetSrc         = "package redblackbst\n\n// GENERATED CODE!!!\n\nimport \"math/bits\"\n\nfunc (r RedBlack) compare(a, b KType) int { return a.Compare(b) }\n\n// RedBlack is a sorted set built on a left leaning red black balanced\n// search sorted set. It stores unique KType values.\ntype RedBlack struct {\n\troot *treenode\n}\n\n// NewRedBlack creates a sorted set.\nfunc NewRedBlack() *RedBlack { return &RedBlack{} }\n\n// IsEmpty tells if the sorted set contains no key.\nfunc (r RedBlack) IsEmpty() bool {\n\treturn r.root == nil\n}\n\n// Size of the sorted set.\nfunc (r RedBlack) Size() int { return r.root.size() }\n\n// Clear all the values in the sorted set.\nfunc (r *RedBlack) Clear() { r.root = nil }\n\n// Put the key `k` in the sorted set. If the value was already there,\n// true is returned.\nfunc (r *RedBlack) Put(k KType) (already bool) {\n\tr.root, already = r.put(r.root, k)\n\treturn\n}\n\nfunc (r *RedBlack) put(h *treenode, k KType) (_ *treenode, already bool) {\n\tif h == nil {\n\t\tn := &treenode{key: k, n: 1, colorRed: true}\n\t\treturn n, already\n\t}\n\n\tcmp := r.compare(k, h.key)\n\tif cmp < 0 {\n\t\th.left, already = r.put(h.left, k)\n\t} else if cmp > 0 {\n\t\th.right, already = r.put(h.right, k)\n\t} else {\n\t\talready = true\n\t}\n\n\tif h.right.isRed() && !h.left.isRed() {\n\t\th = r.rotateLeft(h)\n\t}\n\tif h.left.isRed() && h.left.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\tif h.left.isRed() && h.right.isRed() {\n\t\tr.flipColors(h)\n\t}\n\th.n = h.left.size() + h.right.size() + 1\n\treturn h, already\n}\n\n// Contains tells if `k` is a member of the set.\nfunc (r RedBlack) Contains(k KType) bool {\n\treturn r.loopContains(r.root, k)\n}\n\nfunc (r RedBlack) loopContains(h *treenode, k KType) (ok bool) {\n\tfor h != nil {\n\t\tcmp := r.compare(k, h.key)\n\t\tif cmp == 0 {\n\t\t\treturn true\n\t\t} else if cmp < 0 {\n\t\t\th = h.left\n\t\t} else if cmp > 0 {\n\t\t\th = h.right\n\t\t}\n\t}\n\treturn\n}\n\n// Min returns the smallest key in the sorted set, if it exists.\nfunc (r RedBlack) Min() (k KType, ok bool) {\n\tif r.root == nil {\n\t\treturn\n\t}\n\th := r.min(r.root)\n\treturn h.key, true\n}\n\nfunc (r RedBlack) min(x *treenode) *treenode {\n\tif x.left == nil {\n\t\treturn x\n\t}\n\treturn r.min(x.left)\n}\n\n// Max returns the largest key in the sorted set, if it exists.\nfunc (r RedBlack) Max() (k KType, ok bool) {\n\tif r.root == nil {\n\t\treturn\n\t}\n\th := r.max(r.root)\n\treturn h.key, true\n}\n\nfunc (r RedBlack) max(x *treenode) *treenode {\n\tif x.right == nil {\n\t\treturn x\n\t}\n\treturn r.max(x.right)\n}\n\n// Floor returns the largest key in the sorted set that is smaller than\n// or equal to `k`.\nfunc (r RedBlack) Floor(key KType) (k KType, ok bool) {\n\tx := r.floor(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, true\n}\n\nfunc (r RedBlack) floor(h *treenode, k KType) *treenode {\n\tif h == nil {\n\t\treturn nil\n\t}\n\tcmp := r.compare(k, h.key)\n\tif cmp == 0 {\n\t\treturn h\n\t}\n\tif cmp < 0 {\n\t\treturn r.floor(h.left, k)\n\t}\n\tt := r.floor(h.right, k)\n\tif t != nil {\n\t\treturn t\n\t}\n\treturn h\n}\n\n// Ceiling returns the smallest key in the sorted set that is larger than\n// or equal to `k`.\nfunc (r RedBlack) Ceiling(key KType) (k KType, ok bool) {\n\tx := r.ceiling(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, true\n}\n\nfunc (r RedBlack) ceiling(h *treenode, k KType) *treenode {\n\tif h == nil {\n\t\treturn nil\n\t}\n\tcmp := r.compare(k, h.key)\n\tif cmp == 0 {\n\t\treturn h\n\t}\n\tif cmp > 0 {\n\t\treturn r.ceiling(h.right, k)\n\t}\n\tt := r.ceiling(h.left, k)\n\tif t != nil {\n\t\treturn t\n\t}\n\treturn h\n}\n\n// Lower returns the largest key in the sorted set that is strictly\n// smaller than `k`.\nfunc (r RedBlack) Lower(key KType) (k KType, ok bool) {\n\tx := r.lower(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, true\n}\n\nfunc (r RedBlack) lower(h *treenode, k KType) *treenode {\n\tif h == nil {\n\t\treturn nil\n\t}\n\tif r.compare(k, h.key) <= 0 {\n\t\treturn r.lower(h.left, k)\n\t}\n\tt := r.lower(h.right, k)\n\tif t != nil {\n\t\treturn t\n\t}\n\treturn h\n}\n\n// Higher returns the smallest key in the sorted set that is strictly\n// larger than `k`.\nfunc (r RedBlack) Higher(key KType) (k KType, ok bool) {\n\tx := r.higher(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, true\n}\n\nfunc (r RedBlack) higher(h *treenode, k KType) *treenode {\n\tif h == nil {\n\t\treturn nil\n\t}\n\tif r.compare(k, h.key) >= 0 {\n\t\treturn r.higher(h.right, k)\n\t}\n\tt := r.higher(h.left, k)\n\tif t != nil {\n\t\treturn t\n\t}\n\treturn h\n}\n\n// FloorRank returns the rank of the floor of `k`, if there's one. Its\n// neighbours in the sorted set are at the next and previous ranks, which\n// Select finds.\nfunc (r RedBlack) FloorRank(key KType) (rank int, ok bool) {\n\tx := r.floor(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn r.keyrank(x.key, r.root), true\n}\n\n// CeilingRank returns the rank of the ceiling of `k`, if there's one. Its\n// neighbours in the sorted set are at the next and previous ranks, which\n// Select finds.\nfunc (r RedBlack) CeilingRank(key KType) (rank int, ok bool) {\n\trank = r.keyrank(key, r.root)\n\treturn rank, rank < r.Size()\n}\n\n// Select key of rank k, meaning the k-th biggest KType in the sorted set.\nfunc (r RedBlack) Select(key int) (k KType, ok bool) {\n\tx := r.nodeselect(r.root, key)\n\tif x == nil {\n\t\treturn\n\t}\n\treturn x.key, true\n}\n\nfunc (r RedBlack) nodeselect(x *treenode, k int) *treenode {\n\tif x == nil {\n\t\treturn nil\n\t}\n\tt := x.left.size()\n\tif t > k {\n\t\treturn r.nodeselect(x.left, k)\n\t} else if t < k {\n\t\treturn r.nodeselect(x.right, k-t-1)\n\t} else {\n\t\treturn x\n\t}\n}\n\n// Rank is the number of keys less than `k`.\nfunc (r RedBlack) Rank(k KType) int {\n\treturn r.keyrank(k, r.root)\n}\n\nfunc (r RedBlack) keyrank(k KType, h *treenode) int {\n\tif h == nil {\n\t\treturn 0\n\t}\n\tcmp := r.compare(k, h.key)\n\tif cmp < 0 {\n\t\treturn r.keyrank(k, h.left)\n\t} else if cmp > 0 {\n\t\treturn 1 + h.left.size() + r.keyrank(k, h.right)\n\t} else {\n\t\treturn h.left.size()\n\t}\n}\n\n// CountRange returns the number of keys between lo and hi in the sorted\n// set, which it counts from their ranks in O(log n).\nfunc (r RedBlack) CountRange(lo, hi KType) int {\n\tif r.compare(lo, hi) > 0 {\n\t\treturn 0\n\t}\n\tcount := r.keyrank(hi, r.root) - r.keyrank(lo, r.root)\n\tif r.Contains(hi) {\n\t\tcount++\n\t}\n\treturn count\n}\n\n// Keys visit each keys in the sorted set, in order.\n// It stops when visit returns false.\nfunc (r RedBlack) Keys(visit func(KType) bool) {\n\tmin, ok := r.Min()\n\tif !ok {\n\t\treturn\n\t}\n\t// if the min exists, then the max must exist\n\tmax, _ := r.Max()\n\tr.RangedKeys(min, max, visit)\n}\n\n// RangedKeys visit each keys between lo and hi in the sorted set, in order.\n// It stops when visit returns false.\nfunc (r RedBlack) RangedKeys(lo, hi KType, visit func(KType) bool) {\n\tr.keys(r.root, visit, lo, hi)\n}\n\nfunc (r RedBlack) keys(h *treenode, visit func(KType) bool, lo, hi KType) bool {\n\tif h == nil {\n\t\treturn true\n\t}\n\tcmplo := r.compare(lo, h.key)\n\tcmphi := r.compare(hi, h.key)\n\tif cmplo < 0 {\n\t\tif !r.keys(h.left, visit, lo, hi) {\n\t\t\treturn false\n\t\t}\n\t}\n\tif cmplo <= 0 && cmphi >= 0 {\n\t\tif !visit(h.key) {\n\t\t\treturn false\n\t\t}\n\t}\n\tif cmphi > 0 {\n\t\tif !r.keys(h.right, visit, lo, hi) {\n\t\t\treturn false\n\t\t}\n\t}\n\treturn true\n}\n\n// KeysBetween visit each keys between lo and hi in the sorted set, in\n// order, like RangedKeys, but leaves lo out unless loInclusive and hi out\n// unless hiInclusive: `KeysBetween(lo, true, hi, false, visit)` visits the\n// keys of [lo, hi). It stops when visit returns false.\nfunc (r RedBlack) KeysBetween(lo KType, loInclusive bool, hi KType, hiInclusive bool, visit func(KType) bool) {\n\tr.bounded(r.root, visit, r.above(lo, loInclusive), r.below(hi, hiInclusive), false)\n}\n\n// KeysAbove visit each keys larger than lo in the sorted set, or equal to\n// it if inclusive, in order. It stops when visit returns false.\nfunc (r RedBlack) KeysAbove(lo KType, inclusive bool, visit func(KType) bool) {\n\tr.bounded(r.root, visit, r.above(lo, inclusive), r.unbounded, false)\n}\n\n// KeysBelow visit each keys smaller than hi in the sorted set, or equal to\n// it if inclusive, in order. It stops when visit returns false.\nfunc (r RedBlack) KeysBelow(hi KType, inclusive bool, visit func(KType) bool) {\n\tr.bounded(r.root, visit, r.unbounded, r.below(hi, inclusive), false)\n}\n\n// ReverseKeys visit each keys in the sorted set, in reverse order.\n// It stops when visit returns false.\nfunc (r RedBlack) ReverseKeys(visit func(KType) bool) {\n\tr.bounded(r.root, visit, r.unbounded, r.unbounded, true)\n}\n\n// ReverseRangedKeys visit each keys between lo and hi in the sorted set,\n// in reverse order, from hi down to lo. It stops when visit returns false.\nfunc (r RedBlack) ReverseRangedKeys(lo, hi KType, visit func(KType) bool) {\n\tr.bounded(r.root, visit, r.above(lo, true), r.below(hi, true), true)\n}\n\n// ReverseKeysBetween is KeysBetween, visiting the keys in reverse order.\nfunc (r RedBlack) ReverseKeysBetween(lo KType, loInclusive bool, hi KType, hiInclusive bool, visit func(KType) bool) {\n\tr.bounded(r.root, visit, r.above(lo, loInclusive), r.below(hi, hiInclusive), true)\n}\n\n// ReverseKeysAbove is KeysAbove, visiting the keys in reverse order.\nfunc (r RedBlack) ReverseKeysAbove(lo KType, inclusive bool, visit func(KType) bool) {\n\tr.bounded(r.root, visit, r.above(lo, inclusive), r.unbounded, true)\n}\n\n// ReverseKeysBelow is KeysBelow, visiting the keys in reverse order, like\n// the latest keys before hi.\nfunc (r RedBlack) ReverseKeysBelow(hi KType, inclusive bool, visit func(KType) bool) {\n\tr.bounded(r.root, visit, r.unbounded, r.below(hi, inclusive), true)\n}\n\n// bounded visits the keys of h within bounds, in order or in reverse.\n// aboveLo and belowHi tell if a key is within the lower and the upper bound,\n// which are only crossed once going through the keys in order.\nfunc (r RedBlack) bounded(h *treenode, visit func(KType) bool, aboveLo, belowHi func(KType) bool, reverse bool) bool {\n\tif h == nil {\n\t\treturn true\n\t}\n\tinLo, inHi := aboveLo(h.key), belowHi(h.key)\n\t// the keys of the left subtree are all out of bounds when h is below\n\t// lo, and those of the right subtree when h is above hi\n\tfirst, last := h.left, h.right\n\tinFirst, inLast := inLo, inHi\n\tif reverse {\n\t\tfirst, last = last, first\n\t\tinFirst, inLast = inLast, inFirst\n\t}\n\tif inFirst && !r.bounded(first, visit, aboveLo, belowHi, reverse) {\n\t\treturn false\n\t}\n\tif inLo && inHi && !visit(h.key) {\n\t\treturn false\n\t}\n\tif inLast && !r.bounded(last, visit, aboveLo, belowHi, reverse) {\n\t\treturn false\n\t}\n\treturn true\n}\n\n// above returns the lower bound lo, which keys are within when they're\n// larger than lo, or equal to it if inclusive.\nfunc (r RedBlack) above(lo KType, inclusive bool) func(KType) bool {\n\treturn func(k KType) bool {\n\t\tcmp := r.compare(k, lo)\n\t\treturn cmp > 0 || inclusive && cmp == 0\n\t}\n}\n\n// below returns the upper bound hi, which keys are within when they're\n// smaller than hi, or equal to it if inclusive.\nfunc (r RedBlack) below(hi KType, inclusive bool) func(KType) bool {\n\treturn func(k KType) bool {\n\t\tcmp := r.compare(k, hi)\n\t\treturn cmp < 0 || inclusive && cmp == 0\n\t}\n}\n\n// unbounded is the bound of an open end, which all keys are within.\nfunc (r RedBlack) unbounded(KType) bool { return true }\n\n// DeleteMin removes the smallest key from the sorted set.\nfunc (r *RedBlack) DeleteMin() (oldk KType, ok bool) {\n\tr.root, oldk, ok = r.deleteMin(r.root)\n\tif !r.IsEmpty() {\n\t\tr.root.colorRed = false\n\t}\n\treturn\n}\n\nfunc (r *RedBlack) deleteMin(h *treenode) (_ *treenode, oldk KType, ok bool) {\n\tif h == nil {\n\t\treturn nil, oldk, false\n\t}\n\n\tif h.left == nil {\n\t\treturn nil, h.key, true\n\t}\n\tif !h.left.isRed() && !h.left.left.isRed() {\n\t\th = r.moveRedLeft(h)\n\t}\n\th.left, oldk, ok = r.deleteMin(h.left)\n\treturn r.balance(h), oldk, ok\n}\n\n// DeleteMax removes the largest key from the sorted set.\nfunc (r *RedBlack) DeleteMax() (oldk KType, ok bool) {\n\tr.root, oldk, ok = r.deleteMax(r.root)\n\tif !r.IsEmpty() {\n\t\tr.root.colorRed = false\n\t}\n\treturn\n}\n\nfunc (r *RedBlack) deleteMax(h *treenode) (_ *treenode, oldk KType, ok bool) {\n\tif h == nil {\n\t\treturn nil, oldk, ok\n\t}\n\tif h.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\tif h.right == nil {\n\t\treturn nil, h.key, true\n\t}\n\tif !h.right.isRed() && !h.right.left.isRed() {\n\t\th = r.moveRedRight(h)\n\t}\n\th.right, oldk, ok = r.deleteMax(h.right)\n\treturn r.balance(h), oldk, ok\n}\n\n// Delete key `k` from sorted set, if it exists.\nfunc (r *RedBlack) Delete(k KType) (ok bool) {\n\tif r.root == nil {\n\t\treturn\n\t}\n\tr.root, ok = r.delete(r.root, k)\n\tif !r.IsEmpty() {\n\t\tr.root.colorRed = false\n\t}\n\treturn\n}\n\nfunc (r *RedBlack) delete(h *treenode, k KType) (_ *treenode, ok bool) {\n\n\tif h == nil {\n\t\treturn h, false\n\t}\n\n\tif r.compare(k, h.key) < 0 {\n\t\tif h.left == nil {\n\t\t\treturn h, false\n\t\t}\n\n\t\tif !h.left.isRed() && !h.left.left.isRed() {\n\t\t\th = r.moveRedLeft(h)\n\t\t}\n\n\t\th.left, ok = r.delete(h.left, k)\n\t\th = r.balance(h)\n\t\treturn h, ok\n\t}\n\n\tif h.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\n\tif r.compare(k, h.key) == 0 && h.right == nil {\n\t\treturn nil, true\n\t}\n\n\tif h.right != nil && !h.right.isRed() && !h.right.left.isRed() {\n\t\th = r.moveRedRight(h)\n\t}\n\n\tif r.compare(k, h.key) == 0 {\n\n\t\tvar subk KType\n\t\th.right, subk, ok = r.deleteMin(h.right)\n\t\th.key = subk\n\t\tok = true\n\t} else {\n\t\th.right, ok = r.delete(h.right, k)\n\t}\n\n\th = r.balance(h)\n\treturn h, ok\n}\n\n// DeleteRange removes the keys between lo and hi from the sorted set, and\n// returns how many there were. A few keys are deleted one by one, in\n// O(log n) each, and otherwise the sorted set is rebuilt from the keys\n// left in O(n).\nfunc (r *RedBlack) DeleteRange(lo, hi KType) int {\n\tcount := r.CountRange(lo, hi)\n\tsize := r.Size()\n\tif count*bits.Len(uint(size)) < size {\n\t\tfor i := 0; i < count; i++ {\n\t\t\tr.Delete(r.ceiling(r.root, lo).key)\n\t\t}\n\t\treturn count\n\t}\n\tkept := r.outside(r.root, lo, hi, make([]*treenode, 0, size-count))\n\t// the smallest height of a 2-3 tree holding the keys left, which holds\n\t// up to 3^(height+1)-1 keys\n\theight := 0\n\tfor max := 2; max < len(kept); max = 3*max + 2 {\n\t\theight++\n\t}\n\tr.root = r.build(kept, height)\n\treturn count\n}\n\n// outside appends the nodes of h whose keys aren't between lo and hi to\n// nodes, in order.\nfunc (r *RedBlack) outside(h *treenode, lo, hi KType, nodes []*treenode) []*treenode {\n\tif h == nil {\n\t\treturn nodes\n\t}\n\tnodes = r.outside(h.left, lo, hi, nodes)\n\tif r.compare(h.key, lo) < 0 || r.compare(h.key, hi) > 0 {\n\t\tnodes = append(nodes, h)\n\t}\n\treturn r.outside(h.right, lo, hi, nodes)\n}\n\n// build links nodes, in order, into a 2-3 tree of the given height, whose\n// 3-nodes are a black node leaning on a red left child. A 2-3 tree of that\n// height holds between 2^(height+1)-1 and 3^(height+1)-1 keys, which nodes\n// must be within.\nfunc (r *RedBlack) build(nodes []*treenode, height int) *treenode {\n\tif len(nodes) == 0 {\n\t\treturn nil\n\t}\n\t// the subtrees hold up to 3^height-1 keys\n\tmax := 1\n\tfor i := 0; i < height; i++ {\n\t\tmax *= 3\n\t}\n\tmax--\n\n\tif len(nodes) <= 2*max+1 {\n\t\tmid := len(nodes) / 2\n\t\th := nodes[mid]\n\t\th.left = r.build(nodes[:mid], height-1)\n\t\th.right = r.build(nodes[mid+1:], height-1)\n\t\th.n, h.colorRed = len(nodes), false\n\t\treturn h\n\t}\n\ta := (len(nodes) - 2) / 3\n\tb := (len(nodes) - 2 - a) / 2\n\tleft, h := nodes[a], nodes[a+b+1]\n\tleft.left = r.build(nodes[:a], height-1)\n\tleft.right = r.build(nodes[a+1:a+b+1], height-1)\n\tleft.n, left.colorRed = a+b+1, true\n\th.left = left\n\th.right = r.build(nodes[a+b+2:], height-1)\n\th.n, h.colorRed = len(nodes), false\n\treturn h\n}\n\n// deletions\n\nfunc (r *RedBlack) moveRedLeft(h *treenode) *treenode {\n\tr.flipColors(h)\n\tif h.right.left.isRed() {\n\t\th.right = r.rotateRight(h.right)\n\t\th = r.rotateLeft(h)\n\t\tr.flipColors(h)\n\t}\n\treturn h\n}\n\nfunc (r *RedBlack) moveRedRight(h *treenode) *treenode {\n\tr.flipColors(h)\n\tif h.left.left.isRed() {\n\t\th = r.rotateRight(h)\n\t\tr.flipColors(h)\n\t}\n\treturn h\n}\n\nfunc (r *RedBlack) balance(h *treenode) *treenode {\n\tif h.right.isRed() {\n\t\th = r.rotateLeft(h)\n\t}\n\tif h.left.isRed() && h.left.left.isRed() {\n\t\th = r.rotateRight(h)\n\t}\n\tif h.left.isRed() && h.right.isRed() {\n\t\tr.flipColors(h)\n\t}\n\th.n = h.left.size() + h.right.size() + 1\n\treturn h\n}\n\nfunc (r *RedBlack) rotateLeft(h *treenode) *treenode {\n\tx := h.right\n\th.right = x.left\n\tx.left = h\n\tx.colorRed = h.colorRed\n\th.colorRed = true\n\tx.n = h.n\n\th.n = 1 + h.left.size() + h.right.size()\n\treturn x\n}\n\nfunc (r *RedBlack) rotateRight(h *treenode) *treenode {\n\tx := h.left\n\th.left = x.right\n\tx.right = h\n\tx.colorRed = h.colorRed\n\th.colorRed = true\n\tx.n = h.n\n\th.n = 1 + h.left.size() + h.right.size()\n\treturn x\n}\n\nfunc (r *RedBlack) flipColors(h *treenode) {\n\th.colorRed = !h.colorRed\n\th.left.colorRed = !h.left.colorRed\n\th.right.colorRed = !h.right.colorRed\n}\n\n// nodes\n\ntype treenode struct {\n\tkey         KType\n\tleft, right *treenode\n\tn           int\n\tcolorRed    bool\n}\n\nfunc (x *treenode) isRed() bool { return (x != nil) && (x.colorRed == true) }\n\nfunc (x *treenode) size() int {\n\tif x == nil {\n\t\treturn 0\n\t}\n\treturn x.n\n}\n"
+	heapSrc                   = "package heap\n\n// GENERATED CODE!!!\n\n// Most of the implementation is adapted from Algorithms 4ed by Sedgewick\n// and Wayne.\n\n// Comments are adapted from `container/heap`.\n// \t Copyright 2009 The Go Authors. All rights reserved.\n// \t Use of this source code is governed by a BSD-style\n// \t license that can be found in the LICENSE file.\n\nfunc (h Heap) compare(a, b KType) int { return a.Compare(b) }\n\n// Heap is a container of KType, where the elements can be efficiently\n// retrieved in their decreasing order (according to their comparison\n// rules).\ntype Heap struct {\n\tn  int\n\tpq []KType\n}\n\n// NewHeap creates a heap, optionaly with keys already populating\n// it. The complexity is O(n) where n = len(keys).\nfunc NewHeap(keys ...KType) *Heap {\n\th := &Heap{\n\t\tn:  len(keys),\n\t\tpq: append(make([]KType, 1), keys...),\n\t}\n\th.Fix()\n\treturn h\n}\n\n// Len is the number of elements stored in the heap.\nfunc (h *Heap) Len() int { return h.n }\n\n// Peek at the largest element (according to their comparison rules), without\n// removing it from the heap.\nfunc (h *Heap) Peek() KType { return h.pq[1] }\n\n// Fix re-establishes the heap ordering. This is useful if elements\n// of the heap have had their comparison value changed. It is equivalent to,\n// but less expenasive than, Pop'ing all the elements and Push'ing them\n// again.\n// The complexity is O(n).\nfunc (h *Heap) Fix() {\n\tfor i := (h.n) / 2; i > 0; i-- {\n\t\th.sink(i, h.n)\n\t}\n}\n\n// Push pushes the element k onto the heap. The complexity is\n// O(log(n)) where n == h.Len().\nfunc (h *Heap) Push(k KType) {\n\th.n++\n\th.pq = append(h.pq, k)\n\th.swim(h.n)\n}\n\n// Pop removes the largest element (according to their comparison rules) from\n// the heap and returns it. The complexity is O(log(n)) where n == h.Len().\nfunc (h *Heap) Pop() KType {\n\tval := h.pq[1]\n\th.swap(1, h.n)\n\th.pq = h.pq[:h.n]\n\th.n--\n\th.sink(1, h.n)\n\n\treturn val\n}\n\n// Remove removes k from the heap, if it exists. Equality is defined by\n// Compare == 0.\n// The complexity is O(n+log(n)) where n == h.Len().\nfunc (h *Heap) Remove(k KType) bool {\n\n\tcmp := h.compare(h.pq[1], k)\n\tif cmp == 0 {\n\t\t_ = h.Pop()\n\t\treturn true\n\t}\n\tif cmp < 0 {\n\t\t// larger than largest, don't try to find it\n\t\treturn false\n\t}\n\n\tfor i := 2; i <= h.n; i++ {\n\t\tif h.compare(h.pq[i], k) != 0 {\n\t\t\tcontinue\n\t\t}\n\t\t// the last element takes its place, and is moved up or down to\n\t\t// where it belongs\n\t\th.swap(i, h.n)\n\t\th.pq = h.pq[:h.n]\n\t\th.n--\n\t\tif i <= h.n {\n\t\t\th.sink(i, h.n)\n\t\t\th.swim(i)\n\t\t}\n\t\treturn true\n\t}\n\t// not in the heap\n\treturn false\n}\n\nfunc (h *Heap) swap(i, j int)      { h.pq[i], h.pq[j] = h.pq[j], h.pq[i] }\nfunc (h *Heap) less(i, j int) bool { return h.compare(h.pq[i], h.pq[j]) < 0 }\n\nfunc (h *Heap) swim(k int) {\n\tfor k > 1 && h.less(k/2, k) {\n\t\th.swap(k/2, k)\n\t\tk = k / 2\n\t}\n}\n\nfunc (h *Heap) sink(k, n int) {\n\n\tfor k*2 <= n {\n\t\tj := 2 * k\n\t\tif j < n && h.less(j, j+1) {\n\t\t\tj++\n\t\t}\n\t\tif !h.less(k, j) {\n\t\t\tbreak\n\t\t}\n\t\th.swap(k, j)\n\t\tk = j\n\t}\n}\n"
+	queueSrc                  = "package queue\n\n// GENERATED CODE!!!\n\n// Implementation adapted from github.com/eapache/queue:\n//    The MIT License (MIT)\n//    Copyright (c) 2014 Evan Huus\n\nvar nilKType KType\n\n// Queue represents a single instance of the queue data structure.\ntype Queue struct {\n\tbuf               []KType\n\thead, tail, count int\n\tminlen            int\n}\n\n// NewQueue constructs and returns a new Queue with an initial capacity.\nfunc NewQueue(capacity int) *Queue {\n\t// min capacity of 16\n\tif capacity < 16 {\n\t\tcapacity = 16\n\t}\n\treturn &Queue{buf: make([]KType, capacity), minlen: capacity}\n}\n\n// Len returns the number of elements currently stored in the queue.\nfunc (q *Queue) Len() int {\n\treturn q.count\n}\n\n// Push puts an element on the end of the queue.\nfunc (q *Queue) Push(elem KType) {\n\tif q.count == len(q.buf) {\n\t\tq.resize()\n\t}\n\n\tq.buf[q.tail] = elem\n\tq.tail = (q.tail + 1) % len(q.buf)\n\tq.count++\n}\n\n// Peek returns the element at the head of the queue. This call panics\n// if the queue is empty.\nfunc (q *Queue) Peek() KType {\n\tif q.Len() <= 0 {\n\t\tpanic(\"queue: empty queue\")\n\t}\n\treturn q.buf[q.head]\n}\n\n// Get returns the element at index i in the queue. If the index is\n// invalid, the call will panic.\nfunc (q *Queue) Get(i int) KType {\n\tif i >= q.Len() || i < 0 {\n\t\tpanic(\"queue: index out of range\")\n\t}\n\tmodi := (q.head + i) % len(q.buf)\n\treturn q.buf[modi]\n}\n\n// Pop removes the element from the front of the queue.\n// This call panics if the queue is empty.\nfunc (q *Queue) Pop() KType {\n\tif q.Len() <= 0 {\n\t\tpanic(\"queue: empty queue\")\n\t}\n\tv := q.buf[q.head]\n\t// set to nil to avoid keeping reference to objects\n\t// that would otherwise be garbage collected\n\tq.buf[q.head] = nilKType\n\tq.head = (q.head + 1) % len(q.buf)\n\tq.count--\n\tif len(q.buf) > q.minlen && q.count*4 <= len(q.buf) {\n\t\tq.resize()\n\t}\n\treturn v\n}\n\nfunc (q *Queue) resize() {\n\tnewBuf := make([]KType, q.count*2)\n\n\tif q.tail > q.head {\n\t\tcopy(newBuf, q.buf[q.head:q.tail])\n\t} else {\n\t\tcopy(newBuf, q.buf[q.head:len(q.buf)])\n\t\tcopy(newBuf[len(q.buf)-q.head:], q.buf[:q.tail])\n\t}\n\n\tq.head = 0\n\tq.tail = q.count\n\tq.buf = newBuf\n}\n"
+	redblackbstMapTestSrc     = "package redblackbst\n\n// GENERATED CODE!!!\n\n// The tests of this file are also generated along the sorted maps, by\n// `datagen smap -tests`. They only rely on genKType(i), which returns keys\n// that increase with i, and genVType(i), which returns values, so that they\n// can run against any key type.\n\nimport (\n\t\"math/rand\"\n\t\"reflect\"\n\t\"testing\"\n)\n\n// newRedBlackTest returns a sorted map holding the n odd keys, put in a\n// random order, so that the even keys can be used to look around them.\nfunc newRedBlackTest(t *testing.T, n int) *RedBlack {\n\ttree := NewRedBlack()\n\tfor _, i := range rand.Perm(n) {\n\t\tif old, overwrite := tree.Put(genKType(2*i+1), genVType(2*i+1)); overwrite {\n\t\t\tt.Fatalf(\"put %v: shouldn't have overwritten %v\", genKType(2*i+1), old)\n\t\t}\n\t}\n\tcheckRedBlack(t, tree)\n\treturn tree\n}\n\n// checkRedBlack verifies the invariants of the sorted map: its keys are\n// visited in increasing order, and its tree is a balanced left leaning red\n// black tree where the size of every subtree is known.\nfunc checkRedBlack(t *testing.T, tree *RedBlack) {\n\tsize := 0\n\tvar last KType\n\ttree.Keys(func(k KType, _ VType) bool {\n\t\tif size > 0 && tree.compare(last, k) >= 0 {\n\t\t\tt.Fatalf(\"key %v is visited after key %v\", k, last)\n\t\t}\n\t\tlast = k\n\t\tsize++\n\t\treturn true\n\t})\n\tif size != tree.Size() {\n\t\tt.Fatalf(\"visited %d keys, want Size=%d\", size, tree.Size())\n\t}\n\t// the root is black, even if the tree doesn't bother coloring it\n\tcheckRedBlackNode(t, tree.root, false)\n}\n\n// checkRedBlackNode verifies the subtree at x, of the given color, and\n// returns its number of black links.\nfunc checkRedBlackNode(t *testing.T, x *mapnode, red bool) int {\n\tif x == nil {\n\t\treturn 0\n\t}\n\tif x.right.isRed() {\n\t\tt.Fatalf(\"red link of %v leans right\", x.key)\n\t}\n\tif red && x.left.isRed() {\n\t\tt.Fatalf(\"two red links in a row at %v\", x.key)\n\t}\n\tif want := 1 + x.left.size() + x.right.size(); x.n != want {\n\t\tt.Fatalf(\"size of %v is %d, want %d\", x.key, x.n, want)\n\t}\n\tleft := checkRedBlackNode(t, x.left, x.left.isRed())\n\tright := checkRedBlackNode(t, x.right, x.right.isRed())\n\tif left != right {\n\t\tt.Fatalf(\"%v is unbalanced, %d black links on the left, %d on the right\", x.key, left, right)\n\t}\n\tif !red {\n\t\tleft++\n\t}\n\treturn left\n}\n\nfunc TestRedBlack_Empty(t *testing.T) {\n\ttree := NewRedBlack()\n\tif !tree.IsEmpty() || tree.Size() != 0 {\n\t\tt.Fatalf(\"new tree isn't empty, Size=%d\", tree.Size())\n\t}\n\tif k, _, ok := tree.Min(); ok {\n\t\tt.Errorf(\"empty tree has a min: %v\", k)\n\t}\n\tif k, _, ok := tree.Max(); ok {\n\t\tt.Errorf(\"empty tree has a max: %v\", k)\n\t}\n\tif k, _, ok := tree.DeleteMin(); ok {\n\t\tt.Errorf(\"empty tree deleted a min: %v\", k)\n\t}\n\tif k, _, ok := tree.DeleteMax(); ok {\n\t\tt.Errorf(\"empty tree deleted a max: %v\", k)\n\t}\n\ttree.Keys(func(k KType, _ VType) bool {\n\t\tt.Errorf(\"empty tree visited %v\", k)\n\t\treturn true\n\t})\n\n\ttree = newRedBlackTest(t, 10)\n\ttree.Clear()\n\tif !tree.IsEmpty() || tree.Size() != 0 {\n\t\tt.Fatalf(\"cleared tree isn't empty, Size=%d\", tree.Size())\n\t}\n}\n\nfunc TestRedBlack_PutGet(t *testing.T) {\n\tconst n = 200\n\ttree := newRedBlackTest(t, n)\n\tif tree.Size() != n {\n\t\tt.Fatalf(\"want Size=%d, got %d\", n, tree.Size())\n\t}\n\tfor i := 0; i < 2*n; i++ {\n\t\tv, ok := tree.Get(genKType(i))\n\t\tif ok != (i%2 == 1) || ok != tree.Has(genKType(i)) {\n\t\t\tt.Fatalf(\"get %v: found=%v, has=%v\", genKType(i), ok, tree.Has(genKType(i)))\n\t\t}\n\t\tif ok && !reflect.DeepEqual(v, genVType(i)) {\n\t\t\tt.Fatalf(\"get %v: want %v, got %v\", genKType(i), genVType(i), v)\n\t\t}\n\t}\n\n\tfor i := 1; i < 2*n; i += 2 {\n\t\told, overwrite := tree.Put(genKType(i), genVType(i+1))\n\t\tif !overwrite || !reflect.DeepEqual(old, genVType(i)) {\n\t\t\tt.Fatalf(\"put %v: want overwrite of %v, got %v, %v\", genKType(i), genVType(i), overwrite, old)\n\t\t}\n\t\tif v, _ := tree.Get(genKType(i)); !reflect.DeepEqual(v, genVType(i+1)) {\n\t\t\tt.Fatalf(\"get %v: want %v, got %v\", genKType(i), genVType(i+1), v)\n\t\t}\n\t}\n\tcheckRedBlack(t, tree)\n\tif tree.Size() != n {\n\t\tt.Fatalf(\"want Size=%d after overwrites, got %d\", n, tree.Size())\n\t}\n}\n\nfunc TestRedBlack_Mutate(t *testing.T) {\n\ttree := NewRedBlack()\n\tcreated, mutated := 0, 0\n\tcreate := func() VType { created++; return genVType(0) }\n\tmutate := func(old VType) VType {\n\t\tif !reflect.DeepEqual(old, genVType(0)) {\n\t\t\tt.Errorf(\"want to mutate %v, got %v\", genVType(0), old)\n\t\t}\n\t\tmutated++\n\t\treturn genVType(1)\n\t}\n\ttree.Mutate(genKType(0), create, mutate)\n\ttree.Mutate(genKType(0), create, mutate)\n\tif created != 1 || mutated != 1 {\n\t\tt.Fatalf(\"want 1 creation and 1 mutation, got %d and %d\", created, mutated)\n\t}\n\tif v, _ := tree.Get(genKType(0)); !reflect.DeepEqual(v, genVType(1)) {\n\t\tt.Fatalf(\"want %v, got %v\", genVType(1), v)\n\t}\n}\n\nfunc TestRedBlack_MinMax(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\tif k, v, ok := tree.Min(); !ok || tree.compare(k, genKType(1)) != 0 || !reflect.DeepEqual(v, genVType(1)) {\n\t\tt.Errorf(\"want min %v, got %v (%v)\", genKType(1), k, ok)\n\t}\n\tif k, v, ok := tree.Max(); !ok || tree.compare(k, genKType(2*n-1)) != 0 || !reflect.DeepEqual(v, genVType(2*n-1)) {\n\t\tt.Errorf(\"want max %v, got %v (%v)\", genKType(2*n-1), k, ok)\n\t}\n}\n\nfunc TestRedBlack_FloorCeiling(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\n\tif k, _, ok := tree.Floor(genKType(0)); ok {\n\t\tt.Errorf(\"want no floor below the min, got %v\", k)\n\t}\n\tif k, _, ok := tree.Ceiling(genKType(2 * n)); ok {\n\t\tt.Errorf(\"want no ceiling above the max, got %v\", k)\n\t}\n\tfor i := 1; i < 2*n; i++ {\n\t\tfloor, ceiling := i, i\n\t\tif i%2 == 0 {\n\t\t\tfloor, ceiling = i-1, i+1\n\t\t}\n\t\tif k, _, ok := tree.Floor(genKType(i)); !ok || tree.compare(k, genKType(floor)) != 0 {\n\t\t\tt.Errorf(\"want floor of %v to be %v, got %v (%v)\", genKType(i), genKType(floor), k, ok)\n\t\t}\n\t\tif i == 2*n-1 {\n\t\t\tcontinue\n\t\t}\n\t\tif k, _, ok := tree.Ceiling(genKType(i)); !ok || tree.compare(k, genKType(ceiling)) != 0 {\n\t\t\tt.Errorf(\"want ceiling of %v to be %v, got %v (%v)\", genKType(i), genKType(ceiling), k, ok)\n\t\t}\n\t}\n}\n\nfunc TestRedBlack_LowerHigher(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\n\tif k, _, ok := tree.Lower(genKType(1)); ok {\n\t\tt.Errorf(\"want nothing lower than the min, got %v\", k)\n\t}\n\tif k, _, ok := tree.Higher(genKType(2*n - 1)); ok {\n\t\tt.Errorf(\"want nothing higher than the max, got %v\", k)\n\t}\n\tfor i := 2; i < 2*n-1; i++ {\n\t\tlower, higher := i-1, i+1\n\t\tif i%2 == 1 {\n\t\t\tlower, higher = i-2, i+2\n\t\t}\n\t\tif k, _, ok := tree.Lower(genKType(i)); !ok || tree.compare(k, genKType(lower)) != 0 {\n\t\t\tt.Errorf(\"want lower of %v to be %v, got %v (%v)\", genKType(i), genKType(lower), k, ok)\n\t\t}\n\t\tif k, _, ok := tree.Higher(genKType(i)); !ok || tree.compare(k, genKType(higher)) != 0 {\n\t\t\tt.Errorf(\"want higher of %v to be %v, got %v (%v)\", genKType(i), genKType(higher), k, ok)\n\t\t}\n\t}\n\n\tif rank, ok := tree.FloorRank(genKType(0)); ok {\n\t\tt.Errorf(\"want no floor rank below the min, got %d\", rank)\n\t}\n\tif rank, ok := tree.CeilingRank(genKType(2 * n)); ok {\n\t\tt.Errorf(\"want no ceiling rank above the max, got %d\", rank)\n\t}\n\tfor i := 1; i < 2*n; i++ {\n\t\t// the keys 2*rank+1 are at rank\n\t\tfloor, ceiling := (i-1)/2, i/2\n\t\tif rank, ok := tree.FloorRank(genKType(i)); !ok || rank != floor {\n\t\t\tt.Errorf(\"want floor rank of %v to be %d, got %d (%v)\", genKType(i), floor, rank, ok)\n\t\t}\n\t\tif rank, ok := tree.CeilingRank(genKType(i)); !ok || rank != ceiling {\n\t\t\tt.Errorf(\"want ceiling rank of %v to be %d, got %d (%v)\", genKType(i), ceiling, rank, ok)\n\t\t}\n\t}\n}\n\nfunc TestRedBlack_SelectRank(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\tfor i := 0; i < n; i++ {\n\t\tif k, _, ok := tree.Select(i); !ok || tree.compare(k, genKType(2*i+1)) != 0 {\n\t\t\tt.Errorf(\"want select %d to be %v, got %v (%v)\", i, genKType(2*i+1), k, ok)\n\t\t}\n\t\tif rank := tree.Rank(genKType(2*i + 1)); rank != i {\n\t\t\tt.Errorf(\"want rank of %v to be %d, got %d\", genKType(2*i+1), i, rank)\n\t\t}\n\t\tif rank := tree.Rank(genKType(2 * i)); rank != i {\n\t\t\tt.Errorf(\"want rank of %v to be %d, got %d\", genKType(2*i), i, rank)\n\t\t}\n\t}\n\tif k, _, ok := tree.Select(n); ok {\n\t\tt.Errorf(\"want nothing selected past the max, got %v\", k)\n\t}\n}\n\nfunc TestRedBlack_Keys(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\n\ti := 1\n\ttree.Keys(func(k KType, v VType) bool {\n\t\tif tree.compare(k, genKType(i)) != 0 || !reflect.DeepEqual(v, genVType(i)) {\n\t\t\tt.Fatalf(\"want to visit %v, got %v\", genKType(i), k)\n\t\t}\n\t\ti += 2\n\t\treturn true\n\t})\n\tif i != 2*n+1 {\n\t\tt.Errorf(\"visited %d keys, want %d\", i/2, n)\n\t}\n\n\tvisited := 0\n\ttree.Keys(func(KType, VType) bool {\n\t\tvisited++\n\t\treturn visited < 10\n\t})\n\tif visited != 10 {\n\t\tt.Errorf(\"want to stop after 10 keys, visited %d\", visited)\n\t}\n\n\ti = 11\n\ttree.RangedKeys(genKType(10), genKType(21), func(k KType, _ VType) bool {\n\t\tif tree.compare(k, genKType(i)) != 0 {\n\t\t\tt.Fatalf(\"want to visit %v, got %v\", genKType(i), k)\n\t\t}\n\t\ti += 2\n\t\treturn true\n\t})\n\tif i != 23 {\n\t\tt.Errorf(\"want to visit keys up to %v, stopped before %v\", genKType(21), genKType(i))\n\t}\n}\n\nfunc TestRedBlack_BoundedKeys(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\tkey := genKType\n\n\ttests := []struct {\n\t\tname string\n\t\tkeys func(visit func(KType, VType) bool)\n\t\t// first and last keys visited, by index, or 0 when none are\n\t\tfirst, last int\n\t}{\n\t\t{\"[11, 21)\", func(visit func(KType, VType) bool) { tree.KeysBetween(key(11), true, key(21), false, visit) }, 11, 19},\n\t\t{\"(11, 21]\", func(visit func(KType, VType) bool) { tree.KeysBetween(key(11), false, key(21), true, visit) }, 13, 21},\n\t\t{\"(10, 22)\", func(visit func(KType, VType) bool) { tree.KeysBetween(key(10), false, key(22), false, visit) }, 11, 21},\n\t\t{\"(11, 13)\", func(visit func(KType, VType) bool) { tree.KeysBetween(key(11), false, key(13), false, visit) }, 0, 0},\n\t\t{\"(189, inf)\", func(visit func(KType, VType) bool) { tree.KeysAbove(key(189), false, visit) }, 191, 199},\n\t\t{\"[189, inf)\", func(visit func(KType, VType) bool) { tree.KeysAbove(key(189), true, visit) }, 189, 199},\n\t\t{\"(-inf, 11)\", func(visit func(KType, VType) bool) { tree.KeysBelow(key(11), false, visit) }, 1, 9},\n\t\t{\"(-inf, 11]\", func(visit func(KType, VType) bool) { tree.KeysBelow(key(11), true, visit) }, 1, 11},\n\t\t{\"reversed\", tree.ReverseKeys, 199, 1},\n\t\t{\"reversed [10, 21]\", func(visit func(KType, VType) bool) { tree.ReverseRangedKeys(key(10), key(21), visit) }, 21, 11},\n\t\t{\"reversed [11, 21)\", func(visit func(KType, VType) bool) { tree.ReverseKeysBetween(key(11), true, key(21), false, visit) }, 19, 11},\n\t\t{\"reversed (189, inf)\", func(visit func(KType, VType) bool) { tree.ReverseKeysAbove(key(189), false, visit) }, 199, 191},\n\t\t{\"reversed (-inf, 11]\", func(visit func(KType, VType) bool) { tree.ReverseKeysBelow(key(11), true, visit) }, 11, 1},\n\t}\n\tfor _, tt := range tests {\n\t\tvar want []KType\n\t\tif tt.first != 0 {\n\t\t\tstep := 2\n\t\t\tif tt.first > tt.last {\n\t\t\t\tstep = -2\n\t\t\t}\n\t\t\tfor i := tt.first; i != tt.last+step; i += step {\n\t\t\t\twant = append(want, genKType(i))\n\t\t\t}\n\t\t}\n\t\tvar got []KType\n\t\ttt.keys(func(k KType, _ VType) bool {\n\t\t\tgot = append(got, k)\n\t\t\treturn true\n\t\t})\n\t\tif len(got) != len(want) {\n\t\t\tt.Errorf(\"%s: want %d keys, got %d\", tt.name, len(want), len(got))\n\t\t\tcontinue\n\t\t}\n\t\tfor i := range want {\n\t\t\tif tree.compare(got[i], want[i]) != 0 {\n\t\t\t\tt.Errorf(\"%s: want %v at %d, got %v\", tt.name, want[i], i, got[i])\n\t\t\t}\n\t\t}\n\t}\n\n\tvisited := 0\n\ttree.ReverseKeysBelow(genKType(101), false, func(KType, VType) bool {\n\t\tvisited++\n\t\treturn visited < 10\n\t})\n\tif visited != 10 {\n\t\tt.Errorf(\"want to stop after 10 keys, visited %d\", visited)\n\t}\n}\n\nfunc TestRedBlack_Iterator(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\n\tit := tree.Iterator()\n\tfor i := 1; i < 2*n; i += 2 {\n\t\tif !it.Next() || tree.compare(it.Key(), genKType(i)) != 0 || !reflect.DeepEqual(it.Value(), genVType(i)) {\n\t\t\tt.Fatalf(\"want next to be %v, got %v\", genKType(i), it.Valid())\n\t\t}\n\t}\n\tif it.Next() || it.Valid() || it.Prev() {\n\t\tt.Fatal(\"want the iterator to stop past the max\")\n\t}\n\tit = tree.Iterator()\n\tfor i := 2*n - 1; i > 0; i -= 2 {\n\t\tif !it.Prev() || tree.compare(it.Key(), genKType(i)) != 0 {\n\t\t\tt.Fatalf(\"want previous to be %v, got %v\", genKType(i), it.Valid())\n\t\t}\n\t}\n\tif it.Prev() || it.Next() {\n\t\tt.Fatal(\"want the iterator to stop past the min\")\n\t}\n\tif !it.First() || tree.compare(it.Key(), genKType(1)) != 0 {\n\t\tt.Errorf(\"want first to be %v\", genKType(1))\n\t}\n\tif !it.Last() || tree.compare(it.Key(), genKType(2*n-1)) != 0 {\n\t\tt.Errorf(\"want last to be %v\", genKType(2*n-1))\n\t}\n\n\tfor i := 0; i < 2*n; i++ {\n\t\tceiling := i | 1\n\t\tif !it.SeekCeiling(genKType(i)) || tree.compare(it.Key(), genKType(ceiling)) != 0 {\n\t\t\tt.Fatalf(\"want to seek the ceiling of %v at %v, got %v\", genKType(i), genKType(ceiling), it.Valid())\n\t\t}\n\t\tif ceiling == 1 {\n\t\t\tcontinue\n\t\t}\n\t\tif !it.Prev() || tree.compare(it.Key(), genKType(ceiling-2)) != 0 {\n\t\t\tt.Fatalf(\"want previous of %v to be %v, got %v\", genKType(ceiling), genKType(ceiling-2), it.Valid())\n\t\t}\n\t\tif !it.Next() || tree.compare(it.Key(), genKType(ceiling)) != 0 {\n\t\t\tt.Fatalf(\"want next of %v to be %v, got %v\", genKType(ceiling-2), genKType(ceiling), it.Valid())\n\t\t}\n\t}\n\tif it.SeekCeiling(genKType(2*n)) || it.Next() {\n\t\tt.Error(\"want nothing past the max\")\n\t}\n\n\tfor i := 1; i <= 2*n; i++ {\n\t\tfloor := (i - 1) | 1\n\t\tif !it.SeekFloor(genKType(i)) || tree.compare(it.Key(), genKType(floor)) != 0 {\n\t\t\tt.Fatalf(\"want to seek the floor of %v at %v, got %v\", genKType(i), genKType(floor), it.Valid())\n\t\t}\n\t\tif floor == 2*n-1 {\n\t\t\tcontinue\n\t\t}\n\t\tif !it.Next() || tree.compare(it.Key(), genKType(floor+2)) != 0 {\n\t\t\tt.Fatalf(\"want next of %v to be %v, got %v\", genKType(floor), genKType(floor+2), it.Valid())\n\t\t}\n\t}\n\tif it.SeekFloor(genKType(0)) || it.Prev() {\n\t\tt.Error(\"want nothing before the min\")\n\t}\n\n\tempty := NewRedBlack().Iterator()\n\tif empty.Next() || empty.Prev() || empty.First() || empty.Last() || empty.SeekCeiling(genKType(0)) || empty.SeekFloor(genKType(0)) {\n\t\tt.Error(\"want an empty sorted map to have no keys\")\n\t}\n}\n\nfunc TestRedBlack_Delete(t *testing.T) {\n\tconst n = 200\n\ttree := newRedBlackTest(t, n)\n\tfor _, i := range rand.Perm(n) {\n\t\tif _, ok := tree.Delete(genKType(2 * i)); ok {\n\t\t\tt.Fatalf(\"deleted %v, which isn't there\", genKType(2*i))\n\t\t}\n\t\told, ok := tree.Delete(genKType(2*i + 1))\n\t\tif !ok || !reflect.DeepEqual(old, genVType(2*i+1)) {\n\t\t\tt.Fatalf(\"delete %v: want %v, got %v (%v)\", genKType(2*i+1), genVType(2*i+1), old, ok)\n\t\t}\n\t\tif tree.Has(genKType(2*i + 1)) {\n\t\t\tt.Fatalf(\"%v is still there once deleted\", genKType(2*i+1))\n\t\t}\n\t\tcheckRedBlack(t, tree)\n\t}\n\tif !tree.IsEmpty() {\n\t\tt.Fatalf(\"tree isn't empty once everything is deleted, Size=%d\", tree.Size())\n\t}\n}\n\nfunc TestRedBlack_CountDeleteRange(t *testing.T) {\n\tconst n = 100\n\ttests := []struct {\n\t\tlo, hi int\n\t\tcount  int\n\t}{\n\t\t{10, 21, 6},\n\t\t{11, 11, 1},\n\t\t{12, 12, 0},\n\t\t{21, 10, 0},\n\t\t{0, 2 * n, n},\n\t\t{0, 150, 75},\n\t\t{51, 2 * n, 75},\n\t\t{20, 180, 80},\n\t}\n\tfor _, tt := range tests {\n\t\ttree := newRedBlackTest(t, n)\n\t\tif count := tree.CountRange(genKType(tt.lo), genKType(tt.hi)); count != tt.count {\n\t\t\tt.Errorf(\"want %d keys between %v and %v, counted %d\", tt.count, genKType(tt.lo), genKType(tt.hi), count)\n\t\t}\n\t\tif count := tree.DeleteRange(genKType(tt.lo), genKType(tt.hi)); count != tt.count {\n\t\t\tt.Errorf(\"want %d keys between %v and %v, deleted %d\", tt.count, genKType(tt.lo), genKType(tt.hi), count)\n\t\t}\n\t\tcheckRedBlack(t, tree)\n\t\tif tree.Size() != n-tt.count {\n\t\t\tt.Errorf(\"want Size=%d once the range %v-%v is deleted, was %d\", n-tt.count, genKType(tt.lo), genKType(tt.hi), tree.Size())\n\t\t}\n\t\tfor i := 1; i < 2*n; i += 2 {\n\t\t\tinRange := tt.lo <= i && i <= tt.hi\n\t\t\tif tree.Has(genKType(i)) == inRange {\n\t\t\t\tt.Errorf(\"deleting the range %v-%v: want %v to be there: %v\", genKType(tt.lo), genKType(tt.hi), genKType(i), !inRange)\n\t\t\t}\n\t\t}\n\t}\n\n\t// the trees rebuilt from the keys left are balanced whatever their size\n\tfor size := 0; size < 100; size++ {\n\t\ttree := newRedBlackTest(t, size+10)\n\t\tif count := tree.DeleteRange(genKType(2*size), genKType(2*size+20)); count != 10 {\n\t\t\tt.Fatalf(\"want 10 keys deleted, deleted %d\", count)\n\t\t}\n\t\tcheckRedBlack(t, tree)\n\t}\n}\n\nfunc TestRedBlack_DeleteMinMax(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\tfor i := 0; i < n/2; i++ {\n\t\tk, v, ok := tree.DeleteMin()\n\t\tif !ok || tree.compare(k, genKType(2*i+1)) != 0 || !reflect.DeepEqual(v, genVType(2*i+1)) {\n\t\t\tt.Fatalf(\"want to delete min %v, got %v (%v)\", genKType(2*i+1), k, ok)\n\t\t}\n\t\tcheckRedBlack(t, tree)\n\n\t\tj := n - 1 - i\n\t\tk, v, ok = tree.DeleteMax()\n\t\tif !ok || tree.compare(k, genKType(2*j+1)) != 0 || !reflect.DeepEqual(v, genVType(2*j+1)) {\n\t\t\tt.Fatalf(\"want to delete max %v, got %v (%v)\", genKType(2*j+1), k, ok)\n\t\t}\n\t\tcheckRedBlack(t, tree)\n\t}\n\tif !tree.IsEmpty() {\n\t\tt.Fatalf(\"tree isn't empty once everything is deleted, Size=%d\", tree.Size())\n\t}\n}\n"
+	redblackbstSetTestSrc     = "package redblackbst\n\n// GENERATED CODE!!!\n\n// The tests of this file are also generated along the sorted sets, by\n// `datagen sset -tests`. They only rely on genKType(i), which returns keys\n// that increase with i, so that they can run against any key type.\n\nimport (\n\t\"math/rand\"\n\t\"testing\"\n)\n\n// newRedBlackTest returns a sorted set holding the n odd keys, put in a\n// random order, so that the even keys can be used to look around them.\nfunc newRedBlackTest(t *testing.T, n int) *RedBlack {\n\ttree := NewRedBlack()\n\tfor _, i := range rand.Perm(n) {\n\t\tif already := tree.Put(genKType(2*i + 1)); already {\n\t\t\tt.Fatalf(\"put %v: was already there\", genKType(2*i+1))\n\t\t}\n\t}\n\tcheckRedBlack(t, tree)\n\treturn tree\n}\n\n// checkRedBlack verifies the invariants of the sorted set: its keys are\n// visited in increasing order, and its tree is a balanced left leaning red\n// black tree where the size of every subtree is known.\nfunc checkRedBlack(t *testing.T, tree *RedBlack) {\n\tsize := 0\n\tvar last KType\n\ttree.Keys(func(k KType) bool {\n\t\tif size > 0 && tree.compare(last, k) >= 0 {\n\t\t\tt.Fatalf(\"key %v is visited after key %v\", k, last)\n\t\t}\n\t\tlast = k\n\t\tsize++\n\t\treturn true\n\t})\n\tif size != tree.Size() {\n\t\tt.Fatalf(\"visited %d keys, want Size=%d\", size, tree.Size())\n\t}\n\t// the root is black, even if the tree doesn't bother coloring it\n\tcheckRedBlackNode(t, tree.root, false)\n}\n\n// checkRedBlackNode verifies the subtree at x, of the given color, and\n// returns its number of black links.\nfunc checkRedBlackNode(t *testing.T, x *treenode, red bool) int {\n\tif x == nil {\n\t\treturn 0\n\t}\n\tif x.right.isRed() {\n\t\tt.Fatalf(\"red link of %v leans right\", x.key)\n\t}\n\tif red && x.left.isRed() {\n\t\tt.Fatalf(\"two red links in a row at %v\", x.key)\n\t}\n\tif want := 1 + x.left.size() + x.right.size(); x.n != want {\n\t\tt.Fatalf(\"size of %v is %d, want %d\", x.key, x.n, want)\n\t}\n\tleft := checkRedBlackNode(t, x.left, x.left.isRed())\n\tright := checkRedBlackNode(t, x.right, x.right.isRed())\n\tif left != right {\n\t\tt.Fatalf(\"%v is unbalanced, %d black links on the left, %d on the right\", x.key, left, right)\n\t}\n\tif !red {\n\t\tleft++\n\t}\n\treturn left\n}\n\nfunc TestRedBlack_Empty(t *testing.T) {\n\ttree := NewRedBlack()\n\tif !tree.IsEmpty() || tree.Size() != 0 {\n\t\tt.Fatalf(\"new tree isn't empty, Size=%d\", tree.Size())\n\t}\n\tif k, ok := tree.Min(); ok {\n\t\tt.Errorf(\"empty tree has a min: %v\", k)\n\t}\n\tif k, ok := tree.Max(); ok {\n\t\tt.Errorf(\"empty tree has a max: %v\", k)\n\t}\n\tif k, ok := tree.DeleteMin(); ok {\n\t\tt.Errorf(\"empty tree deleted a min: %v\", k)\n\t}\n\tif k, ok := tree.DeleteMax(); ok {\n\t\tt.Errorf(\"empty tree deleted a max: %v\", k)\n\t}\n\ttree.Keys(func(k KType) bool {\n\t\tt.Errorf(\"empty tree visited %v\", k)\n\t\treturn true\n\t})\n\n\ttree = newRedBlackTest(t, 10)\n\ttree.Clear()\n\tif !tree.IsEmpty() || tree.Size() != 0 {\n\t\tt.Fatalf(\"cleared tree isn't empty, Size=%d\", tree.Size())\n\t}\n}\n\nfunc TestRedBlack_PutContains(t *testing.T) {\n\tconst n = 200\n\ttree := newRedBlackTest(t, n)\n\tif tree.Size() != n {\n\t\tt.Fatalf(\"want Size=%d, got %d\", n, tree.Size())\n\t}\n\tfor i := 0; i < 2*n; i++ {\n\t\tif ok := tree.Contains(genKType(i)); ok != (i%2 == 1) {\n\t\t\tt.Fatalf(\"contains %v: %v\", genKType(i), ok)\n\t\t}\n\t}\n\n\tfor i := 1; i < 2*n; i += 2 {\n\t\tif already := tree.Put(genKType(i)); !already {\n\t\t\tt.Fatalf(\"put %v: want it to be already there\", genKType(i))\n\t\t}\n\t}\n\tcheckRedBlack(t, tree)\n\tif tree.Size() != n {\n\t\tt.Fatalf(\"want Size=%d after putting keys again, got %d\", n, tree.Size())\n\t}\n}\n\nfunc TestRedBlack_MinMax(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\tif k, ok := tree.Min(); !ok || tree.compare(k, genKType(1)) != 0 {\n\t\tt.Errorf(\"want min %v, got %v (%v)\", genKType(1), k, ok)\n\t}\n\tif k, ok := tree.Max(); !ok || tree.compare(k, genKType(2*n-1)) != 0 {\n\t\tt.Errorf(\"want max %v, got %v (%v)\", genKType(2*n-1), k, ok)\n\t}\n}\n\nfunc TestRedBlack_FloorCeiling(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\n\tif k, ok := tree.Floor(genKType(0)); ok {\n\t\tt.Errorf(\"want no floor below the min, got %v\", k)\n\t}\n\tif k, ok := tree.Ceiling(genKType(2 * n)); ok {\n\t\tt.Errorf(\"want no ceiling above the max, got %v\", k)\n\t}\n\tfor i := 1; i < 2*n; i++ {\n\t\tfloor, ceiling := i, i\n\t\tif i%2 == 0 {\n\t\t\tfloor, ceiling = i-1, i+1\n\t\t}\n\t\tif k, ok := tree.Floor(genKType(i)); !ok || tree.compare(k, genKType(floor)) != 0 {\n\t\t\tt.Errorf(\"want floor of %v to be %v, got %v (%v)\", genKType(i), genKType(floor), k, ok)\n\t\t}\n\t\tif i == 2*n-1 {\n\t\t\tcontinue\n\t\t}\n\t\tif k, ok := tree.Ceiling(genKType(i)); !ok || tree.compare(k, genKType(ceiling)) != 0 {\n\t\t\tt.Errorf(\"want ceiling of %v to be %v, got %v (%v)\", genKType(i), genKType(ceiling), k, ok)\n\t\t}\n\t}\n}\n\nfunc TestRedBlack_LowerHigher(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\n\tif k, ok := tree.Lower(genKType(1)); ok {\n\t\tt.Errorf(\"want nothing lower than the min, got %v\", k)\n\t}\n\tif k, ok := tree.Higher(genKType(2*n - 1)); ok {\n\t\tt.Errorf(\"want nothing higher than the max, got %v\", k)\n\t}\n\tfor i := 2; i < 2*n-1; i++ {\n\t\tlower, higher := i-1, i+1\n\t\tif i%2 == 1 {\n\t\t\tlower, higher = i-2, i+2\n\t\t}\n\t\tif k, ok := tree.Lower(genKType(i)); !ok || tree.compare(k, genKType(lower)) != 0 {\n\t\t\tt.Errorf(\"want lower of %v to be %v, got %v (%v)\", genKType(i), genKType(lower), k, ok)\n\t\t}\n\t\tif k, ok := tree.Higher(genKType(i)); !ok || tree.compare(k, genKType(higher)) != 0 {\n\t\t\tt.Errorf(\"want higher of %v to be %v, got %v (%v)\", genKType(i), genKType(higher), k, ok)\n\t\t}\n\t}\n\n\tif rank, ok := tree.FloorRank(genKType(0)); ok {\n\t\tt.Errorf(\"want no floor rank below the min, got %d\", rank)\n\t}\n\tif rank, ok := tree.CeilingRank(genKType(2 * n)); ok {\n\t\tt.Errorf(\"want no ceiling rank above the max, got %d\", rank)\n\t}\n\tfor i := 1; i < 2*n; i++ {\n\t\t// the keys 2*rank+1 are at rank\n\t\tfloor, ceiling := (i-1)/2, i/2\n\t\tif rank, ok := tree.FloorRank(genKType(i)); !ok || rank != floor {\n\t\t\tt.Errorf(\"want floor rank of %v to be %d, got %d (%v)\", genKType(i), floor, rank, ok)\n\t\t}\n\t\tif rank, ok := tree.CeilingRank(genKType(i)); !ok || rank != ceiling {\n\t\t\tt.Errorf(\"want ceiling rank of %v to be %d, got %d (%v)\", genKType(i), ceiling, rank, ok)\n\t\t}\n\t}\n}\n\nfunc TestRedBlack_SelectRank(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\tfor i := 0; i < n; i++ {\n\t\tif k, ok := tree.Select(i); !ok || tree.compare(k, genKType(2*i+1)) != 0 {\n\t\t\tt.Errorf(\"want select %d to be %v, got %v (%v)\", i, genKType(2*i+1), k, ok)\n\t\t}\n\t\tif rank := tree.Rank(genKType(2*i + 1)); rank != i {\n\t\t\tt.Errorf(\"want rank of %v to be %d, got %d\", genKType(2*i+1), i, rank)\n\t\t}\n\t\tif rank := tree.Rank(genKType(2 * i)); rank != i {\n\t\t\tt.Errorf(\"want rank of %v to be %d, got %d\", genKType(2*i), i, rank)\n\t\t}\n\t}\n\tif k, ok := tree.Select(n); ok {\n\t\tt.Errorf(\"want nothing selected past the max, got %v\", k)\n\t}\n}\n\nfunc TestRedBlack_Keys(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\n\ti := 1\n\ttree.Keys(func(k KType) bool {\n\t\tif tree.compare(k, genKType(i)) != 0 {\n\t\t\tt.Fatalf(\"want to visit %v, got %v\", genKType(i), k)\n\t\t}\n\t\ti += 2\n\t\treturn true\n\t})\n\tif i != 2*n+1 {\n\t\tt.Errorf(\"visited %d keys, want %d\", i/2, n)\n\t}\n\n\tvisited := 0\n\ttree.Keys(func(KType) bool {\n\t\tvisited++\n\t\treturn visited < 10\n\t})\n\tif visited != 10 {\n\t\tt.Errorf(\"want to stop after 10 keys, visited %d\", visited)\n\t}\n\n\ti = 11\n\ttree.RangedKeys(genKType(10), genKType(21), func(k KType) bool {\n\t\tif tree.compare(k, genKType(i)) != 0 {\n\t\t\tt.Fatalf(\"want to visit %v, got %v\", genKType(i), k)\n\t\t}\n\t\ti += 2\n\t\treturn true\n\t})\n\tif i != 23 {\n\t\tt.Errorf(\"want to visit keys up to %v, stopped before %v\", genKType(21), genKType(i))\n\t}\n}\n\nfunc TestRedBlack_BoundedKeys(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\tkey := genKType\n\n\ttests := []struct {\n\t\tname string\n\t\tkeys func(visit func(KType) bool)\n\t\t// first and last keys visited, by index, or 0 when none are\n\t\tfirst, last int\n\t}{\n\t\t{\"[11, 21)\", func(visit func(KType) bool) { tree.KeysBetween(key(11), true, key(21), false, visit) }, 11, 19},\n\t\t{\"(11, 21]\", func(visit func(KType) bool) { tree.KeysBetween(key(11), false, key(21), true, visit) }, 13, 21},\n\t\t{\"(10, 22)\", func(visit func(KType) bool) { tree.KeysBetween(key(10), false, key(22), false, visit) }, 11, 21},\n\t\t{\"(11, 13)\", func(visit func(KType) bool) { tree.KeysBetween(key(11), false, key(13), false, visit) }, 0, 0},\n\t\t{\"(189, inf)\", func(visit func(KType) bool) { tree.KeysAbove(key(189), false, visit) }, 191, 199},\n\t\t{\"[189, inf)\", func(visit func(KType) bool) { tree.KeysAbove(key(189), true, visit) }, 189, 199},\n\t\t{\"(-inf, 11)\", func(visit func(KType) bool) { tree.KeysBelow(key(11), false, visit) }, 1, 9},\n\t\t{\"(-inf, 11]\", func(visit func(KType) bool) { tree.KeysBelow(key(11), true, visit) }, 1, 11},\n\t\t{\"reversed\", tree.ReverseKeys, 199, 1},\n\t\t{\"reversed [10, 21]\", func(visit func(KType) bool) { tree.ReverseRangedKeys(key(10), key(21), visit) }, 21, 11},\n\t\t{\"reversed [11, 21)\", func(visit func(KType) bool) { tree.ReverseKeysBetween(key(11), true, key(21), false, visit) }, 19, 11},\n\t\t{\"reversed (189, inf)\", func(visit func(KType) bool) { tree.ReverseKeysAbove(key(189), false, visit) }, 199, 191},\n\t\t{\"reversed (-inf, 11]\", func(visit func(KType) bool) { tree.ReverseKeysBelow(key(11), true, visit) }, 11, 1},\n\t}\n\tfor _, tt := range tests {\n\t\tvar want []KType\n\t\tif tt.first != 0 {\n\t\t\tstep := 2\n\t\t\tif tt.first > tt.last {\n\t\t\t\tstep = -2\n\t\t\t}\n\t\t\tfor i := tt.first; i != tt.last+step; i += step {\n\t\t\t\twant = append(want, genKType(i))\n\t\t\t}\n\t\t}\n\t\tvar got []KType\n\t\ttt.keys(func(k KType) bool {\n\t\t\tgot = append(got, k)\n\t\t\treturn true\n\t\t})\n\t\tif len(got) != len(want) {\n\t\t\tt.Errorf(\"%s: want %d keys, got %d\", tt.name, len(want), len(got))\n\t\t\tcontinue\n\t\t}\n\t\tfor i := range want {\n\t\t\tif tree.compare(got[i], want[i]) != 0 {\n\t\t\t\tt.Errorf(\"%s: want %v at %d, got %v\", tt.name, want[i], i, got[i])\n\t\t\t}\n\t\t}\n\t}\n\n\tvisited := 0\n\ttree.ReverseKeysBelow(genKType(101), false, func(KType) bool {\n\t\tvisited++\n\t\treturn visited < 10\n\t})\n\tif visited != 10 {\n\t\tt.Errorf(\"want to stop after 10 keys, visited %d\", visited)\n\t}\n}\n\nfunc TestRedBlack_Delete(t *testing.T) {\n\tconst n = 200\n\ttree := newRedBlackTest(t, n)\n\tfor _, i := range rand.Perm(n) {\n\t\tif ok := tree.Delete(genKType(2 * i)); ok {\n\t\t\tt.Fatalf(\"deleted %v, which isn't there\", genKType(2*i))\n\t\t}\n\t\tif ok := tree.Delete(genKType(2*i + 1)); !ok {\n\t\t\tt.Fatalf(\"delete %v: not found\", genKType(2*i+1))\n\t\t}\n\t\tif tree.Contains(genKType(2*i + 1)) {\n\t\t\tt.Fatalf(\"%v is still there once deleted\", genKType(2*i+1))\n\t\t}\n\t\tcheckRedBlack(t, tree)\n\t}\n\tif !tree.IsEmpty() {\n\t\tt.Fatalf(\"tree isn't empty once everything is deleted, Size=%d\", tree.Size())\n\t}\n}\n\nfunc TestRedBlack_CountDeleteRange(t *testing.T) {\n\tconst n = 100\n\ttests := []struct {\n\t\tlo, hi int\n\t\tcount  int\n\t}{\n\t\t{10, 21, 6},\n\t\t{11, 11, 1},\n\t\t{12, 12, 0},\n\t\t{21, 10, 0},\n\t\t{0, 2 * n, n},\n\t\t{0, 150, 75},\n\t\t{51, 2 * n, 75},\n\t\t{20, 180, 80},\n\t}\n\tfor _, tt := range tests {\n\t\ttree := newRedBlackTest(t, n)\n\t\tif count := tree.CountRange(genKType(tt.lo), genKType(tt.hi)); count != tt.count {\n\t\t\tt.Errorf(\"want %d keys between %v and %v, counted %d\", tt.count, genKType(tt.lo), genKType(tt.hi), count)\n\t\t}\n\t\tif count := tree.DeleteRange(genKType(tt.lo), genKType(tt.hi)); count != tt.count {\n\t\t\tt.Errorf(\"want %d keys between %v and %v, deleted %d\", tt.count, genKType(tt.lo), genKType(tt.hi), count)\n\t\t}\n\t\tcheckRedBlack(t, tree)\n\t\tif tree.Size() != n-tt.count {\n\t\t\tt.Errorf(\"want Size=%d once the range %v-%v is deleted, was %d\", n-tt.count, genKType(tt.lo), genKType(tt.hi), tree.Size())\n\t\t}\n\t\tfor i := 1; i < 2*n; i += 2 {\n\t\t\tinRange := tt.lo <= i && i <= tt.hi\n\t\t\tif tree.Contains(genKType(i)) == inRange {\n\t\t\t\tt.Errorf(\"deleting the range %v-%v: want %v to be there: %v\", genKType(tt.lo), genKType(tt.hi), genKType(i), !inRange)\n\t\t\t}\n\t\t}\n\t}\n\n\t// the trees rebuilt from the keys left are balanced whatever their size\n\tfor size := 0; size < 100; size++ {\n\t\ttree := newRedBlackTest(t, size+10)\n\t\tif count := tree.DeleteRange(genKType(2*size), genKType(2*size+20)); count != 10 {\n\t\t\tt.Fatalf(\"want 10 keys deleted, deleted %d\", count)\n\t\t}\n\t\tcheckRedBlack(t, tree)\n\t}\n}\n\nfunc TestRedBlack_DeleteMinMax(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\tfor i := 0; i < n/2; i++ {\n\t\tk, ok := tree.DeleteMin()\n\t\tif !ok || tree.compare(k, genKType(2*i+1)) != 0 {\n\t\t\tt.Fatalf(\"want to delete min %v, got %v (%v)\", genKType(2*i+1), k, ok)\n\t\t}\n\t\tcheckRedBlack(t, tree)\n\n\t\tj := n - 1 - i\n\t\tk, ok = tree.DeleteMax()\n\t\tif !ok || tree.compare(k, genKType(2*j+1)) != 0 {\n\t\t\tt.Fatalf(\"want to delete max %v, got %v (%v)\", genKType(2*j+1), k, ok)\n\t\t}\n\t\tcheckRedBlack(t, tree)\n\t}\n\tif !tree.IsEmpty() {\n\t\tt.Fatalf(\"tree isn't empty once everything is deleted, Size=%d\", tree.Size())\n\t}\n}\n"
+	heapTestSrc               = "package heap\n\n// GENERATED CODE!!!\n\n// The tests of this file are also generated along the heaps, by `datagen\n// heap -tests`. They only rely on genKType(i), which returns keys that\n// increase with i, so that they can run against any key type.\n\nimport (\n\t\"math/rand\"\n\t\"testing\"\n)\n\n// newHeapTest returns a heap holding the keys 0 to n-1, put in a random\n// order.\nfunc newHeapTest(t *testing.T, n int) *Heap {\n\th := NewHeap()\n\tfor _, i := range rand.Perm(n) {\n\t\th.Push(genKType(i))\n\t\tcheckHeap(t, h)\n\t}\n\treturn h\n}\n\n// checkHeap verifies that no element of the heap is larger than its parent.\nfunc checkHeap(t *testing.T, h *Heap) {\n\tif len(h.pq) != h.Len()+1 {\n\t\tt.Fatalf(\"heap of Len=%d holds %d elements\", h.Len(), len(h.pq)-1)\n\t}\n\tfor i := 2; i <= h.Len(); i++ {\n\t\tif h.compare(h.pq[i/2], h.pq[i]) < 0 {\n\t\t\tt.Fatalf(\"heap invariant invalidated: [%d] = %v < [%d] = %v\", i/2, h.pq[i/2], i, h.pq[i])\n\t\t}\n\t}\n}\n\nfunc TestHeap_PushPop(t *testing.T) {\n\tconst n = 200\n\th := newHeapTest(t, n)\n\tif h.Len() != n {\n\t\tt.Fatalf(\"want Len=%d, got %d\", n, h.Len())\n\t}\n\tfor i := n - 1; i >= 0; i-- {\n\t\tif k := h.Peek(); h.compare(k, genKType(i)) != 0 {\n\t\t\tt.Fatalf(\"want to peek %v, got %v\", genKType(i), k)\n\t\t}\n\t\tif k := h.Pop(); h.compare(k, genKType(i)) != 0 {\n\t\t\tt.Fatalf(\"want to pop %v, got %v\", genKType(i), k)\n\t\t}\n\t\tcheckHeap(t, h)\n\t}\n\tif h.Len() != 0 {\n\t\tt.Fatalf(\"want Len=0 once everything is popped, got %d\", h.Len())\n\t}\n}\n\nfunc TestHeap_NewWithKeys(t *testing.T) {\n\tconst n = 200\n\tkeys := make([]KType, 0, n)\n\tfor _, i := range rand.Perm(n) {\n\t\tkeys = append(keys, genKType(i))\n\t}\n\th := NewHeap(keys...)\n\tcheckHeap(t, h)\n\tfor i := n - 1; i >= 0; i-- {\n\t\tif k := h.Pop(); h.compare(k, genKType(i)) != 0 {\n\t\t\tt.Fatalf(\"want to pop %v, got %v\", genKType(i), k)\n\t\t}\n\t}\n}\n\nfunc TestHeap_Duplicates(t *testing.T) {\n\th := NewHeap()\n\tfor i := 0; i < 20; i++ {\n\t\th.Push(genKType(0))\n\t\th.Push(genKType(1))\n\t\tcheckHeap(t, h)\n\t}\n\tfor i := 0; i < 40; i++ {\n\t\twant := genKType(1)\n\t\tif i >= 20 {\n\t\t\twant = genKType(0)\n\t\t}\n\t\tif k := h.Pop(); h.compare(k, want) != 0 {\n\t\t\tt.Fatalf(\"%d.th pop: want %v, got %v\", i, want, k)\n\t\t}\n\t\tcheckHeap(t, h)\n\t}\n}\n\nfunc TestHeap_Remove(t *testing.T) {\n\tconst n = 100\n\th := newHeapTest(t, n)\n\n\tif h.Remove(genKType(n)) {\n\t\tt.Errorf(\"removed %v, which is larger than the largest\", genKType(n))\n\t}\n\tfor _, i := range rand.Perm(n) {\n\t\tif !h.Remove(genKType(i)) {\n\t\t\tt.Fatalf(\"should have removed %v\", genKType(i))\n\t\t}\n\t\tcheckHeap(t, h)\n\t\tif h.Len() > 0 && h.Remove(genKType(i)) {\n\t\t\tt.Fatalf(\"removed %v twice\", genKType(i))\n\t\t}\n\t}\n\tif h.Len() != 0 {\n\t\tt.Fatalf(\"want Len=0 once everything is removed, got %d\", h.Len())\n\t}\n}\n\nfunc TestHeap_Fix(t *testing.T) {\n\tconst n = 100\n\th := newHeapTest(t, n)\n\n\t// change the elements in place, as if their comparison value changed\n\tfor i := 0; i < 100; i++ {\n\t\th.pq[1+rand.Intn(h.Len())] = genKType(rand.Intn(2 * n))\n\t\th.Fix()\n\t\tcheckHeap(t, h)\n\t}\n}\n"
+	queueTestSrc              = "package queue\n\n// GENERATED CODE!!!\n\n// The tests of this file are also generated along the queues, by `datagen\n// queue -tests`. They only rely on genKType(i), which returns distinct\n// elements for each i, so that they can run against any element type.\n\nimport (\n\t\"reflect\"\n\t\"testing\"\n)\n\nfunc TestQueue_PushPop(t *testing.T) {\n\tconst n = 1000\n\tq := NewQueue(0)\n\tfor i := 0; i < n; i++ {\n\t\tq.Push(genKType(i))\n\t\tif q.Len() != i+1 {\n\t\t\tt.Fatalf(\"pushing: queue with %d elements has length %d\", i+1, q.Len())\n\t\t}\n\t\tfor j := 0; j < q.Len(); j++ {\n\t\t\tif k := q.Get(j); !reflect.DeepEqual(k, genKType(j)) {\n\t\t\t\tt.Fatalf(\"index %d: want %v, got %v\", j, genKType(j), k)\n\t\t\t}\n\t\t}\n\t}\n\tfor i := 0; i < n; i++ {\n\t\tif k := q.Peek(); !reflect.DeepEqual(k, genKType(i)) {\n\t\t\tt.Fatalf(\"want to peek %v, got %v\", genKType(i), k)\n\t\t}\n\t\tif k := q.Pop(); !reflect.DeepEqual(k, genKType(i)) {\n\t\t\tt.Fatalf(\"want to pop %v, got %v\", genKType(i), k)\n\t\t}\n\t\tif q.Len() != n-i-1 {\n\t\t\tt.Fatalf(\"popping: queue with %d elements has length %d\", n-i-1, q.Len())\n\t\t}\n\t}\n}\n\nfunc TestQueue_TickTock(t *testing.T) {\n\tq := NewQueue(0)\n\tfor i := 0; i < 100; i++ {\n\t\tq.Push(genKType(2 * i))\n\t\tq.Push(genKType(2*i + 1))\n\t\tif k := q.Pop(); !reflect.DeepEqual(k, genKType(i)) {\n\t\t\tt.Fatalf(\"want to pop %v, got %v\", genKType(i), k)\n\t\t}\n\t}\n\tif q.Len() != 100 {\n\t\tt.Fatalf(\"want Len=100, got %d\", q.Len())\n\t}\n}\n\nfunc TestQueue_OutOfRangePanics(t *testing.T) {\n\tq := NewQueue(0)\n\tpanicsQueue(t, \"peeking an empty queue\", func() { q.Peek() })\n\tpanicsQueue(t, \"popping an empty queue\", func() { q.Pop() })\n\n\tq.Push(genKType(0))\n\tpanicsQueue(t, \"getting a negative index\", func() { q.Get(-1) })\n\tpanicsQueue(t, \"getting an index past the length\", func() { q.Get(1) })\n\n\tq.Pop()\n\tpanicsQueue(t, \"peeking an emptied queue\", func() { q.Peek() })\n\tpanicsQueue(t, \"popping an emptied queue\", func() { q.Pop() })\n}\n\nfunc panicsQueue(t *testing.T, name string, f func()) {\n\tdefer func() {\n\t\tif r := recover(); r == nil {\n\t\t\tt.Errorf(\"%s: didn't panic as expected\", name)\n\t\t}\n\t}()\n\tf()\n}\n"
+	redblackbstMapBenchSrc    = "package redblackbst\n\n// GENERATED CODE!!!\n\n// The benchmarks of this file are also generated along the sorted maps, by\n// `datagen smap -bench`. They only rely on genKType(i), which returns keys,\n// and genVType(i), which returns values. When the keys can be used in a Go\n// map, the benchmarks are followed by the same work done with a map, as a\n// baseline.\n\nimport (\n\t\"math/rand\"\n\t\"testing\"\n)\n\n// keysRedBlackBench returns n keys in a random order.\nfunc keysRedBlackBench(n int) []KType {\n\tkeys := make([]KType, 0, n)\n\tfor _, i := range rand.Perm(n) {\n\t\tkeys = append(keys, genKType(i))\n\t}\n\treturn keys\n}\n\n// newRedBlackBench returns a sorted map holding the keys.\nfunc newRedBlackBench(keys []KType) *RedBlack {\n\ttree := NewRedBlack()\n\tv := genVType(0)\n\tfor _, k := range keys {\n\t\ttree.Put(k, v)\n\t}\n\treturn tree\n}\n\n// newRedBlackGoMapBench returns a Go map holding the keys.\nfunc newRedBlackGoMapBench(keys []KType) map[KType]VType {\n\tm := make(map[KType]VType)\n\tv := genVType(0)\n\tfor _, k := range keys {\n\t\tm[k] = v\n\t}\n\treturn m\n}\n\nfunc BenchmarkRedBlack_Put(b *testing.B) {\n\tkeys := keysRedBlackBench(b.N)\n\ttree := NewRedBlack()\n\tv := genVType(0)\n\tb.ResetTimer()\n\tfor _, k := range keys {\n\t\ttree.Put(k, v)\n\t}\n}\n\nfunc BenchmarkRedBlack_Put_GoMap(b *testing.B) {\n\tkeys := keysRedBlackBench(b.N)\n\tm := make(map[KType]VType)\n\tv := genVType(0)\n\tb.ResetTimer()\n\tfor _, k := range keys {\n\t\tm[k] = v\n\t}\n}\n\nfunc BenchmarkRedBlack_Get(b *testing.B) {\n\tkeys := keysRedBlackBench(b.N)\n\ttree := newRedBlackBench(keys)\n\tb.ResetTimer()\n\tfor _, k := range keys {\n\t\ttree.Get(k)\n\t}\n}\n\nfunc BenchmarkRedBlack_Get_GoMap(b *testing.B) {\n\tkeys := keysRedBlackBench(b.N)\n\tm := newRedBlackGoMapBench(keys)\n\tb.ResetTimer()\n\tfor _, k := range keys {\n\t\t_ = m[k]\n\t}\n}\n\nfunc BenchmarkRedBlack_Delete(b *testing.B) {\n\tkeys := keysRedBlackBench(b.N)\n\ttree := newRedBlackBench(keys)\n\tb.ResetTimer()\n\tfor _, k := range keys {\n\t\ttree.Delete(k)\n\t}\n}\n\nfunc BenchmarkRedBlack_Delete_GoMap(b *testing.B) {\n\tkeys := keysRedBlackBench(b.N)\n\tm := newRedBlackGoMapBench(keys)\n\tb.ResetTimer()\n\tfor _, k := range keys {\n\t\tdelete(m, k)\n\t}\n}\n\nfunc BenchmarkRedBlack_DeleteMin(b *testing.B) {\n\ttree := newRedBlackBench(keysRedBlackBench(b.N))\n\tb.ResetTimer()\n\tfor i := 0; i < b.N; i++ {\n\t\ttree.DeleteMin()\n\t}\n}\n"
+	redblackbstSetBenchSrc    = "package redblackbst\n\n// GENERATED CODE!!!\n\n// The benchmarks of this file are also generated along the sorted sets, by\n// `datagen sset -bench`. They only rely on genKType(i), which returns keys.\n// When the keys can be used in a Go map, the benchmarks are followed by the\n// same work done with a map, as a baseline.\n\nimport (\n\t\"math/rand\"\n\t\"testing\"\n)\n\n// keysRedBlackBench returns n keys in a random order.\nfunc keysRedBlackBench(n int) []KType {\n\tkeys := make([]KType, 0, n)\n\tfor _, i := range rand.Perm(n) {\n\t\tkeys = append(keys, genKType(i))\n\t}\n\treturn keys\n}\n\n// newRedBlackBench returns a sorted set holding the keys.\nfunc newRedBlackBench(keys []KType) *RedBlack {\n\ttree := NewRedBlack()\n\tfor _, k := range keys {\n\t\ttree.Put(k)\n\t}\n\treturn tree\n}\n\n// newRedBlackGoMapBench returns a Go map holding the keys.\nfunc newRedBlackGoMapBench(keys []KType) map[KType]struct{} {\n\tm := make(map[KType]struct{})\n\tfor _, k := range keys {\n\t\tm[k] = struct{}{}\n\t}\n\treturn m\n}\n\nfunc BenchmarkRedBlack_Put(b *testing.B) {\n\tkeys := keysRedBlackBench(b.N)\n\ttree := NewRedBlack()\n\tb.ResetTimer()\n\tfor _, k := range keys {\n\t\ttree.Put(k)\n\t}\n}\n\nfunc BenchmarkRedBlack_Put_GoMap(b *testing.B) {\n\tkeys := keysRedBlackBench(b.N)\n\tm := make(map[KType]struct{})\n\tb.ResetTimer()\n\tfor _, k := range keys {\n\t\tm[k] = struct{}{}\n\t}\n}\n\nfunc BenchmarkRedBlack_Contains(b *testing.B) {\n\tkeys := keysRedBlackBench(b.N)\n\ttree := newRedBlackBench(keys)\n\tb.ResetTimer()\n\tfor _, k := range keys {\n\t\ttree.Contains(k)\n\t}\n}\n\nfunc BenchmarkRedBlack_Contains_GoMap(b *testing.B) {\n\tkeys := keysRedBlackBench(b.N)\n\tm := newRedBlackGoMapBench(keys)\n\tb.ResetTimer()\n\tfor _, k := range keys {\n\t\t_ = m[k]\n\t}\n}\n\nfunc BenchmarkRedBlack_Delete(b *testing.B) {\n\tkeys := keysRedBlackBench(b.N)\n\ttree := newRedBlackBench(keys)\n\tb.ResetTimer()\n\tfor _, k := range keys {\n\t\ttree.Delete(k)\n\t}\n}\n\nfunc BenchmarkRedBlack_Delete_GoMap(b *testing.B) {\n\tkeys := keysRedBlackBench(b.N)\n\tm := newRedBlackGoMapBench(keys)\n\tb.ResetTimer()\n\tfor _, k := range keys {\n\t\tdelete(m, k)\n\t}\n}\n\nfunc BenchmarkRedBlack_DeleteMin(b *testing.B) {\n\ttree := newRedBlackBench(keysRedBlackBench(b.N))\n\tb.ResetTimer()\n\tfor i := 0; i < b.N; i++ {\n\t\ttree.DeleteMin()\n\t}\n}\n"
+	heapBenchSrc              = "package heap\n\n// GENERATED CODE!!!\n\n// The benchmarks of this file are also generated along the heaps, by\n// `datagen heap -bench`. They only rely on genKType(i), which returns the\n// keys of the benchmarks. Each benchmark is followed by the same work done\n// with container/heap, as a baseline.\n\nimport (\n\tstdheap \"container/heap\"\n\t\"math/rand\"\n\t\"testing\"\n)\n\n// keysHeapBench returns n keys in a random order.\nfunc keysHeapBench(n int) []KType {\n\tkeys := make([]KType, 0, n)\n\tfor _, i := range rand.Perm(n) {\n\t\tkeys = append(keys, genKType(i))\n\t}\n\treturn keys\n}\n\n// baselineHeap is a container/heap of the same keys, with the same\n// comparison rules.\ntype baselineHeap []KType\n\nfunc (h baselineHeap) Len() int            { return len(h) }\nfunc (h baselineHeap) Less(i, j int) bool  { return Heap{}.compare(h[i], h[j]) > 0 }\nfunc (h baselineHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }\nfunc (h *baselineHeap) Push(x interface{}) { *h = append(*h, x.(KType)) }\nfunc (h *baselineHeap) Pop() interface{} {\n\told := *h\n\tx := old[len(old)-1]\n\t*h = old[:len(old)-1]\n\treturn x\n}\n\nfunc BenchmarkHeap_Push(b *testing.B) {\n\tkeys := keysHeapBench(b.N)\n\th := NewHeap()\n\tb.ResetTimer()\n\tfor i := 0; i < b.N; i++ {\n\t\th.Push(keys[i])\n\t}\n}\n\nfunc BenchmarkHeap_Push_ContainerHeap(b *testing.B) {\n\tkeys := keysHeapBench(b.N)\n\th := &baselineHeap{}\n\tb.ResetTimer()\n\tfor i := 0; i < b.N; i++ {\n\t\tstdheap.Push(h, keys[i])\n\t}\n}\n\nfunc BenchmarkHeap_Pop(b *testing.B) {\n\th := NewHeap(keysHeapBench(b.N)...)\n\tb.ResetTimer()\n\tfor i := 0; i < b.N; i++ {\n\t\th.Pop()\n\t}\n}\n\nfunc BenchmarkHeap_Pop_ContainerHeap(b *testing.B) {\n\th := baselineHeap(keysHeapBench(b.N))\n\tstdheap.Init(&h)\n\tb.ResetTimer()\n\tfor i := 0; i < b.N; i++ {\n\t\tstdheap.Pop(&h)\n\t}\n}\n\nfunc BenchmarkHeap_PushPop(b *testing.B) {\n\tconst n = 10000\n\tkeys := keysHeapBench(n)\n\th := NewHeap(make([]KType, 0, n)...)\n\tb.ResetTimer()\n\tfor i := 0; i < b.N; i++ {\n\t\tfor _, k := range keys {\n\t\t\th.Push(k)\n\t\t}\n\t\tfor h.Len() > 0 {\n\t\t\th.Pop()\n\t\t}\n\t}\n}\n\nfunc BenchmarkHeap_PushPop_ContainerHeap(b *testing.B) {\n\tconst n = 10000\n\tkeys := keysHeapBench(n)\n\th := make(baselineHeap, 0, n)\n\tb.ResetTimer()\n\tfor i := 0; i < b.N; i++ {\n\t\tfor _, k := range keys {\n\t\t\tstdheap.Push(&h, k)\n\t\t}\n\t\tfor h.Len() > 0 {\n\t\t\tstdheap.Pop(&h)\n\t\t}\n\t}\n}\n"
+	queueBenchSrc             = "package queue\n\n// GENERATED CODE!!!\n\n// The benchmarks of this file are also generated along the queues, by\n// `datagen queue -bench`. They only rely on genKType(i), which returns the\n// elements of the benchmarks. Each benchmark is followed by the same work\n// done with container/list, as a baseline.\n\nimport (\n\t\"container/list\"\n\t\"testing\"\n)\n\n// elemsQueueBench returns n elements.\nfunc elemsQueueBench(n int) []KType {\n\telems := make([]KType, 0, n)\n\tfor i := 0; i < n; i++ {\n\t\telems = append(elems, genKType(i))\n\t}\n\treturn elems\n}\n\nfunc BenchmarkQueue_Push(b *testing.B) {\n\telems := elemsQueueBench(b.N)\n\tq := NewQueue(0)\n\tb.ResetTimer()\n\tfor _, e := range elems {\n\t\tq.Push(e)\n\t}\n}\n\nfunc BenchmarkQueue_Push_List(b *testing.B) {\n\telems := elemsQueueBench(b.N)\n\tq := list.New()\n\tb.ResetTimer()\n\tfor _, e := range elems {\n\t\tq.PushBack(e)\n\t}\n}\n\nfunc BenchmarkQueue_Pop(b *testing.B) {\n\tq := NewQueue(0)\n\tfor _, e := range elemsQueueBench(b.N) {\n\t\tq.Push(e)\n\t}\n\tb.ResetTimer()\n\tfor i := 0; i < b.N; i++ {\n\t\tq.Pop()\n\t}\n}\n\nfunc BenchmarkQueue_Pop_List(b *testing.B) {\n\tq := list.New()\n\tfor _, e := range elemsQueueBench(b.N) {\n\t\tq.PushBack(e)\n\t}\n\tb.ResetTimer()\n\tfor i := 0; i < b.N; i++ {\n\t\tq.Remove(q.Front())\n\t}\n}\n\nfunc BenchmarkQueue_Serial(b *testing.B) {\n\telems := elemsQueueBench(b.N)\n\tq := NewQueue(0)\n\tb.ResetTimer()\n\tfor _, e := range elems {\n\t\tq.Push(e)\n\t}\n\tfor i := 0; i < b.N; i++ {\n\t\tq.Pop()\n\t}\n}\n\nfunc BenchmarkQueue_Serial_List(b *testing.B) {\n\telems := elemsQueueBench(b.N)\n\tq := list.New()\n\tb.ResetTimer()\n\tfor _, e := range elems {\n\t\tq.PushBack(e)\n\t}\n\tfor i := 0; i < b.N; i++ {\n\t\tq.Remove(q.Front())\n\t}\n}\n\nfunc BenchmarkQueue_TickTock(b *testing.B) {\n\telems := elemsQueueBench(b.N)\n\tq := NewQueue(0)\n\tb.ResetTimer()\n\tfor _, e := range elems {\n\t\tq.Push(e)\n\t\tq.Pop()\n\t}\n}\n\nfunc BenchmarkQueue_TickTock_List(b *testing.B) {\n\telems := elemsQueueBench(b.N)\n\tq := list.New()\n\tb.ResetTimer()\n\tfor _, e := range elems {\n\t\tq.PushBack(e)\n\t\tq.Remove(q.Front())\n\t}\n}\n"
+	redblackbstMapIterSrc     = "//go:build go1.23\n\npackage redblackbst\n\nimport \"iter\"\n\n// The iterators of this file are only generated along the sorted maps of\n// the modules whose Go version supports them, from Go 1.23 on.\n\n// All returns the keys/values of the sorted map, in increasing order.\nfunc (r RedBlack) All() iter.Seq2[KType, VType] {\n\treturn func(yield func(KType, VType) bool) {\n\t\tr.Keys(yield)\n\t}\n}\n\n// Backward returns the keys/values of the sorted map, in decreasing order.\nfunc (r RedBlack) Backward() iter.Seq2[KType, VType] {\n\treturn func(yield func(KType, VType) bool) {\n\t\tr.ReverseKeys(yield)\n\t}\n}\n\n// Range returns the keys/values between lo and hi in the sorted map, in\n// increasing order.\nfunc (r RedBlack) Range(lo, hi KType) iter.Seq2[KType, VType] {\n\treturn func(yield func(KType, VType) bool) {\n\t\tr.RangedKeys(lo, hi, yield)\n\t}\n}\n\n// AllKeys returns the keys of the sorted map, in increasing order.\nfunc (r RedBlack) AllKeys() iter.Seq[KType] {\n\treturn func(yield func(KType) bool) {\n\t\tr.Keys(func(k KType, _ VType) bool { return yield(k) })\n\t}\n}\n\n// AllValues returns the values of the sorted map, in the increasing order\n// of their keys.\nfunc (r RedBlack) AllValues() iter.Seq[VType] {\n\treturn func(yield func(VType) bool) {\n\t\tr.Keys(func(_ KType, v VType) bool { return yield(v) })\n\t}\n}\n"
+	redblackbstSetIterSrc     = "//go:build go1.23\n\npackage redblackbst\n\nimport \"iter\"\n\n// The iterators of this file are only generated along the sorted sets of\n// the modules whose Go version supports them, from Go 1.23 on.\n\n// All returns the keys of the sorted set, in increasing order.\nfunc (r RedBlack) All() iter.Seq[KType] {\n\treturn func(yield func(KType) bool) {\n\t\tr.Keys(yield)\n\t}\n}\n"
+	heapIterSrc               = "//go:build go1.23\n\npackage heap\n\nimport \"iter\"\n\n// The iterators of this file are only generated along the heaps of the\n// modules whose Go version supports them, from Go 1.23 on.\n\n// Drain returns the elements of the heap in decreasing order, removing\n// them as they're iterated over. The elements left once the iteration\n// stops stay in the heap.\nfunc (h *Heap) Drain() iter.Seq[KType] {\n\treturn func(yield func(KType) bool) {\n\t\tfor h.Len() > 0 {\n\t\t\tif !yield(h.Pop()) {\n\t\t\t\treturn\n\t\t\t}\n\t\t}\n\t}\n}\n"
+	queueIterSrc              = "//go:build go1.23\n\npackage queue\n\nimport \"iter\"\n\n// The iterators of this file are only generated along the queues of the\n// modules whose Go version supports them, from Go 1.23 on.\n\n// All returns the elements of the queue, from its front to its end,\n// without removing them.\nfunc (q *Queue) All() iter.Seq[KType] {\n\treturn func(yield func(KType) bool) {\n\t\tfor i := 0; i < q.Len(); i++ {\n\t\t\tif !yield(q.Get(i)) {\n\t\t\t\treturn\n\t\t\t}\n\t\t}\n\t}\n}\n"
+	redblackbstMapIterTestSrc = "//go:build go1.23\n\npackage redblackbst\n\n// The tests of this file are also generated along the sorted maps that\n// have iterators, by `datagen smap -tests`.\n\nimport (\n\t\"reflect\"\n\t\"testing\"\n)\n\nfunc TestRedBlack_All(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\n\ti := 1\n\tfor k, v := range tree.All() {\n\t\tif tree.compare(k, genKType(i)) != 0 || !reflect.DeepEqual(v, genVType(i)) {\n\t\t\tt.Fatalf(\"want %v, got %v\", genKType(i), k)\n\t\t}\n\t\ti += 2\n\t}\n\tif i != 2*n+1 {\n\t\tt.Errorf(\"iterated over %d keys, want %d\", i/2, n)\n\t}\n\n\ti = 2*n - 1\n\tfor k := range tree.Backward() {\n\t\tif tree.compare(k, genKType(i)) != 0 {\n\t\t\tt.Fatalf(\"want %v, got %v\", genKType(i), k)\n\t\t}\n\t\tif i -= 2; i < n {\n\t\t\tbreak\n\t\t}\n\t}\n\tif i >= n {\n\t\tt.Errorf(\"want to stop iterating backward at %v\", genKType(n))\n\t}\n\n\ti = 11\n\tfor k := range tree.Range(genKType(10), genKType(21)) {\n\t\tif tree.compare(k, genKType(i)) != 0 {\n\t\t\tt.Fatalf(\"want %v, got %v\", genKType(i), k)\n\t\t}\n\t\ti += 2\n\t}\n\tif i != 23 {\n\t\tt.Errorf(\"want keys up to %v, stopped before %v\", genKType(21), genKType(i))\n\t}\n\n\ti = 1\n\tfor k := range tree.AllKeys() {\n\t\tif tree.compare(k, genKType(i)) != 0 {\n\t\t\tt.Fatalf(\"want %v, got %v\", genKType(i), k)\n\t\t}\n\t\ti += 2\n\t}\n\ti = 1\n\tfor v := range tree.AllValues() {\n\t\tif !reflect.DeepEqual(v, genVType(i)) {\n\t\t\tt.Fatalf(\"want %v, got %v\", genVType(i), v)\n\t\t}\n\t\ti += 2\n\t}\n}\n"
+	redblackbstSetIterTestSrc = "//go:build go1.23\n\npackage redblackbst\n\n// The tests of this file are also generated along the sorted sets that\n// have iterators, by `datagen sset -tests`.\n\nimport \"testing\"\n\nfunc TestRedBlack_All(t *testing.T) {\n\tconst n = 100\n\ttree := newRedBlackTest(t, n)\n\n\ti := 1\n\tfor k := range tree.All() {\n\t\tif tree.compare(k, genKType(i)) != 0 {\n\t\t\tt.Fatalf(\"want %v, got %v\", genKType(i), k)\n\t\t}\n\t\tif i += 2; i > n {\n\t\t\tbreak\n\t\t}\n\t}\n\tif i <= n {\n\t\tt.Errorf(\"want to stop iterating at %v\", genKType(n))\n\t}\n}\n"
+	heapIterTestSrc           = "//go:build go1.23\n\npackage heap\n\n// The tests of this file are also generated along the heaps that have\n// iterators, by `datagen heap -tests`.\n\nimport \"testing\"\n\nfunc TestHeap_Drain(t *testing.T) {\n\tconst n = 100\n\th := newHeapTest(t, n)\n\n\ti := n - 1\n\tfor k := range h.Drain() {\n\t\tif h.compare(k, genKType(i)) != 0 {\n\t\t\tt.Fatalf(\"want %v, got %v\", genKType(i), k)\n\t\t}\n\t\tif i--; i < n/2 {\n\t\t\tbreak\n\t\t}\n\t}\n\tif h.Len() != n/2 {\n\t\tt.Fatalf(\"want %d elements left once the iteration stopped, got %d\", n/2, h.Len())\n\t}\n\tcheckHeap(t, h)\n\tfor range h.Drain() {\n\t}\n\tif h.Len() != 0 {\n\t\tt.Errorf(\"want the heap drained, %d elements left\", h.Len())\n\t}\n}\n"
+	queueIterTestSrc          = "//go:build go1.23\n\npackage queue\n\n// The tests of this file are also generated along the queues that have\n// iterators, by `datagen queue -tests`.\n\nimport (\n\t\"reflect\"\n\t\"testing\"\n)\n\nfunc TestQueue_All(t *testing.T) {\n\tconst n = 100\n\tq := NewQueue(0)\n\tfor i := 0; i < n; i++ {\n\t\tq.Push(genKType(i))\n\t}\n\tq.Pop()\n\n\ti := 1\n\tfor k := range q.All() {\n\t\tif !reflect.DeepEqual(k, genKType(i)) {\n\t\t\tt.Fatalf(\"want %v, got %v\", genKType(i), k)\n\t\t}\n\t\ti++\n\t}\n\tif i != n || q.Len() != n-1 {\n\t\tt.Errorf(\"iterated over %d elements of %d, want %d\", i-1, q.Len(), n-1)\n\t}\n}\n"
 )
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	testsFlag = cli.BoolFlag{
+		Name:  "tests",
+		Usage: "also generate the tests of the datastructure, in a _test.go file next to the -o file",
+	}
+	benchFlag = cli.BoolFlag{
+		Name:  "bench",
+		Usage: "also generate benchmarks of the datastructure, in a _bench_test.go file next to the -o file",
+	}
+	keyGenFlag = cli.StringFlag{
+		Name:  "key-gen",
+		Usage: "name of a `func(i int) T` returning distinct keys that increase with i, or decrease for min-heaps and sorted maps and sets in desc -order, for -tests and -bench (default: derived from the key type)",
+	}
+	valGenFlag = cli.StringFlag{
+		Name:  "val-gen",
+		Usage: "name of a `func(i int) T` returning values, for -tests and -bench (default: derived from the value type)",
+	}
+)
+
+// testSuite is the template of the tests or benchmarks generated along a
+// datastructure.
+type testSuite struct {
+	src string
+	// placeholder type the tests are about. The functions and types of
+	// the tests that are named after it are renamed after the generated
+	// type, so that the tests of many datastructures can live in one
+	// package.
+	placeholder string
+	// flag asking for the suite, which also names its file and key and
+	// value generators.
+	flag string
+	// iterSrc are the tests of the iterators, added to the suite when the
+	// datastructure has them.
+	iterSrc string
+}
+
+var (
+	heapTests      = testSuite{src: heapTestSrc, placeholder: "Heap", flag: "tests", iterSrc: heapIterTestSrc}
+	queueTests     = testSuite{src: queueTestSrc, placeholder: "Queue", flag: "tests", iterSrc: queueIterTestSrc}
+	sortedMapTests = testSuite{src: redblackbstMapTestSrc, placeholder: "RedBlack", flag: "tests", iterSrc: redblackbstMapIterTestSrc}
+	sortedSetTests = testSuite{src: redblackbstSetTestSrc, placeholder: "RedBlack", flag: "tests", iterSrc: redblackbstSetIterTestSrc}
+
+	heapBenchmarks      = testSuite{src: heapBenchSrc, placeholder: "Heap", flag: "bench"}
+	queueBenchmarks     = testSuite{src: queueBenchSrc, placeholder: "Queue", flag: "bench"}
+	sortedMapBenchmarks = testSuite{src: redblackbstMapBenchSrc, placeholder: "RedBlack", flag: "bench"}
+	sortedSetBenchmarks = testSuite{src: redblackbstSetBenchSrc, placeholder: "RedBlack", flag: "bench"}
+)
+
+// goMapBaseline is found in the names of the benchmarks, and of their
+// helpers, that compare a datastructure to a Go map. They're left out when
+// the keys can't be used in a Go map.
+const goMapBaseline = "GoMap"
+
+// generateSuites generates the suites asked for on the command line, for
+// the datastructure generated for out. The keys are in reverse order when
+// the datastructure's order is reversed.
+func generateSuites(ctx *cli.Context, out output, idents map[string]string, ktype, vtype *goType, reverse bool, suites ...testSuite) ([]*generatedTests, error) {
+	var generated []*generatedTests
+	for _, s := range suites {
+		if !ctx.Bool(s.flag) {
+			continue
+		}
+		tests, err := s.generate(ctx, out, idents, ktype, vtype, reverse)
+		if err != nil {
+			return nil, err
+		}
+		generated = append(generated, tests)
+	}
+	return generated, nil
+}
+
+// generatedTests are the tests of a datastructure, written next to it.
+type generatedTests struct {
+	out output
+	src []byte
+}
+
+// generate the tests of the datastructure generated for out, with the
+// same idents. The value type is nil for datastructures that only hold
+// keys.
+func (s testSuite) generate(ctx *cli.Context, out output, idents map[string]string, ktype, vtype *goType, reverse bool) (*generatedTests, error) {
+	tests := out
+	// the generators of the tests and benchmarks are named apart, so
+	// that both can be generated for one datastructure
+	suffix, what, infix := "_test.go", "tests", ""
+	if s.flag == "bench" {
+		suffix, what, infix = "_bench_test.go", "benchmarks", "Bench"
+	}
+	tests.filename = strings.TrimSuffix(out.filename, ".go") + suffix
+
+	// within the names of the tests, the type is exported for them to be
+	// run even when it isn't, like in TestIntHeapPush for an intHeap. The
+	// tests of an IntHeap in the same package have those names already,
+	// so they're told apart by a number, like in TestIntHeap2Push.
+	typeName := idents[s.placeholder]
+	declared, err := declaredNames(tests, typeName)
+	if err != nil {
+		return nil, err
+	}
+	exported := upperFirst(typeName)
+	for i := 2; declared["gen"+exported+infix+"Key"]; i++ {
+		exported = fmt.Sprintf("%s%d", upperFirst(typeName), i)
+	}
+	testIdents := map[string]string{
+		"genKType": "gen" + exported + infix + "Key",
+		"genVType": "gen" + exported + infix + "Value",
+	}
+	for placeholder, repl := range idents {
+		testIdents[placeholder] = repl
+	}
+
+	tmpl := newTemplate(s.src)
+	if err := tmpl.replaceComment("// GENERATED CODE!!!", ""); err != nil {
+		return nil, err
+	}
+	if s.iterSrc != "" && out.iterators() {
+		if err := tmpl.appendTemplate(s.iterSrc); err != nil {
+			return nil, err
+		}
+	}
+	names, err := tmpl.declNames()
+	if err != nil {
+		return nil, err
+	}
+	goMap := true
+	if s.flag == "bench" {
+		typ, err := typeOf(out, ktype)
+		if err != nil {
+			return nil, err
+		}
+		goMap = types.Comparable(typ)
+	}
+	for _, name := range names {
+		if !goMap && strings.Contains(name, goMapBaseline) {
+			if err := tmpl.removeFunc(name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if strings.HasPrefix(name, s.placeholder) {
+			testIdents[name] = typeName + strings.TrimPrefix(name, s.placeholder)
+		} else if strings.Contains(name, s.placeholder) {
+			testIdents[name] = strings.Replace(name, s.placeholder, exported, 1)
+		}
+	}
+
+	if err := tmpl.addImports(ktype); err != nil {
+		return nil, err
+	}
+	gen, err := testGenerator("genKType", "KType", ktype, ctx.String("key-gen"), false, reverse, what)
+	if err != nil {
+		return nil, fmt.Errorf("%v, give one with -key-gen", err)
+	}
+	if err := gen.addTo(tmpl); err != nil {
+		return nil, err
+	}
+	if vtype != nil {
+		if err := tmpl.addImports(vtype); err != nil {
+			return nil, err
+		}
+		gen, err := testGenerator("genVType", "VType", vtype, ctx.String("val-gen"), true, false, what)
+		if err != nil {
+			return nil, fmt.Errorf("%v, give one with -val-gen", err)
+		}
+		if err := gen.addTo(tmpl); err != nil {
+			return nil, err
+		}
+	}
+
+	src, err := tmpl.rewrite(out.pkgName, testIdents)
+	if err != nil {
+		return nil, err
+	}
+	return &generatedTests{out: tests, src: src}, nil
+}
+
+// write the tests, once the datastructure they test has been written.
+func (t *generatedTests) write() error {
+	src, err := finish(t.out, t.src)
+	if err != nil {
+		return fmt.Errorf("%s: %v", filepath.Base(t.out.filename), err)
+	}
+	return t.out.write(src)
+}
+
+// generator is the declaration of a function generating the keys or values
+// used by the tests or benchmarks.
+type generator struct {
+	decl    string
+	imports []string
+}
+
+func (g generator) addTo(tmpl *template) error {
+	for _, path := range g.imports {
+		if err := tmpl.addImport("", path); err != nil {
+			return err
+		}
+	}
+	tmpl.appendDecl(g.decl)
+	return nil
+}
+
+// testGenerator declares the function `name`, returning the i-th value of
+// typ, for the tests or benchmarks named by what. Unless it's a user given
+// function, the generator is derived from the type: keys must be distinct
+// and increase with i, or decrease when they're in reverse order, while
+// values can fall back to the zero value of their type.
+func testGenerator(name, placeholder string, typ *goType, userFunc string, zero, reverse bool, what string) (generator, error) {
+	declare := func(expr string, imports ...string) generator {
+		return generator{
+			decl:    fmt.Sprintf("func %s(i int) %s { return %s }", name, placeholder, expr),
+			imports: imports,
+		}
+	}
+
+	if userFunc != "" {
+		if !isFuncName(userFunc) {
+			return generator{}, fmt.Errorf("%q isn't the name of a function", userFunc)
+		}
+		return declare(userFunc + "(i)"), nil
+	}
+
+	// ^i decreases with i, and so does every conversion of it, even to
+	// unsigned integers; the strings are padded to the width of the
+	// largest uint64 for it to hold with them too
+	n, digits := "i", `fmt.Sprintf("%08d", i)`
+	if reverse {
+		n, digits = "^i", `fmt.Sprintf("%020d", ^uint64(i))`
+	}
+	switch typ.name {
+	case "int", "int16", "int32", "int64", "rune",
+		"uint", "uint16", "uint32", "uint64", "uintptr",
+		"float32", "float64":
+		return declare(placeholder + "(" + n + ")"), nil
+	case "string":
+		return declare(digits, "fmt"), nil
+	case "[]byte":
+		return declare("[]byte("+digits+")", "fmt"), nil
+	case "time.Time":
+		return declare("time.Unix(int64(" + n + "), 0)"), nil
+	}
+	// the other slices are compared by their length, which can't decrease
+	// forever
+	if slice, ok := typ.expr.(*ast.ArrayType); ok && slice.Len == nil && !reverse {
+		return declare(fmt.Sprintf("make(%s, i)", placeholder)), nil
+	}
+	if zero {
+		return declare(fmt.Sprintf("*new(%s)", placeholder)), nil
+	}
+	return generator{}, fmt.Errorf("can't derive the %s used by the %s", typ.name, what)
+}
+
+// isFuncName tells if name refers to a function, possibly from another
+// package.
+func isFuncName(name string) bool {
+	for _, part := range strings.SplitN(name, ".", 2) {
+		if !token.IsIdentifier(part) {
+			return false
+		}
+	}
+	return true
+}
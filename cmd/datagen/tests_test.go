@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTestGenerator(t *testing.T) {
+	tests := []struct {
+		typ      string
+		userFunc string
+		zero     bool
+		reverse  bool
+		want     string
+		wantErr  bool
+	}{
+		{typ: "int", want: "return KType(i)"},
+		{typ: "string", want: `return fmt.Sprintf("%08d", i)`},
+		{typ: "[]byte", want: `return []byte(fmt.Sprintf("%08d", i))`},
+		{typ: "[]*Item", want: "return make(KType, i)"},
+		{typ: "time.Time", want: "return time.Unix(int64(i), 0)"},
+		{typ: "int", reverse: true, want: "return KType(^i)"},
+		{typ: "uint16", reverse: true, want: "return KType(^i)"},
+		{typ: "string", reverse: true, want: `return fmt.Sprintf("%020d", ^uint64(i))`},
+		{typ: "time.Time", reverse: true, want: "return time.Unix(int64(^i), 0)"},
+		{typ: "*Item", userFunc: "makeItem", want: "return makeItem(i)"},
+		{typ: "*Item", userFunc: "items.Make", want: "return items.Make(i)"},
+		{typ: "*Item", zero: true, want: "return *new(KType)"},
+		// can't be distinct and increasing
+		{typ: "*Item", wantErr: true},
+		{typ: "int8", wantErr: true},
+		{typ: "bool", wantErr: true},
+		{typ: "[]*Item", reverse: true, wantErr: true},
+		{typ: "*Item", userFunc: "makeItem()", wantErr: true},
+	}
+	for _, tt := range tests {
+		typ, err := parseType(tt.typ, output{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		gen, err := testGenerator("genKType", "KType", typ, tt.userFunc, tt.zero, tt.reverse, "tests")
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: want an error, got %q", tt.typ, gen.decl)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tt.typ, err)
+			continue
+		}
+		if !strings.Contains(gen.decl, tt.want) {
+			t.Errorf("%q: want %q in %q", tt.typ, tt.want, gen.decl)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	pathpkg "path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// goType is a type given on the command line, as it must be written in the
+// generated code.
+type goType struct {
+	// expr is the type expression.
+	expr ast.Expr
+	// name is the canonical spelling of expr.
+	name string
+	// imports needed to refer to the type.
+	imports []importSpec
+}
+
+// importSpec is an import needed by the generated code.
+type importSpec struct {
+	name string
+	path string
+}
+
+// parseType verifies that typ is a Go type expression. Types from other
+// packages can be qualified by the package's name, like `time.Time`, or
+// by its full import path, like `github.com/org/pkg.Type`. Types from the
+// output's own package are left unqualified.
+func parseType(typ string, out output) (*goType, error) {
+	qualified, imports := resolveQualifiers(typ, out)
+
+	expr, err := parser.ParseExpr(qualified)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid type: %v", typ, err)
+	}
+	if !isTypeExpr(expr) {
+		return nil, fmt.Errorf("%q is not a type", typ)
+	}
+	buf := bytes.NewBuffer(nil)
+	if err := printer.Fprint(buf, token.NewFileSet(), expr); err != nil {
+		return nil, fmt.Errorf("printing type %q: %v", typ, err)
+	}
+	return &goType{expr: expr, name: buf.String(), imports: imports}, nil
+}
+
+// qualifiedIdent matches the qualified identifiers in a type, along with
+// whatever import path precedes them.
+var qualifiedIdent = regexp.MustCompile(`[\w.~/-]+\.[\pL_][\pL\pN_]*`)
+
+// resolveQualifiers rewrites the qualified identifiers of typ so they're
+// qualified by a package name, and returns the imports of those packages.
+func resolveQualifiers(typ string, out output) (string, []importSpec) {
+	matches := qualifiedIdent.FindAllString(typ, -1)
+	if len(matches) == 0 {
+		return typ, nil
+	}
+
+	// qualifiers are either import paths or the name of a package already
+	// imported by the output package
+	known := packageImports(out.dir)
+
+	var paths []string
+	for _, match := range matches {
+		path := match[:strings.LastIndex(match, ".")]
+		if p, ok := known[path]; ok {
+			path = p
+		}
+		paths = append(paths, path)
+	}
+	names := packageNames(out.dir, paths)
+
+	var (
+		imports []importSpec
+		repls   []string
+		// package name -> import path, to avoid importing two packages
+		// under the same name
+		used = make(map[string]string)
+	)
+	for i, match := range matches {
+		ident := match[strings.LastIndex(match, ".")+1:]
+		if paths[i] == out.pkgPath {
+			repls = append(repls, ident)
+			continue
+		}
+		name := names[paths[i]]
+		for n := 2; used[name] != "" && used[name] != paths[i]; n++ {
+			name = fmt.Sprintf("%s%d", names[paths[i]], n)
+		}
+		if used[name] == "" {
+			used[name] = paths[i]
+			spec := importSpec{path: paths[i]}
+			if name != pathpkg.Base(paths[i]) {
+				spec.name = name
+			}
+			imports = append(imports, spec)
+		}
+		repls = append(repls, name+"."+ident)
+	}
+
+	i := 0
+	typ = qualifiedIdent.ReplaceAllStringFunc(typ, func(string) string {
+		i++
+		return repls[i-1]
+	})
+	return typ, imports
+}
+
+// packageImports returns the import paths of the packages imported by the
+// files in dir, keyed by the name they're imported as.
+func packageImports(dir string) map[string]string {
+	imports := make(map[string]string)
+
+	filenames, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	var unnamed []string
+	for _, filename := range filenames {
+		file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range file.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			switch {
+			case imp.Name == nil:
+				unnamed = append(unnamed, path)
+			case imp.Name.Name != "_" && imp.Name.Name != ".":
+				imports[imp.Name.Name] = path
+			}
+		}
+	}
+	for path, name := range packageNames(dir, unnamed) {
+		if _, ok := imports[name]; !ok {
+			imports[name] = path
+		}
+	}
+	return imports
+}
+
+// packageNames returns the name of the packages found at each import path.
+// Packages that can't be loaded are assumed to be named after the last
+// element of their import path.
+func packageNames(dir string, paths []string) map[string]string {
+	names := make(map[string]string)
+	if len(paths) == 0 {
+		return names
+	}
+	for _, path := range paths {
+		names[path] = guessPackageName(path)
+	}
+	cfg := &packages.Config{Mode: packages.NeedName, Dir: dir}
+	pkgs, err := packages.Load(cfg, paths...)
+	if err != nil {
+		return names
+	}
+	for _, pkg := range pkgs {
+		if pkg.Name != "" {
+			names[pkg.PkgPath] = pkg.Name
+		}
+	}
+	return names
+}
+
+// guessPackageName follows the conventions of import paths, where
+// `gopkg.in/yaml.v2` is package `yaml` and `github.com/org/pkg/v2` is
+// package `pkg`.
+func guessPackageName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	if i := strings.Index(name, "."); i > 0 {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	return strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, name)
+}
+
+func isTypeExpr(expr ast.Expr) bool {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return true
+	case *ast.SelectorExpr:
+		_, ok := t.X.(*ast.Ident)
+		return ok
+	case *ast.StarExpr:
+		return isTypeExpr(t.X)
+	case *ast.ParenExpr:
+		return isTypeExpr(t.X)
+	case *ast.ArrayType, *ast.MapType, *ast.ChanType,
+		*ast.FuncType, *ast.InterfaceType, *ast.StructType:
+		return true
+	default:
+		return false
+	}
+}
+
+// typeIdent derives an exported identifier from a type expression, to be
+// used when naming the datastructures generated for that type. For
+// instance `*Item` gives `Item`, `[]string` gives `Strings` and
+// `map[string]int` gives `StringToIntMap`.
+func typeIdent(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return strings.Title(t.Name)
+	case *ast.SelectorExpr:
+		// the package is left out, `time.Time` is just a `Time`
+		return typeIdent(t.Sel)
+	case *ast.StarExpr:
+		return typeIdent(t.X)
+	case *ast.ParenExpr:
+		return typeIdent(t.X)
+	case *ast.ArrayType:
+		if t.Len == nil {
+			return typeIdent(t.Elt) + "s"
+		}
+		return typeIdent(t.Elt) + "Array"
+	case *ast.MapType:
+		return typeIdent(t.Key) + "To" + typeIdent(t.Value) + "Map"
+	case *ast.ChanType:
+		return typeIdent(t.Value) + "Chan"
+	case *ast.FuncType:
+		return "Func"
+	case *ast.InterfaceType:
+		return "Interface"
+	case *ast.StructType:
+		return "Struct"
+	default:
+		panic(fmt.Sprintf("unsupported type expression %T", expr))
+	}
+}
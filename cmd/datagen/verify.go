@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/urfave/cli.v1"
+)
+
+func verifyCommand() cli.Command {
+	return cli.Command{
+		Name:      "verify",
+		Usage:     "Check that the files generated by datagen in a module are up to date.",
+		ArgsUsage: "[dir]",
+		Description: `Regenerate, in memory, the files generated by datagen that are found in
+dir, or in the module of the current directory, with the command recorded
+in their header. The files that differ, because they were edited or
+generated by another version of datagen, are reported with a unified diff
+and make the command fail.`,
+		Action: func(ctx *cli.Context) error {
+			root, err := walkRoot(ctx.Args().First())
+			if err != nil {
+				return err
+			}
+			gens, err := findGenerated(root)
+			if err != nil {
+				return err
+			}
+
+			want := make(map[string][]byte)
+			if ctx.App.Metadata == nil {
+				ctx.App.Metadata = make(map[string]interface{})
+			}
+			ctx.App.Metadata[captureKey] = captureFunc(func(filename string, src []byte) error {
+				want[filename] = src
+				return nil
+			})
+			defer delete(ctx.App.Metadata, captureKey)
+			for _, gen := range gens {
+				if err := gen.run(ctx.App); err != nil {
+					return err
+				}
+			}
+
+			var filenames []string
+			for filename := range want {
+				filenames = append(filenames, filename)
+			}
+			sort.Strings(filenames)
+			stale := 0
+			for _, filename := range filenames {
+				got, err := ioutil.ReadFile(filename)
+				if err != nil && !os.IsNotExist(err) {
+					return err
+				}
+				name, err := filepath.Rel(root, filename)
+				if err != nil {
+					name = filename
+				}
+				if diff := unifiedDiff(name, name+" (generated)", got, want[filename]); diff != "" {
+					fmt.Print(diff)
+					stale++
+				}
+			}
+			if stale > 0 {
+				return fmt.Errorf("%d of %d generated files are out of date, run datagen regen", stale, len(filenames))
+			}
+			return nil
+		},
+	}
+}
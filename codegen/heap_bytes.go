@@ -1,13 +1,10 @@
-package codegen
-
-// GENERATED CODE, DO NOT EDIT
-// This code was generated by a tool.
-//
-// 	github.com/aybabtme/datagen
+// Code generated by datagen master. DO NOT EDIT.
 //
-// The command that generated this was:
+// The command that generated this file was:
 //
-//	/var/folders/ng/k4qlpfms3bd8m6g7rqn5rx1r0000gn/T/go-build587471059/command-line-arguments/_obj/exe/heap heap -key []byte
+//	datagen gen -f datagen.yaml
+
+package codegen
 
 // Most of the implementation is adapted from Algorithms 4ed by Sedgewick
 // and Wayne.
@@ -95,15 +92,19 @@ func (h *BytesHeap) Remove(k []byte) bool {
 		return false
 	}
 
-	i := 0
-	for _, j := range h.pq[1:] {
-		i++
-		if h.compare(j, k) != 0 {
+	for i := 2; i <= h.n; i++ {
+		if h.compare(h.pq[i], k) != 0 {
 			continue
 		}
-		h.swap(i, 1)
-		h.sink(i, h.n)
-		_ = h.Pop()
+		// the last element takes its place, and is moved up or down to
+		// where it belongs
+		h.swap(i, h.n)
+		h.pq = h.pq[:h.n]
+		h.n--
+		if i <= h.n {
+			h.sink(i, h.n)
+			h.swim(i)
+		}
 		return true
 	}
 	// not in the heap
@@ -134,4 +135,3 @@ func (h *BytesHeap) sink(k, n int) {
 		k = j
 	}
 }
-
@@ -1,23 +1,21 @@
-package codegen
-
-// GENERATED CODE, DO NOT EDIT
-// This code was generated by a tool.
+// Code generated by datagen master. DO NOT EDIT.
 //
-// 	github.com/aybabtme/datagen
+// The command that generated this file was:
 //
-// The command that generated this was:
-//
-//	/var/folders/ng/k4qlpfms3bd8m6g7rqn5rx1r0000gn/T/go-build018793619/command-line-arguments/_obj/exe/heap sset -key string
+//	datagen gen -f datagen.yaml
 
+package codegen
+
+import "math/bits"
 
 func (r SortedStringSet) compare(a, b string) int {
-    if a < b {
-        return -1
-    }
-    if a > b {
-        return 1
-    }
-    return 0
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
 }
 
 // SortedStringSet is a sorted set built on a left leaning red black balanced
@@ -127,7 +125,7 @@ func (r SortedStringSet) max(x *nodeString) *nodeString {
 }
 
 // Floor returns the largest key in the sorted set that is smaller than
-// `k`.
+// or equal to `k`.
 func (r SortedStringSet) Floor(key string) (k string, ok bool) {
 	x := r.floor(r.root, key)
 	if x == nil {
@@ -155,7 +153,7 @@ func (r SortedStringSet) floor(h *nodeString, k string) *nodeString {
 }
 
 // Ceiling returns the smallest key in the sorted set that is larger than
-// `k`.
+// or equal to `k`.
 func (r SortedStringSet) Ceiling(key string) (k string, ok bool) {
 	x := r.ceiling(r.root, key)
 	if x == nil {
@@ -182,6 +180,73 @@ func (r SortedStringSet) ceiling(h *nodeString, k string) *nodeString {
 	return h
 }
 
+// Lower returns the largest key in the sorted set that is strictly
+// smaller than `k`.
+func (r SortedStringSet) Lower(key string) (k string, ok bool) {
+	x := r.lower(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, true
+}
+
+func (r SortedStringSet) lower(h *nodeString, k string) *nodeString {
+	if h == nil {
+		return nil
+	}
+	if r.compare(k, h.key) <= 0 {
+		return r.lower(h.left, k)
+	}
+	t := r.lower(h.right, k)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// Higher returns the smallest key in the sorted set that is strictly
+// larger than `k`.
+func (r SortedStringSet) Higher(key string) (k string, ok bool) {
+	x := r.higher(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, true
+}
+
+func (r SortedStringSet) higher(h *nodeString, k string) *nodeString {
+	if h == nil {
+		return nil
+	}
+	if r.compare(k, h.key) >= 0 {
+		return r.higher(h.right, k)
+	}
+	t := r.higher(h.left, k)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// FloorRank returns the rank of the floor of `k`, if there's one. Its
+// neighbours in the sorted set are at the next and previous ranks, which
+// Select finds.
+func (r SortedStringSet) FloorRank(key string) (rank int, ok bool) {
+	x := r.floor(r.root, key)
+	if x == nil {
+		return
+	}
+	return r.keyrank(x.key, r.root), true
+}
+
+// CeilingRank returns the rank of the ceiling of `k`, if there's one. Its
+// neighbours in the sorted set are at the next and previous ranks, which
+// Select finds.
+func (r SortedStringSet) CeilingRank(key string) (rank int, ok bool) {
+	rank = r.keyrank(key, r.root)
+	return rank, rank < r.Size()
+}
+
 // Select key of rank k, meaning the k-th biggest string in the sorted set.
 func (r SortedStringSet) Select(key int) (k string, ok bool) {
 	x := r.nodeselect(r.root, key)
@@ -224,6 +289,19 @@ func (r SortedStringSet) keyrank(k string, h *nodeString) int {
 	}
 }
 
+// CountRange returns the number of keys between lo and hi in the sorted
+// set, which it counts from their ranks in O(log n).
+func (r SortedStringSet) CountRange(lo, hi string) int {
+	if r.compare(lo, hi) > 0 {
+		return 0
+	}
+	count := r.keyrank(hi, r.root) - r.keyrank(lo, r.root)
+	if r.Contains(hi) {
+		count++
+	}
+	return count
+}
+
 // Keys visit each keys in the sorted set, in order.
 // It stops when visit returns false.
 func (r SortedStringSet) Keys(visit func(string) bool) {
@@ -266,6 +344,103 @@ func (r SortedStringSet) keys(h *nodeString, visit func(string) bool, lo, hi str
 	return true
 }
 
+// KeysBetween visit each keys between lo and hi in the sorted set, in
+// order, like RangedKeys, but leaves lo out unless loInclusive and hi out
+// unless hiInclusive: `KeysBetween(lo, true, hi, false, visit)` visits the
+// keys of [lo, hi). It stops when visit returns false.
+func (r SortedStringSet) KeysBetween(lo string, loInclusive bool, hi string, hiInclusive bool, visit func(string) bool) {
+	r.bounded(r.root, visit, r.above(lo, loInclusive), r.below(hi, hiInclusive), false)
+}
+
+// KeysAbove visit each keys larger than lo in the sorted set, or equal to
+// it if inclusive, in order. It stops when visit returns false.
+func (r SortedStringSet) KeysAbove(lo string, inclusive bool, visit func(string) bool) {
+	r.bounded(r.root, visit, r.above(lo, inclusive), r.unbounded, false)
+}
+
+// KeysBelow visit each keys smaller than hi in the sorted set, or equal to
+// it if inclusive, in order. It stops when visit returns false.
+func (r SortedStringSet) KeysBelow(hi string, inclusive bool, visit func(string) bool) {
+	r.bounded(r.root, visit, r.unbounded, r.below(hi, inclusive), false)
+}
+
+// ReverseKeys visit each keys in the sorted set, in reverse order.
+// It stops when visit returns false.
+func (r SortedStringSet) ReverseKeys(visit func(string) bool) {
+	r.bounded(r.root, visit, r.unbounded, r.unbounded, true)
+}
+
+// ReverseRangedKeys visit each keys between lo and hi in the sorted set,
+// in reverse order, from hi down to lo. It stops when visit returns false.
+func (r SortedStringSet) ReverseRangedKeys(lo, hi string, visit func(string) bool) {
+	r.bounded(r.root, visit, r.above(lo, true), r.below(hi, true), true)
+}
+
+// ReverseKeysBetween is KeysBetween, visiting the keys in reverse order.
+func (r SortedStringSet) ReverseKeysBetween(lo string, loInclusive bool, hi string, hiInclusive bool, visit func(string) bool) {
+	r.bounded(r.root, visit, r.above(lo, loInclusive), r.below(hi, hiInclusive), true)
+}
+
+// ReverseKeysAbove is KeysAbove, visiting the keys in reverse order.
+func (r SortedStringSet) ReverseKeysAbove(lo string, inclusive bool, visit func(string) bool) {
+	r.bounded(r.root, visit, r.above(lo, inclusive), r.unbounded, true)
+}
+
+// ReverseKeysBelow is KeysBelow, visiting the keys in reverse order, like
+// the latest keys before hi.
+func (r SortedStringSet) ReverseKeysBelow(hi string, inclusive bool, visit func(string) bool) {
+	r.bounded(r.root, visit, r.unbounded, r.below(hi, inclusive), true)
+}
+
+// bounded visits the keys of h within bounds, in order or in reverse.
+// aboveLo and belowHi tell if a key is within the lower and the upper bound,
+// which are only crossed once going through the keys in order.
+func (r SortedStringSet) bounded(h *nodeString, visit func(string) bool, aboveLo, belowHi func(string) bool, reverse bool) bool {
+	if h == nil {
+		return true
+	}
+	inLo, inHi := aboveLo(h.key), belowHi(h.key)
+	// the keys of the left subtree are all out of bounds when h is below
+	// lo, and those of the right subtree when h is above hi
+	first, last := h.left, h.right
+	inFirst, inLast := inLo, inHi
+	if reverse {
+		first, last = last, first
+		inFirst, inLast = inLast, inFirst
+	}
+	if inFirst && !r.bounded(first, visit, aboveLo, belowHi, reverse) {
+		return false
+	}
+	if inLo && inHi && !visit(h.key) {
+		return false
+	}
+	if inLast && !r.bounded(last, visit, aboveLo, belowHi, reverse) {
+		return false
+	}
+	return true
+}
+
+// above returns the lower bound lo, which keys are within when they're
+// larger than lo, or equal to it if inclusive.
+func (r SortedStringSet) above(lo string, inclusive bool) func(string) bool {
+	return func(k string) bool {
+		cmp := r.compare(k, lo)
+		return cmp > 0 || inclusive && cmp == 0
+	}
+}
+
+// below returns the upper bound hi, which keys are within when they're
+// smaller than hi, or equal to it if inclusive.
+func (r SortedStringSet) below(hi string, inclusive bool) func(string) bool {
+	return func(k string) bool {
+		cmp := r.compare(k, hi)
+		return cmp < 0 || inclusive && cmp == 0
+	}
+}
+
+// unbounded is the bound of an open end, which all keys are within.
+func (r SortedStringSet) unbounded(string) bool { return true }
+
 // DeleteMin removes the smallest key from the sorted set.
 func (r *SortedStringSet) DeleteMin() (oldk string, ok bool) {
 	r.root, oldk, ok = r.deleteMin(r.root)
@@ -374,6 +549,78 @@ func (r *SortedStringSet) delete(h *nodeString, k string) (_ *nodeString, ok boo
 	return h, ok
 }
 
+// DeleteRange removes the keys between lo and hi from the sorted set, and
+// returns how many there were. A few keys are deleted one by one, in
+// O(log n) each, and otherwise the sorted set is rebuilt from the keys
+// left in O(n).
+func (r *SortedStringSet) DeleteRange(lo, hi string) int {
+	count := r.CountRange(lo, hi)
+	size := r.Size()
+	if count*bits.Len(uint(size)) < size {
+		for i := 0; i < count; i++ {
+			r.Delete(r.ceiling(r.root, lo).key)
+		}
+		return count
+	}
+	kept := r.outside(r.root, lo, hi, make([]*nodeString, 0, size-count))
+	// the smallest height of a 2-3 tree holding the keys left, which holds
+	// up to 3^(height+1)-1 keys
+	height := 0
+	for max := 2; max < len(kept); max = 3*max + 2 {
+		height++
+	}
+	r.root = r.build(kept, height)
+	return count
+}
+
+// outside appends the nodes of h whose keys aren't between lo and hi to
+// nodes, in order.
+func (r *SortedStringSet) outside(h *nodeString, lo, hi string, nodes []*nodeString) []*nodeString {
+	if h == nil {
+		return nodes
+	}
+	nodes = r.outside(h.left, lo, hi, nodes)
+	if r.compare(h.key, lo) < 0 || r.compare(h.key, hi) > 0 {
+		nodes = append(nodes, h)
+	}
+	return r.outside(h.right, lo, hi, nodes)
+}
+
+// build links nodes, in order, into a 2-3 tree of the given height, whose
+// 3-nodes are a black node leaning on a red left child. A 2-3 tree of that
+// height holds between 2^(height+1)-1 and 3^(height+1)-1 keys, which nodes
+// must be within.
+func (r *SortedStringSet) build(nodes []*nodeString, height int) *nodeString {
+	if len(nodes) == 0 {
+		return nil
+	}
+	// the subtrees hold up to 3^height-1 keys
+	max := 1
+	for i := 0; i < height; i++ {
+		max *= 3
+	}
+	max--
+
+	if len(nodes) <= 2*max+1 {
+		mid := len(nodes) / 2
+		h := nodes[mid]
+		h.left = r.build(nodes[:mid], height-1)
+		h.right = r.build(nodes[mid+1:], height-1)
+		h.n, h.colorRed = len(nodes), false
+		return h
+	}
+	a := (len(nodes) - 2) / 3
+	b := (len(nodes) - 2 - a) / 2
+	left, h := nodes[a], nodes[a+b+1]
+	left.left = r.build(nodes[:a], height-1)
+	left.right = r.build(nodes[a+1:a+b+1], height-1)
+	left.n, left.colorRed = a+b+1, true
+	h.left = left
+	h.right = r.build(nodes[a+b+2:], height-1)
+	h.n, h.colorRed = len(nodes), false
+	return h
+}
+
 // deletions
 
 func (r *SortedStringSet) moveRedLeft(h *nodeString) *nodeString {
@@ -454,4 +701,3 @@ func (x *nodeString) size() int {
 	}
 	return x.n
 }
-
@@ -0,0 +1,8 @@
+// Package heap is a generic heap, generated from the same template as the
+// heaps created by `datagen heap`.
+//
+// Go 1.22 or later is needed to use it.
+package heap
+
+//go:generate datagen generic-library -kind heap -o heap.go
+//go:generate datagen generic-library -kind heap -iterators -o heap_iter.go
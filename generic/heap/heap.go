@@ -0,0 +1,143 @@
+// Code generated by datagen master. DO NOT EDIT.
+//
+// The command that generated this file was:
+//
+//	datagen generic-library -kind heap -o heap.go
+
+package heap
+
+// Most of the implementation is adapted from Algorithms 4ed by Sedgewick
+// and Wayne.
+
+// Comments are adapted from `container/heap`.
+// 	 Copyright 2009 The Go Authors. All rights reserved.
+// 	 Use of this source code is governed by a BSD-style
+// 	 license that can be found in the LICENSE file.
+
+import "cmp"
+
+func (h Heap[K]) compare(a, b K) int { return h.compareFunc(a, b) }
+
+// Heap is a container of K, where the elements can be efficiently
+// retrieved in their decreasing order (according to their comparison
+// rules).
+type Heap[K any] struct {
+	n           int
+	pq          []K
+	compareFunc func(a, b K) int
+}
+
+// New creates a heap of ordered keys, optionaly with keys already
+// populating it. The complexity is O(n) where n = len(keys).
+func New[K cmp.Ordered](keys ...K) *Heap[K] {
+	return NewFunc(cmp.Compare[K], keys...)
+}
+
+// NewFunc creates a heap where keys are ordered by compare, optionaly with
+// keys already populating it. The complexity is O(n) where n = len(keys).
+func NewFunc[K any](compare func(a, b K) int, keys ...K) *Heap[K] {
+	h := &Heap[K]{
+		n:           len(keys),
+		pq:          append(make([]K, 1), keys...),
+		compareFunc: compare,
+	}
+	h.Fix()
+	return h
+}
+
+// Len is the number of elements stored in the heap.
+func (h *Heap[K]) Len() int { return h.n }
+
+// Peek at the largest element (according to their comparison rules), without
+// removing it from the heap.
+func (h *Heap[K]) Peek() K { return h.pq[1] }
+
+// Fix re-establishes the heap ordering. This is useful if elements
+// of the heap have had their comparison value changed. It is equivalent to,
+// but less expenasive than, Pop'ing all the elements and Push'ing them
+// again.
+// The complexity is O(n).
+func (h *Heap[K]) Fix() {
+	for i := (h.n) / 2; i > 0; i-- {
+		h.sink(i, h.n)
+	}
+}
+
+// Push pushes the element k onto the heap. The complexity is
+// O(log(n)) where n == h.Len().
+func (h *Heap[K]) Push(k K) {
+	h.n++
+	h.pq = append(h.pq, k)
+	h.swim(h.n)
+}
+
+// Pop removes the largest element (according to their comparison rules) from
+// the heap and returns it. The complexity is O(log(n)) where n == h.Len().
+func (h *Heap[K]) Pop() K {
+	val := h.pq[1]
+	h.swap(1, h.n)
+	h.pq = h.pq[:h.n]
+	h.n--
+	h.sink(1, h.n)
+
+	return val
+}
+
+// Remove removes k from the heap, if it exists. Equality is defined by
+// Compare == 0.
+// The complexity is O(n+log(n)) where n == h.Len().
+func (h *Heap[K]) Remove(k K) bool {
+
+	cmp := h.compare(h.pq[1], k)
+	if cmp == 0 {
+		_ = h.Pop()
+		return true
+	}
+	if cmp < 0 {
+		// larger than largest, don't try to find it
+		return false
+	}
+
+	for i := 2; i <= h.n; i++ {
+		if h.compare(h.pq[i], k) != 0 {
+			continue
+		}
+		// the last element takes its place, and is moved up or down to
+		// where it belongs
+		h.swap(i, h.n)
+		h.pq = h.pq[:h.n]
+		h.n--
+		if i <= h.n {
+			h.sink(i, h.n)
+			h.swim(i)
+		}
+		return true
+	}
+	// not in the heap
+	return false
+}
+
+func (h *Heap[K]) swap(i, j int)      { h.pq[i], h.pq[j] = h.pq[j], h.pq[i] }
+func (h *Heap[K]) less(i, j int) bool { return h.compare(h.pq[i], h.pq[j]) < 0 }
+
+func (h *Heap[K]) swim(k int) {
+	for k > 1 && h.less(k/2, k) {
+		h.swap(k/2, k)
+		k = k / 2
+	}
+}
+
+func (h *Heap[K]) sink(k, n int) {
+
+	for k*2 <= n {
+		j := 2 * k
+		if j < n && h.less(j, j+1) {
+			j++
+		}
+		if !h.less(k, j) {
+			break
+		}
+		h.swap(k, j)
+		k = j
+	}
+}
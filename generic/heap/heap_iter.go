@@ -0,0 +1,24 @@
+// Code generated by datagen master. DO NOT EDIT.
+//
+// The command that generated this file was:
+//
+//	datagen generic-library -kind heap -iterators -o heap_iter.go
+
+//go:build go1.23
+
+package heap
+
+import "iter"
+
+// Drain returns the elements of the heap in decreasing order, removing
+// them as they're iterated over. The elements left once the iteration
+// stops stay in the heap.
+func (h *Heap[K]) Drain() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for h.Len() > 0 {
+			if !yield(h.Pop()) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,22 @@
+//go:build go1.23
+
+package heap
+
+import "testing"
+
+func TestHeapDrain(t *testing.T) {
+	h := New(3, 1, 4, 1, 5)
+	var got []int
+	for k := range h.Drain() {
+		got = append(got, k)
+		if k == 3 {
+			break
+		}
+	}
+	if len(got) != 3 || got[0] != 5 || got[1] != 4 || got[2] != 3 {
+		t.Errorf("want to drain 5, 4, 3, got %v", got)
+	}
+	if h.Len() != 2 {
+		t.Errorf("want Len=%d, was %d", 2, h.Len())
+	}
+}
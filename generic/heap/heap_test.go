@@ -0,0 +1,60 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestHeapPopsInDecreasingOrder(t *testing.T) {
+	keys := rand.Perm(1000)
+	h := New(keys[:500]...)
+	for _, k := range keys[500:] {
+		h.Push(k)
+	}
+	if h.Len() != len(keys) {
+		t.Fatalf("want Len=%d, was %d", len(keys), h.Len())
+	}
+	for want := len(keys) - 1; want >= 0; want-- {
+		if got := h.Peek(); got != want {
+			t.Fatalf("want Peek=%d, was %d", want, got)
+		}
+		if got := h.Pop(); got != want {
+			t.Fatalf("want Pop=%d, was %d", want, got)
+		}
+	}
+	if h.Len() != 0 {
+		t.Errorf("want Len=%d, was %d", 0, h.Len())
+	}
+}
+
+func TestHeapRemoveKeepsTheOrder(t *testing.T) {
+	h := New[int]()
+	for _, k := range []int{9, 8, 7, 1, 2, 3, 4} {
+		h.Push(k)
+	}
+	if !h.Remove(1) {
+		t.Fatal("want 1 removed")
+	}
+	for _, want := range []int{9, 8, 7, 4, 3, 2} {
+		if got := h.Pop(); got != want {
+			t.Fatalf("want Pop=%d, was %d", want, got)
+		}
+	}
+}
+
+func TestHeapFunc(t *testing.T) {
+	words := []string{"banana", "Apple", "cherry", "apricot", "Blueberry"}
+	h := NewFunc(func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}, words...)
+
+	want := append([]string(nil), words...)
+	sort.Slice(want, func(i, j int) bool { return strings.ToLower(want[i]) > strings.ToLower(want[j]) })
+	for _, w := range want {
+		if got := h.Pop(); got != w {
+			t.Errorf("want Pop=%q, was %q", w, got)
+		}
+	}
+}
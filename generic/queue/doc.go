@@ -0,0 +1,8 @@
+// Package queue is a generic queue, generated from the same template as
+// the queues created by `datagen queue`.
+//
+// Go 1.22 or later is needed to use it.
+package queue
+
+//go:generate datagen generic-library -kind queue -o queue.go
+//go:generate datagen generic-library -kind queue -iterators -o queue_iter.go
@@ -0,0 +1,23 @@
+// Code generated by datagen master. DO NOT EDIT.
+//
+// The command that generated this file was:
+//
+//	datagen generic-library -kind queue -iterators -o queue_iter.go
+
+//go:build go1.23
+
+package queue
+
+import "iter"
+
+// All returns the elements of the queue, from its front to its end,
+// without removing them.
+func (q *Queue[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for i := 0; i < q.Len(); i++ {
+			if !yield(q.Get(i)) {
+				return
+			}
+		}
+	}
+}
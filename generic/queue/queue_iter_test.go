@@ -0,0 +1,22 @@
+//go:build go1.23
+
+package queue
+
+import "testing"
+
+func TestQueueAll(t *testing.T) {
+	q := New[int](0)
+	for i := 0; i < 10; i++ {
+		q.Push(i)
+	}
+	want := 0
+	for k := range q.All() {
+		if k != want {
+			t.Fatalf("want %d, got %d", want, k)
+		}
+		want++
+	}
+	if want != 10 || q.Len() != 10 {
+		t.Errorf("want to iterate over the 10 elements without removing them, got %d and Len=%d", want, q.Len())
+	}
+}
@@ -0,0 +1,32 @@
+package queue
+
+import "testing"
+
+func TestQueue(t *testing.T) {
+	q := New[string](0)
+	if q.Len() != 0 {
+		t.Error("empty queue length not 0")
+	}
+
+	for i := 0; i < 1000; i++ {
+		q.Push(string(rune('a' + i%26)))
+		if q.Len() != i+1 {
+			t.Error("adding: queue with", i, "elements has length", q.Len())
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		want := string(rune('a' + i%26))
+		if got := q.Get(0); got != want {
+			t.Errorf("index 0 is %q, want %q", got, want)
+		}
+		if got := q.Peek(); got != want {
+			t.Errorf("peek is %q, want %q", got, want)
+		}
+		if got := q.Pop(); got != want {
+			t.Errorf("pop is %q, want %q", got, want)
+		}
+	}
+	if q.Len() != 0 {
+		t.Error("drained queue length not 0")
+	}
+}
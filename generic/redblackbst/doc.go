@@ -0,0 +1,11 @@
+// Package redblackbst holds a generic sorted map and sorted set, generated
+// from the same templates as the ones created by `datagen smap` and
+// `datagen sset`.
+//
+// Go 1.22 or later is needed to use it.
+package redblackbst
+
+//go:generate datagen generic-library -kind smap -o map.go
+//go:generate datagen generic-library -kind sset -o set.go
+//go:generate datagen generic-library -kind smap -iterators -o map_iter.go
+//go:generate datagen generic-library -kind sset -iterators -o set_iter.go
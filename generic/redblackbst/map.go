@@ -0,0 +1,873 @@
+// Code generated by datagen master. DO NOT EDIT.
+//
+// The command that generated this file was:
+//
+//	datagen generic-library -kind smap -o map.go
+
+package redblackbst
+
+import (
+	"cmp"
+	"math/bits"
+)
+
+func (r Map[K, V]) compare(a, b K) int { return r.compareFunc(a, b) }
+
+// Map is a sorted map built on a left leaning red black balanced
+// search sorted map. It stores V values, keyed by K.
+type Map[K, V any] struct {
+	root        *mapnode[K, V]
+	compareFunc func(a, b K) int
+}
+
+// NewMap creates a sorted map of ordered keys.
+func NewMap[K cmp.Ordered, V any]() *Map[K, V] {
+	return NewMapFunc[K, V](cmp.Compare[K])
+}
+
+// NewMapFunc creates a sorted map where keys are ordered by compare.
+func NewMapFunc[K, V any](compare func(a, b K) int) *Map[K, V] {
+	return &Map[K, V]{compareFunc: compare}
+}
+
+// IsEmpty tells if the sorted map contains no key/value.
+func (r Map[K, V]) IsEmpty() bool {
+	return r.root == nil
+}
+
+// Size of the sorted map.
+func (r Map[K, V]) Size() int { return r.root.size() }
+
+// Clear all the values in the sorted map.
+func (r *Map[K, V]) Clear() { r.root = nil }
+
+// Put a value in the sorted map at key `k`. The old value at `k` is returned
+// if the key was already present.
+func (r *Map[K, V]) Put(k K, v V) (old V, overwrite bool) {
+	r.root, old, overwrite = r.put(r.root, k, func() V { return v }, func(_ V) V { return v })
+	return
+}
+
+// Mutate is like a Put when `k` isn't defined, but allows you to create or mutate the value found at the location of `k`.
+func (r *Map[K, V]) Mutate(k K, creator func() V, mutator func(old V) V) {
+	r.root, _, _ = r.put(r.root, k, creator, mutator)
+}
+
+func (r *Map[K, V]) put(h *mapnode[K, V], k K, create func() V, mutate func(old V) V) (_ *mapnode[K, V], old V, overwrite bool) {
+	if h == nil {
+		n := &mapnode[K, V]{key: k, val: create(), n: 1, colorRed: true}
+		return n, old, overwrite
+	}
+
+	cmp := r.compare(k, h.key)
+	if cmp < 0 {
+		h.left, old, overwrite = r.put(h.left, k, create, mutate)
+	} else if cmp > 0 {
+		h.right, old, overwrite = r.put(h.right, k, create, mutate)
+	} else {
+		overwrite = true
+		old = h.val
+		h.val = mutate(old)
+	}
+
+	if h.right.isRed() && !h.left.isRed() {
+		h = r.rotateLeft(h)
+	}
+	if h.left.isRed() && h.left.left.isRed() {
+		h = r.rotateRight(h)
+	}
+	if h.left.isRed() && h.right.isRed() {
+		r.flipColors(h)
+	}
+	h.n = h.left.size() + h.right.size() + 1
+	return h, old, overwrite
+}
+
+// Get a value from the sorted map at key `k`. Returns false
+// if the key doesn't exist.
+func (r Map[K, V]) Get(k K) (V, bool) {
+	return r.loopGet(r.root, k)
+}
+
+func (r Map[K, V]) loopGet(h *mapnode[K, V], k K) (v V, ok bool) {
+	for h != nil {
+		cmp := r.compare(k, h.key)
+		if cmp == 0 {
+			return h.val, true
+		} else if cmp < 0 {
+			h = h.left
+		} else if cmp > 0 {
+			h = h.right
+		}
+	}
+	return
+}
+
+// Has tells if a value exists at key `k`. This is short hand for `Get.
+func (r Map[K, V]) Has(k K) bool {
+	_, ok := r.loopGet(r.root, k)
+	return ok
+}
+
+// Min returns the smallest key/value in the sorted map, if it exists.
+func (r Map[K, V]) Min() (k K, v V, ok bool) {
+	if r.root == nil {
+		return
+	}
+	h := r.min(r.root)
+	return h.key, h.val, true
+}
+
+func (r Map[K, V]) min(x *mapnode[K, V]) *mapnode[K, V] {
+	if x.left == nil {
+		return x
+	}
+	return r.min(x.left)
+}
+
+// Max returns the largest key/value in the sorted map, if it exists.
+func (r Map[K, V]) Max() (k K, v V, ok bool) {
+	if r.root == nil {
+		return
+	}
+	h := r.max(r.root)
+	return h.key, h.val, true
+}
+
+func (r Map[K, V]) max(x *mapnode[K, V]) *mapnode[K, V] {
+	if x.right == nil {
+		return x
+	}
+	return r.max(x.right)
+}
+
+// Floor returns the largest key/value in the sorted map that is smaller than
+// or equal to `k`.
+func (r Map[K, V]) Floor(key K) (k K, v V, ok bool) {
+	x := r.floor(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, x.val, true
+}
+
+func (r Map[K, V]) floor(h *mapnode[K, V], k K) *mapnode[K, V] {
+	if h == nil {
+		return nil
+	}
+	cmp := r.compare(k, h.key)
+	if cmp == 0 {
+		return h
+	}
+	if cmp < 0 {
+		return r.floor(h.left, k)
+	}
+	t := r.floor(h.right, k)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// Ceiling returns the smallest key/value in the sorted map that is larger than
+// or equal to `k`.
+func (r Map[K, V]) Ceiling(key K) (k K, v V, ok bool) {
+	x := r.ceiling(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, x.val, true
+}
+
+func (r Map[K, V]) ceiling(h *mapnode[K, V], k K) *mapnode[K, V] {
+	if h == nil {
+		return nil
+	}
+	cmp := r.compare(k, h.key)
+	if cmp == 0 {
+		return h
+	}
+	if cmp > 0 {
+		return r.ceiling(h.right, k)
+	}
+	t := r.ceiling(h.left, k)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// Lower returns the largest key/value in the sorted map that is strictly
+// smaller than `k`.
+func (r Map[K, V]) Lower(key K) (k K, v V, ok bool) {
+	x := r.lower(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, x.val, true
+}
+
+func (r Map[K, V]) lower(h *mapnode[K, V], k K) *mapnode[K, V] {
+	if h == nil {
+		return nil
+	}
+	if r.compare(k, h.key) <= 0 {
+		return r.lower(h.left, k)
+	}
+	t := r.lower(h.right, k)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// Higher returns the smallest key/value in the sorted map that is strictly
+// larger than `k`.
+func (r Map[K, V]) Higher(key K) (k K, v V, ok bool) {
+	x := r.higher(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, x.val, true
+}
+
+func (r Map[K, V]) higher(h *mapnode[K, V], k K) *mapnode[K, V] {
+	if h == nil {
+		return nil
+	}
+	if r.compare(k, h.key) >= 0 {
+		return r.higher(h.right, k)
+	}
+	t := r.higher(h.left, k)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// FloorRank returns the rank of the floor of `k`, if there's one. Its
+// neighbours in the sorted map are at the next and previous ranks, which
+// Select finds.
+func (r Map[K, V]) FloorRank(key K) (rank int, ok bool) {
+	x := r.floor(r.root, key)
+	if x == nil {
+		return
+	}
+	return r.keyrank(x.key, r.root), true
+}
+
+// CeilingRank returns the rank of the ceiling of `k`, if there's one. Its
+// neighbours in the sorted map are at the next and previous ranks, which
+// Select finds.
+func (r Map[K, V]) CeilingRank(key K) (rank int, ok bool) {
+	rank = r.keyrank(key, r.root)
+	return rank, rank < r.Size()
+}
+
+// Select key of rank k, meaning the k-th biggest K in the sorted map.
+func (r Map[K, V]) Select(key int) (k K, v V, ok bool) {
+	x := r.nodeselect(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, x.val, true
+}
+
+func (r Map[K, V]) nodeselect(x *mapnode[K, V], k int) *mapnode[K, V] {
+	if x == nil {
+		return nil
+	}
+	t := x.left.size()
+	if t > k {
+		return r.nodeselect(x.left, k)
+	} else if t < k {
+		return r.nodeselect(x.right, k-t-1)
+	} else {
+		return x
+	}
+}
+
+// Rank is the number of keys less than `k`.
+func (r Map[K, V]) Rank(k K) int {
+	return r.keyrank(k, r.root)
+}
+
+func (r Map[K, V]) keyrank(k K, h *mapnode[K, V]) int {
+	if h == nil {
+		return 0
+	}
+	cmp := r.compare(k, h.key)
+	if cmp < 0 {
+		return r.keyrank(k, h.left)
+	} else if cmp > 0 {
+		return 1 + h.left.size() + r.keyrank(k, h.right)
+	} else {
+		return h.left.size()
+	}
+}
+
+// CountRange returns the number of keys between lo and hi in the sorted
+// map, which it counts from their ranks in O(log n).
+func (r Map[K, V]) CountRange(lo, hi K) int {
+	if r.compare(lo, hi) > 0 {
+		return 0
+	}
+	count := r.keyrank(hi, r.root) - r.keyrank(lo, r.root)
+	if r.Has(hi) {
+		count++
+	}
+	return count
+}
+
+// Keys visit each keys in the sorted map, in order.
+// It stops when visit returns false.
+func (r Map[K, V]) Keys(visit func(K, V) bool) {
+	min, _, ok := r.Min()
+	if !ok {
+		return
+	}
+	// if the min exists, then the max must exist
+	max, _, _ := r.Max()
+	r.RangedKeys(min, max, visit)
+}
+
+// RangedKeys visit each keys between lo and hi in the sorted map, in order.
+// It stops when visit returns false.
+func (r Map[K, V]) RangedKeys(lo, hi K, visit func(K, V) bool) {
+	r.keys(r.root, visit, lo, hi)
+}
+
+func (r Map[K, V]) keys(h *mapnode[K, V], visit func(K, V) bool, lo, hi K) bool {
+	if h == nil {
+		return true
+	}
+	cmplo := r.compare(lo, h.key)
+	cmphi := r.compare(hi, h.key)
+	if cmplo < 0 {
+		if !r.keys(h.left, visit, lo, hi) {
+			return false
+		}
+	}
+	if cmplo <= 0 && cmphi >= 0 {
+		if !visit(h.key, h.val) {
+			return false
+		}
+	}
+	if cmphi > 0 {
+		if !r.keys(h.right, visit, lo, hi) {
+			return false
+		}
+	}
+	return true
+}
+
+// KeysBetween visit each keys between lo and hi in the sorted map, in
+// order, like RangedKeys, but leaves lo out unless loInclusive and hi out
+// unless hiInclusive: `KeysBetween(lo, true, hi, false, visit)` visits the
+// keys of [lo, hi). It stops when visit returns false.
+func (r Map[K, V]) KeysBetween(lo K, loInclusive bool, hi K, hiInclusive bool, visit func(K, V) bool) {
+	r.bounded(r.root, visit, r.above(lo, loInclusive), r.below(hi, hiInclusive), false)
+}
+
+// KeysAbove visit each keys larger than lo in the sorted map, or equal to
+// it if inclusive, in order. It stops when visit returns false.
+func (r Map[K, V]) KeysAbove(lo K, inclusive bool, visit func(K, V) bool) {
+	r.bounded(r.root, visit, r.above(lo, inclusive), r.unbounded, false)
+}
+
+// KeysBelow visit each keys smaller than hi in the sorted map, or equal to
+// it if inclusive, in order. It stops when visit returns false.
+func (r Map[K, V]) KeysBelow(hi K, inclusive bool, visit func(K, V) bool) {
+	r.bounded(r.root, visit, r.unbounded, r.below(hi, inclusive), false)
+}
+
+// ReverseKeys visit each keys in the sorted map, in reverse order.
+// It stops when visit returns false.
+func (r Map[K, V]) ReverseKeys(visit func(K, V) bool) {
+	r.bounded(r.root, visit, r.unbounded, r.unbounded, true)
+}
+
+// ReverseRangedKeys visit each keys between lo and hi in the sorted map,
+// in reverse order, from hi down to lo. It stops when visit returns false.
+func (r Map[K, V]) ReverseRangedKeys(lo, hi K, visit func(K, V) bool) {
+	r.bounded(r.root, visit, r.above(lo, true), r.below(hi, true), true)
+}
+
+// ReverseKeysBetween is KeysBetween, visiting the keys in reverse order.
+func (r Map[K, V]) ReverseKeysBetween(lo K, loInclusive bool, hi K, hiInclusive bool, visit func(K, V) bool) {
+	r.bounded(r.root, visit, r.above(lo, loInclusive), r.below(hi, hiInclusive), true)
+}
+
+// ReverseKeysAbove is KeysAbove, visiting the keys in reverse order.
+func (r Map[K, V]) ReverseKeysAbove(lo K, inclusive bool, visit func(K, V) bool) {
+	r.bounded(r.root, visit, r.above(lo, inclusive), r.unbounded, true)
+}
+
+// ReverseKeysBelow is KeysBelow, visiting the keys in reverse order, like
+// the latest keys before hi.
+func (r Map[K, V]) ReverseKeysBelow(hi K, inclusive bool, visit func(K, V) bool) {
+	r.bounded(r.root, visit, r.unbounded, r.below(hi, inclusive), true)
+}
+
+// bounded visits the keys of h within bounds, in order or in reverse.
+// aboveLo and belowHi tell if a key is within the lower and the upper bound,
+// which are only crossed once going through the keys in order.
+func (r Map[K, V]) bounded(h *mapnode[K, V], visit func(K, V) bool, aboveLo, belowHi func(K) bool, reverse bool) bool {
+	if h == nil {
+		return true
+	}
+	inLo, inHi := aboveLo(h.key), belowHi(h.key)
+	// the keys of the left subtree are all out of bounds when h is below
+	// lo, and those of the right subtree when h is above hi
+	first, last := h.left, h.right
+	inFirst, inLast := inLo, inHi
+	if reverse {
+		first, last = last, first
+		inFirst, inLast = inLast, inFirst
+	}
+	if inFirst && !r.bounded(first, visit, aboveLo, belowHi, reverse) {
+		return false
+	}
+	if inLo && inHi && !visit(h.key, h.val) {
+		return false
+	}
+	if inLast && !r.bounded(last, visit, aboveLo, belowHi, reverse) {
+		return false
+	}
+	return true
+}
+
+// above returns the lower bound lo, which keys are within when they're
+// larger than lo, or equal to it if inclusive.
+func (r Map[K, V]) above(lo K, inclusive bool) func(K) bool {
+	return func(k K) bool {
+		cmp := r.compare(k, lo)
+		return cmp > 0 || inclusive && cmp == 0
+	}
+}
+
+// below returns the upper bound hi, which keys are within when they're
+// smaller than hi, or equal to it if inclusive.
+func (r Map[K, V]) below(hi K, inclusive bool) func(K) bool {
+	return func(k K) bool {
+		cmp := r.compare(k, hi)
+		return cmp < 0 || inclusive && cmp == 0
+	}
+}
+
+// unbounded is the bound of an open end, which all keys are within.
+func (r Map[K, V]) unbounded(K) bool { return true }
+
+// Iterator returns an iterator over the keys/values of the sorted map, in
+// order. It isn't positioned yet: Next moves it to the smallest key, and
+// Prev to the largest one.
+func (r *Map[K, V]) Iterator() *MapIterator[K, V] {
+	return &MapIterator[K, V]{tree: r}
+}
+
+// MapIterator is a cursor over the keys/values of a sorted map, which
+// moves in either direction. Putting a new key in the sorted map or
+// deleting one invalidates its iterators: seek the last key seen to
+// resume.
+type MapIterator[K, V any] struct {
+	tree *Map[K, V]
+	// path from the root to the node of the current key, empty when the
+	// iterator isn't positioned at a key.
+	path []*mapnode[K, V]
+	// done once the iterator moved past either end of the sorted map. It
+	// stays there until it's positioned again.
+	done bool
+}
+
+// First moves the iterator to the smallest key, and tells if there's one.
+func (it *MapIterator[K, V]) First() bool {
+	it.path, it.done = it.path[:0], false
+	it.pushLeft(it.tree.root)
+	return it.Valid()
+}
+
+// Last moves the iterator to the largest key, and tells if there's one.
+func (it *MapIterator[K, V]) Last() bool {
+	it.path, it.done = it.path[:0], false
+	it.pushRight(it.tree.root)
+	return it.Valid()
+}
+
+// SeekCeiling moves the iterator to the smallest key larger than or equal
+// to `k`, and tells if there's one.
+func (it *MapIterator[K, V]) SeekCeiling(k K) bool {
+	it.path, it.done = it.path[:0], false
+	// the key sought is the last one of the path to `k` that's larger
+	ceiling := 0
+	for h := it.tree.root; h != nil; {
+		it.path = append(it.path, h)
+		cmp := it.tree.compare(k, h.key)
+		if cmp == 0 {
+			return true
+		} else if cmp < 0 {
+			ceiling = len(it.path)
+			h = h.left
+		} else {
+			h = h.right
+		}
+	}
+	it.path, it.done = it.path[:ceiling], ceiling == 0
+	return it.Valid()
+}
+
+// SeekFloor moves the iterator to the largest key smaller than or equal
+// to `k`, and tells if there's one.
+func (it *MapIterator[K, V]) SeekFloor(k K) bool {
+	it.path, it.done = it.path[:0], false
+	// the key sought is the last one of the path to `k` that's smaller
+	floor := 0
+	for h := it.tree.root; h != nil; {
+		it.path = append(it.path, h)
+		cmp := it.tree.compare(k, h.key)
+		if cmp == 0 {
+			return true
+		} else if cmp > 0 {
+			floor = len(it.path)
+			h = h.right
+		} else {
+			h = h.left
+		}
+	}
+	it.path, it.done = it.path[:floor], floor == 0
+	return it.Valid()
+}
+
+// Next moves the iterator to the next key, and tells if there's one.
+func (it *MapIterator[K, V]) Next() bool {
+	if it.done {
+		return false
+	}
+	if !it.Valid() {
+		return it.First()
+	}
+	if h := it.path[len(it.path)-1]; h.right != nil {
+		it.pushLeft(h.right)
+		return true
+	}
+	// the next key is the first ancestor found on the right
+	for len(it.path) > 1 {
+		child := it.path[len(it.path)-1]
+		it.path = it.path[:len(it.path)-1]
+		if it.path[len(it.path)-1].left == child {
+			return true
+		}
+	}
+	it.path, it.done = it.path[:0], true
+	return false
+}
+
+// Prev moves the iterator to the previous key, and tells if there's one.
+func (it *MapIterator[K, V]) Prev() bool {
+	if it.done {
+		return false
+	}
+	if !it.Valid() {
+		return it.Last()
+	}
+	if h := it.path[len(it.path)-1]; h.left != nil {
+		it.pushRight(h.left)
+		return true
+	}
+	// the previous key is the first ancestor found on the left
+	for len(it.path) > 1 {
+		child := it.path[len(it.path)-1]
+		it.path = it.path[:len(it.path)-1]
+		if it.path[len(it.path)-1].right == child {
+			return true
+		}
+	}
+	it.path, it.done = it.path[:0], true
+	return false
+}
+
+// Valid tells if the iterator is positioned at a key.
+func (it *MapIterator[K, V]) Valid() bool { return len(it.path) > 0 }
+
+// Key the iterator is positioned at. It panics if the iterator isn't
+// valid.
+func (it *MapIterator[K, V]) Key() K { return it.path[len(it.path)-1].key }
+
+// Value of the key the iterator is positioned at. It panics if the
+// iterator isn't valid.
+func (it *MapIterator[K, V]) Value() V { return it.path[len(it.path)-1].val }
+
+func (it *MapIterator[K, V]) pushLeft(h *mapnode[K, V]) {
+	for ; h != nil; h = h.left {
+		it.path = append(it.path, h)
+	}
+}
+
+func (it *MapIterator[K, V]) pushRight(h *mapnode[K, V]) {
+	for ; h != nil; h = h.right {
+		it.path = append(it.path, h)
+	}
+}
+
+// DeleteMin removes the smallest key and its value from the sorted map.
+func (r *Map[K, V]) DeleteMin() (oldk K, oldv V, ok bool) {
+	r.root, oldk, oldv, ok = r.deleteMin(r.root)
+	if !r.IsEmpty() {
+		r.root.colorRed = false
+	}
+	return
+}
+
+func (r *Map[K, V]) deleteMin(h *mapnode[K, V]) (_ *mapnode[K, V], oldk K, oldv V, ok bool) {
+	if h == nil {
+		return nil, oldk, oldv, false
+	}
+
+	if h.left == nil {
+		return nil, h.key, h.val, true
+	}
+	if !h.left.isRed() && !h.left.left.isRed() {
+		h = r.moveRedLeft(h)
+	}
+	h.left, oldk, oldv, ok = r.deleteMin(h.left)
+	return r.balance(h), oldk, oldv, ok
+}
+
+// DeleteMax removes the largest key and its value from the sorted map.
+func (r *Map[K, V]) DeleteMax() (oldk K, oldv V, ok bool) {
+	r.root, oldk, oldv, ok = r.deleteMax(r.root)
+	if !r.IsEmpty() {
+		r.root.colorRed = false
+	}
+	return
+}
+
+func (r *Map[K, V]) deleteMax(h *mapnode[K, V]) (_ *mapnode[K, V], oldk K, oldv V, ok bool) {
+	if h == nil {
+		return nil, oldk, oldv, ok
+	}
+	if h.left.isRed() {
+		h = r.rotateRight(h)
+	}
+	if h.right == nil {
+		return nil, h.key, h.val, true
+	}
+	if !h.right.isRed() && !h.right.left.isRed() {
+		h = r.moveRedRight(h)
+	}
+	h.right, oldk, oldv, ok = r.deleteMax(h.right)
+	return r.balance(h), oldk, oldv, ok
+}
+
+// Delete key `k` from sorted map, if it exists.
+func (r *Map[K, V]) Delete(k K) (old V, ok bool) {
+	if r.root == nil {
+		return
+	}
+	r.root, old, ok = r.delete(r.root, k)
+	if !r.IsEmpty() {
+		r.root.colorRed = false
+	}
+	return
+}
+
+func (r *Map[K, V]) delete(h *mapnode[K, V], k K) (_ *mapnode[K, V], old V, ok bool) {
+
+	if h == nil {
+		return h, old, false
+	}
+
+	if r.compare(k, h.key) < 0 {
+		if h.left == nil {
+			return h, old, false
+		}
+
+		if !h.left.isRed() && !h.left.left.isRed() {
+			h = r.moveRedLeft(h)
+		}
+
+		h.left, old, ok = r.delete(h.left, k)
+		h = r.balance(h)
+		return h, old, ok
+	}
+
+	if h.left.isRed() {
+		h = r.rotateRight(h)
+	}
+
+	if r.compare(k, h.key) == 0 && h.right == nil {
+		return nil, h.val, true
+	}
+
+	if h.right != nil && !h.right.isRed() && !h.right.left.isRed() {
+		h = r.moveRedRight(h)
+	}
+
+	if r.compare(k, h.key) == 0 {
+
+		var subk K
+		var subv V
+		h.right, subk, subv, ok = r.deleteMin(h.right)
+
+		old, h.key, h.val = h.val, subk, subv
+		ok = true
+	} else {
+		h.right, old, ok = r.delete(h.right, k)
+	}
+
+	h = r.balance(h)
+	return h, old, ok
+}
+
+// DeleteRange removes the keys between lo and hi from the sorted map, and
+// returns how many there were. A few keys are deleted one by one, in
+// O(log n) each, and otherwise the sorted map is rebuilt from the keys
+// left in O(n).
+func (r *Map[K, V]) DeleteRange(lo, hi K) int {
+	count := r.CountRange(lo, hi)
+	size := r.Size()
+	if count*bits.Len(uint(size)) < size {
+		for i := 0; i < count; i++ {
+			r.Delete(r.ceiling(r.root, lo).key)
+		}
+		return count
+	}
+	kept := r.outside(r.root, lo, hi, make([]*mapnode[K, V], 0, size-count))
+	// the smallest height of a 2-3 tree holding the keys left, which holds
+	// up to 3^(height+1)-1 keys
+	height := 0
+	for max := 2; max < len(kept); max = 3*max + 2 {
+		height++
+	}
+	r.root = r.build(kept, height)
+	return count
+}
+
+// outside appends the nodes of h whose keys aren't between lo and hi to
+// nodes, in order.
+func (r *Map[K, V]) outside(h *mapnode[K, V], lo, hi K, nodes []*mapnode[K, V]) []*mapnode[K, V] {
+	if h == nil {
+		return nodes
+	}
+	nodes = r.outside(h.left, lo, hi, nodes)
+	if r.compare(h.key, lo) < 0 || r.compare(h.key, hi) > 0 {
+		nodes = append(nodes, h)
+	}
+	return r.outside(h.right, lo, hi, nodes)
+}
+
+// build links nodes, in order, into a 2-3 tree of the given height, whose
+// 3-nodes are a black node leaning on a red left child. A 2-3 tree of that
+// height holds between 2^(height+1)-1 and 3^(height+1)-1 keys, which nodes
+// must be within.
+func (r *Map[K, V]) build(nodes []*mapnode[K, V], height int) *mapnode[K, V] {
+	if len(nodes) == 0 {
+		return nil
+	}
+	// the subtrees hold up to 3^height-1 keys
+	max := 1
+	for i := 0; i < height; i++ {
+		max *= 3
+	}
+	max--
+
+	if len(nodes) <= 2*max+1 {
+		mid := len(nodes) / 2
+		h := nodes[mid]
+		h.left = r.build(nodes[:mid], height-1)
+		h.right = r.build(nodes[mid+1:], height-1)
+		h.n, h.colorRed = len(nodes), false
+		return h
+	}
+	a := (len(nodes) - 2) / 3
+	b := (len(nodes) - 2 - a) / 2
+	left, h := nodes[a], nodes[a+b+1]
+	left.left = r.build(nodes[:a], height-1)
+	left.right = r.build(nodes[a+1:a+b+1], height-1)
+	left.n, left.colorRed = a+b+1, true
+	h.left = left
+	h.right = r.build(nodes[a+b+2:], height-1)
+	h.n, h.colorRed = len(nodes), false
+	return h
+}
+
+// deletions
+
+func (r *Map[K, V]) moveRedLeft(h *mapnode[K, V]) *mapnode[K, V] {
+	r.flipColors(h)
+	if h.right.left.isRed() {
+		h.right = r.rotateRight(h.right)
+		h = r.rotateLeft(h)
+		r.flipColors(h)
+	}
+	return h
+}
+
+func (r *Map[K, V]) moveRedRight(h *mapnode[K, V]) *mapnode[K, V] {
+	r.flipColors(h)
+	if h.left.left.isRed() {
+		h = r.rotateRight(h)
+		r.flipColors(h)
+	}
+	return h
+}
+
+func (r *Map[K, V]) balance(h *mapnode[K, V]) *mapnode[K, V] {
+	if h.right.isRed() {
+		h = r.rotateLeft(h)
+	}
+	if h.left.isRed() && h.left.left.isRed() {
+		h = r.rotateRight(h)
+	}
+	if h.left.isRed() && h.right.isRed() {
+		r.flipColors(h)
+	}
+	h.n = h.left.size() + h.right.size() + 1
+	return h
+}
+
+func (r *Map[K, V]) rotateLeft(h *mapnode[K, V]) *mapnode[K, V] {
+	x := h.right
+	h.right = x.left
+	x.left = h
+	x.colorRed = h.colorRed
+	h.colorRed = true
+	x.n = h.n
+	h.n = 1 + h.left.size() + h.right.size()
+	return x
+}
+
+func (r *Map[K, V]) rotateRight(h *mapnode[K, V]) *mapnode[K, V] {
+	x := h.left
+	h.left = x.right
+	x.right = h
+	x.colorRed = h.colorRed
+	h.colorRed = true
+	x.n = h.n
+	h.n = 1 + h.left.size() + h.right.size()
+	return x
+}
+
+func (r *Map[K, V]) flipColors(h *mapnode[K, V]) {
+	h.colorRed = !h.colorRed
+	h.left.colorRed = !h.left.colorRed
+	h.right.colorRed = !h.right.colorRed
+}
+
+// nodes
+
+type mapnode[K, V any] struct {
+	key         K
+	val         V
+	left, right *mapnode[K, V]
+	n           int
+	colorRed    bool
+}
+
+func (x *mapnode[K, V]) isRed() bool { return (x != nil) && (x.colorRed == true) }
+
+func (x *mapnode[K, V]) size() int {
+	if x == nil {
+		return 0
+	}
+	return x.n
+}
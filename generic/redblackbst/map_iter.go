@@ -0,0 +1,48 @@
+// Code generated by datagen master. DO NOT EDIT.
+//
+// The command that generated this file was:
+//
+//	datagen generic-library -kind smap -iterators -o map_iter.go
+
+//go:build go1.23
+
+package redblackbst
+
+import "iter"
+
+// All returns the keys/values of the sorted map, in increasing order.
+func (r Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		r.Keys(yield)
+	}
+}
+
+// Backward returns the keys/values of the sorted map, in decreasing order.
+func (r Map[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		r.ReverseKeys(yield)
+	}
+}
+
+// Range returns the keys/values between lo and hi in the sorted map, in
+// increasing order.
+func (r Map[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		r.RangedKeys(lo, hi, yield)
+	}
+}
+
+// AllKeys returns the keys of the sorted map, in increasing order.
+func (r Map[K, V]) AllKeys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		r.Keys(func(k K, _ V) bool { return yield(k) })
+	}
+}
+
+// AllValues returns the values of the sorted map, in the increasing order
+// of their keys.
+func (r Map[K, V]) AllValues() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		r.Keys(func(_ K, v V) bool { return yield(v) })
+	}
+}
@@ -0,0 +1,60 @@
+//go:build go1.23
+
+package redblackbst
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestMapIterators(t *testing.T) {
+	m := NewMap[int, string]()
+	for _, k := range []int{3, 1, 4, 5, 9, 2, 6} {
+		m.Put(k, string(rune('a'+k)))
+	}
+
+	var keys []int
+	for k, v := range m.All() {
+		if want := string(rune('a' + k)); v != want {
+			t.Errorf("want %q for %d, got %q", want, k, v)
+		}
+		keys = append(keys, k)
+	}
+	if want := []int{1, 2, 3, 4, 5, 6, 9}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("want All=%v, got %v", want, keys)
+	}
+
+	keys = keys[:0]
+	for k := range m.Backward() {
+		keys = append(keys, k)
+	}
+	if want := []int{9, 6, 5, 4, 3, 2, 1}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("want Backward=%v, got %v", want, keys)
+	}
+
+	keys = keys[:0]
+	for k := range m.Range(2, 5) {
+		keys = append(keys, k)
+	}
+	if want := []int{2, 3, 4, 5}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("want Range=%v, got %v", want, keys)
+	}
+
+	if got, want := slices.Collect(m.AllKeys()), []int{1, 2, 3, 4, 5, 6, 9}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want AllKeys=%v, got %v", want, got)
+	}
+	if got, want := slices.Collect(m.AllValues()), []string{"b", "c", "d", "e", "f", "g", "j"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want AllValues=%v, got %v", want, got)
+	}
+}
+
+func TestSetAll(t *testing.T) {
+	s := NewSet[string]()
+	for _, k := range []string{"b", "c", "a"} {
+		s.Put(k)
+	}
+	if got, want := slices.Collect(s.All()), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("want All=%v, got %v", want, got)
+	}
+}
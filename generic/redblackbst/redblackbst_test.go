@@ -0,0 +1,156 @@
+package redblackbst
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestMap(t *testing.T) {
+	m := NewMap[int, string]()
+	for _, k := range rand.Perm(100) {
+		if _, overwrite := m.Put(k, "first"); overwrite {
+			t.Fatalf("%d: overwrote a value that wasn't there", k)
+		}
+	}
+	if old, overwrite := m.Put(42, "second"); !overwrite || old != "first" {
+		t.Errorf("want overwrite of %q, got %v, %q", "first", overwrite, old)
+	}
+	if m.Size() != 100 {
+		t.Errorf("want Size=%d, was %d", 100, m.Size())
+	}
+	if v, ok := m.Get(42); !ok || v != "second" {
+		t.Errorf("want Get=%q, was %v, %q", "second", ok, v)
+	}
+
+	want := 0
+	m.Keys(func(k int, v string) bool {
+		if k != want {
+			t.Errorf("want key %d, got %d", want, k)
+		}
+		want++
+		return true
+	})
+	if want != 100 {
+		t.Errorf("want %d keys, visited %d", 100, want)
+	}
+
+	if k, _, ok := m.Floor(-1); ok {
+		t.Errorf("want no floor of -1, got %d", k)
+	}
+	if k, _, ok := m.Max(); !ok || k != 99 {
+		t.Errorf("want Max=%d, was %v, %d", 99, ok, k)
+	}
+}
+
+func TestMapFunc(t *testing.T) {
+	t0 := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMapFunc[time.Time, int](time.Time.Compare)
+	for _, i := range rand.Perm(10) {
+		m.Put(t0.Add(time.Duration(i)*time.Hour), i)
+	}
+	if k, v, ok := m.Min(); !ok || !k.Equal(t0) || v != 0 {
+		t.Errorf("want Min=%v, was %v, %v, %d", t0, ok, k, v)
+	}
+}
+
+func TestMapIterator(t *testing.T) {
+	m := NewMap[int, string]()
+	for _, k := range rand.Perm(100) {
+		m.Put(2*k, "even")
+	}
+
+	// the next 10 keys after 41
+	it := m.Iterator()
+	var page []int
+	for ok := it.SeekCeiling(41); ok && len(page) < 10; ok = it.Next() {
+		page = append(page, it.Key())
+	}
+	if len(page) != 10 || page[0] != 42 || page[9] != 60 {
+		t.Errorf("want the 10 keys from 42 to 60, got %v", page)
+	}
+	if !it.Prev() || it.Key() != 60 || it.Value() != "even" {
+		t.Errorf("want to go back to 60, got %v", it.Valid())
+	}
+}
+
+func TestSetReverseKeysBelow(t *testing.T) {
+	s := NewSet[int]()
+	for _, k := range rand.Perm(100) {
+		s.Put(k)
+	}
+
+	// the latest 3 keys before 50
+	var latest []int
+	s.ReverseKeysBelow(50, false, func(k int) bool {
+		latest = append(latest, k)
+		return len(latest) < 3
+	})
+	if len(latest) != 3 || latest[0] != 49 || latest[1] != 48 || latest[2] != 47 {
+		t.Errorf("want the keys 49, 48 and 47, got %v", latest)
+	}
+}
+
+func TestSetLowerHigher(t *testing.T) {
+	s := NewSet[int]()
+	for _, k := range []int{10, 20, 30} {
+		s.Put(k)
+	}
+	if k, ok := s.Lower(20); !ok || k != 10 {
+		t.Errorf("want 10 lower than 20, got %d (%v)", k, ok)
+	}
+	if k, ok := s.Higher(20); !ok || k != 30 {
+		t.Errorf("want 30 higher than 20, got %d (%v)", k, ok)
+	}
+	if rank, ok := s.FloorRank(25); !ok || rank != 1 {
+		t.Errorf("want the floor of 25 at rank 1, got %d (%v)", rank, ok)
+	}
+}
+
+func TestMapDeleteRange(t *testing.T) {
+	m := NewMap[int, string]()
+	for _, k := range rand.Perm(1000) {
+		m.Put(k, "kept")
+	}
+
+	// evict the keys older than 900
+	if count := m.CountRange(0, 899); count != 900 {
+		t.Errorf("want 900 keys to evict, counted %d", count)
+	}
+	if count := m.DeleteRange(0, 899); count != 900 {
+		t.Errorf("want 900 keys evicted, got %d", count)
+	}
+	if k, _, _ := m.Min(); m.Size() != 100 || k != 900 {
+		t.Errorf("want the 100 keys from 900 left, got %d from %d", m.Size(), k)
+	}
+}
+
+func TestSet(t *testing.T) {
+	s := NewSet[string]()
+	for _, k := range []string{"c", "a", "b", "a"} {
+		s.Put(k)
+	}
+	if s.Size() != 3 {
+		t.Errorf("want Size=%d, was %d", 3, s.Size())
+	}
+	var got []string
+	s.Keys(func(k string) bool {
+		got = append(got, k)
+		return true
+	})
+	if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("want keys [a b c], got %v", got)
+	}
+	if !s.Delete("b") || s.Contains("b") {
+		t.Error("b wasn't deleted")
+	}
+}
+
+func TestSetFunc(t *testing.T) {
+	s := NewSetFunc[[]int](func(a, b []int) int { return len(a) - len(b) })
+	s.Put([]int{1, 2})
+	s.Put([]int{1})
+	if k, ok := s.Min(); !ok || len(k) != 1 {
+		t.Errorf("want Min of length 1, was %v, %v", ok, k)
+	}
+}
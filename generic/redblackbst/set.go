@@ -0,0 +1,706 @@
+// Code generated by datagen master. DO NOT EDIT.
+//
+// The command that generated this file was:
+//
+//	datagen generic-library -kind sset -o set.go
+
+package redblackbst
+
+import (
+	"cmp"
+	"math/bits"
+)
+
+func (r Set[K]) compare(a, b K) int { return r.compareFunc(a, b) }
+
+// Set is a sorted set built on a left leaning red black balanced
+// search sorted set. It stores unique K values.
+type Set[K any] struct {
+	root        *treenode[K]
+	compareFunc func(a, b K) int
+}
+
+// NewSet creates a sorted set of ordered keys.
+func NewSet[K cmp.Ordered]() *Set[K] {
+	return NewSetFunc[K](cmp.Compare[K])
+}
+
+// NewSetFunc creates a sorted set where keys are ordered by compare.
+func NewSetFunc[K any](compare func(a, b K) int) *Set[K] {
+	return &Set[K]{compareFunc: compare}
+}
+
+// IsEmpty tells if the sorted set contains no key.
+func (r Set[K]) IsEmpty() bool {
+	return r.root == nil
+}
+
+// Size of the sorted set.
+func (r Set[K]) Size() int { return r.root.size() }
+
+// Clear all the values in the sorted set.
+func (r *Set[K]) Clear() { r.root = nil }
+
+// Put the key `k` in the sorted set. If the value was already there,
+// true is returned.
+func (r *Set[K]) Put(k K) (already bool) {
+	r.root, already = r.put(r.root, k)
+	return
+}
+
+func (r *Set[K]) put(h *treenode[K], k K) (_ *treenode[K], already bool) {
+	if h == nil {
+		n := &treenode[K]{key: k, n: 1, colorRed: true}
+		return n, already
+	}
+
+	cmp := r.compare(k, h.key)
+	if cmp < 0 {
+		h.left, already = r.put(h.left, k)
+	} else if cmp > 0 {
+		h.right, already = r.put(h.right, k)
+	} else {
+		already = true
+	}
+
+	if h.right.isRed() && !h.left.isRed() {
+		h = r.rotateLeft(h)
+	}
+	if h.left.isRed() && h.left.left.isRed() {
+		h = r.rotateRight(h)
+	}
+	if h.left.isRed() && h.right.isRed() {
+		r.flipColors(h)
+	}
+	h.n = h.left.size() + h.right.size() + 1
+	return h, already
+}
+
+// Contains tells if `k` is a member of the set.
+func (r Set[K]) Contains(k K) bool {
+	return r.loopContains(r.root, k)
+}
+
+func (r Set[K]) loopContains(h *treenode[K], k K) (ok bool) {
+	for h != nil {
+		cmp := r.compare(k, h.key)
+		if cmp == 0 {
+			return true
+		} else if cmp < 0 {
+			h = h.left
+		} else if cmp > 0 {
+			h = h.right
+		}
+	}
+	return
+}
+
+// Min returns the smallest key in the sorted set, if it exists.
+func (r Set[K]) Min() (k K, ok bool) {
+	if r.root == nil {
+		return
+	}
+	h := r.min(r.root)
+	return h.key, true
+}
+
+func (r Set[K]) min(x *treenode[K]) *treenode[K] {
+	if x.left == nil {
+		return x
+	}
+	return r.min(x.left)
+}
+
+// Max returns the largest key in the sorted set, if it exists.
+func (r Set[K]) Max() (k K, ok bool) {
+	if r.root == nil {
+		return
+	}
+	h := r.max(r.root)
+	return h.key, true
+}
+
+func (r Set[K]) max(x *treenode[K]) *treenode[K] {
+	if x.right == nil {
+		return x
+	}
+	return r.max(x.right)
+}
+
+// Floor returns the largest key in the sorted set that is smaller than
+// or equal to `k`.
+func (r Set[K]) Floor(key K) (k K, ok bool) {
+	x := r.floor(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, true
+}
+
+func (r Set[K]) floor(h *treenode[K], k K) *treenode[K] {
+	if h == nil {
+		return nil
+	}
+	cmp := r.compare(k, h.key)
+	if cmp == 0 {
+		return h
+	}
+	if cmp < 0 {
+		return r.floor(h.left, k)
+	}
+	t := r.floor(h.right, k)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// Ceiling returns the smallest key in the sorted set that is larger than
+// or equal to `k`.
+func (r Set[K]) Ceiling(key K) (k K, ok bool) {
+	x := r.ceiling(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, true
+}
+
+func (r Set[K]) ceiling(h *treenode[K], k K) *treenode[K] {
+	if h == nil {
+		return nil
+	}
+	cmp := r.compare(k, h.key)
+	if cmp == 0 {
+		return h
+	}
+	if cmp > 0 {
+		return r.ceiling(h.right, k)
+	}
+	t := r.ceiling(h.left, k)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// Lower returns the largest key in the sorted set that is strictly
+// smaller than `k`.
+func (r Set[K]) Lower(key K) (k K, ok bool) {
+	x := r.lower(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, true
+}
+
+func (r Set[K]) lower(h *treenode[K], k K) *treenode[K] {
+	if h == nil {
+		return nil
+	}
+	if r.compare(k, h.key) <= 0 {
+		return r.lower(h.left, k)
+	}
+	t := r.lower(h.right, k)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// Higher returns the smallest key in the sorted set that is strictly
+// larger than `k`.
+func (r Set[K]) Higher(key K) (k K, ok bool) {
+	x := r.higher(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, true
+}
+
+func (r Set[K]) higher(h *treenode[K], k K) *treenode[K] {
+	if h == nil {
+		return nil
+	}
+	if r.compare(k, h.key) >= 0 {
+		return r.higher(h.right, k)
+	}
+	t := r.higher(h.left, k)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// FloorRank returns the rank of the floor of `k`, if there's one. Its
+// neighbours in the sorted set are at the next and previous ranks, which
+// Select finds.
+func (r Set[K]) FloorRank(key K) (rank int, ok bool) {
+	x := r.floor(r.root, key)
+	if x == nil {
+		return
+	}
+	return r.keyrank(x.key, r.root), true
+}
+
+// CeilingRank returns the rank of the ceiling of `k`, if there's one. Its
+// neighbours in the sorted set are at the next and previous ranks, which
+// Select finds.
+func (r Set[K]) CeilingRank(key K) (rank int, ok bool) {
+	rank = r.keyrank(key, r.root)
+	return rank, rank < r.Size()
+}
+
+// Select key of rank k, meaning the k-th biggest K in the sorted set.
+func (r Set[K]) Select(key int) (k K, ok bool) {
+	x := r.nodeselect(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, true
+}
+
+func (r Set[K]) nodeselect(x *treenode[K], k int) *treenode[K] {
+	if x == nil {
+		return nil
+	}
+	t := x.left.size()
+	if t > k {
+		return r.nodeselect(x.left, k)
+	} else if t < k {
+		return r.nodeselect(x.right, k-t-1)
+	} else {
+		return x
+	}
+}
+
+// Rank is the number of keys less than `k`.
+func (r Set[K]) Rank(k K) int {
+	return r.keyrank(k, r.root)
+}
+
+func (r Set[K]) keyrank(k K, h *treenode[K]) int {
+	if h == nil {
+		return 0
+	}
+	cmp := r.compare(k, h.key)
+	if cmp < 0 {
+		return r.keyrank(k, h.left)
+	} else if cmp > 0 {
+		return 1 + h.left.size() + r.keyrank(k, h.right)
+	} else {
+		return h.left.size()
+	}
+}
+
+// CountRange returns the number of keys between lo and hi in the sorted
+// set, which it counts from their ranks in O(log n).
+func (r Set[K]) CountRange(lo, hi K) int {
+	if r.compare(lo, hi) > 0 {
+		return 0
+	}
+	count := r.keyrank(hi, r.root) - r.keyrank(lo, r.root)
+	if r.Contains(hi) {
+		count++
+	}
+	return count
+}
+
+// Keys visit each keys in the sorted set, in order.
+// It stops when visit returns false.
+func (r Set[K]) Keys(visit func(K) bool) {
+	min, ok := r.Min()
+	if !ok {
+		return
+	}
+	// if the min exists, then the max must exist
+	max, _ := r.Max()
+	r.RangedKeys(min, max, visit)
+}
+
+// RangedKeys visit each keys between lo and hi in the sorted set, in order.
+// It stops when visit returns false.
+func (r Set[K]) RangedKeys(lo, hi K, visit func(K) bool) {
+	r.keys(r.root, visit, lo, hi)
+}
+
+func (r Set[K]) keys(h *treenode[K], visit func(K) bool, lo, hi K) bool {
+	if h == nil {
+		return true
+	}
+	cmplo := r.compare(lo, h.key)
+	cmphi := r.compare(hi, h.key)
+	if cmplo < 0 {
+		if !r.keys(h.left, visit, lo, hi) {
+			return false
+		}
+	}
+	if cmplo <= 0 && cmphi >= 0 {
+		if !visit(h.key) {
+			return false
+		}
+	}
+	if cmphi > 0 {
+		if !r.keys(h.right, visit, lo, hi) {
+			return false
+		}
+	}
+	return true
+}
+
+// KeysBetween visit each keys between lo and hi in the sorted set, in
+// order, like RangedKeys, but leaves lo out unless loInclusive and hi out
+// unless hiInclusive: `KeysBetween(lo, true, hi, false, visit)` visits the
+// keys of [lo, hi). It stops when visit returns false.
+func (r Set[K]) KeysBetween(lo K, loInclusive bool, hi K, hiInclusive bool, visit func(K) bool) {
+	r.bounded(r.root, visit, r.above(lo, loInclusive), r.below(hi, hiInclusive), false)
+}
+
+// KeysAbove visit each keys larger than lo in the sorted set, or equal to
+// it if inclusive, in order. It stops when visit returns false.
+func (r Set[K]) KeysAbove(lo K, inclusive bool, visit func(K) bool) {
+	r.bounded(r.root, visit, r.above(lo, inclusive), r.unbounded, false)
+}
+
+// KeysBelow visit each keys smaller than hi in the sorted set, or equal to
+// it if inclusive, in order. It stops when visit returns false.
+func (r Set[K]) KeysBelow(hi K, inclusive bool, visit func(K) bool) {
+	r.bounded(r.root, visit, r.unbounded, r.below(hi, inclusive), false)
+}
+
+// ReverseKeys visit each keys in the sorted set, in reverse order.
+// It stops when visit returns false.
+func (r Set[K]) ReverseKeys(visit func(K) bool) {
+	r.bounded(r.root, visit, r.unbounded, r.unbounded, true)
+}
+
+// ReverseRangedKeys visit each keys between lo and hi in the sorted set,
+// in reverse order, from hi down to lo. It stops when visit returns false.
+func (r Set[K]) ReverseRangedKeys(lo, hi K, visit func(K) bool) {
+	r.bounded(r.root, visit, r.above(lo, true), r.below(hi, true), true)
+}
+
+// ReverseKeysBetween is KeysBetween, visiting the keys in reverse order.
+func (r Set[K]) ReverseKeysBetween(lo K, loInclusive bool, hi K, hiInclusive bool, visit func(K) bool) {
+	r.bounded(r.root, visit, r.above(lo, loInclusive), r.below(hi, hiInclusive), true)
+}
+
+// ReverseKeysAbove is KeysAbove, visiting the keys in reverse order.
+func (r Set[K]) ReverseKeysAbove(lo K, inclusive bool, visit func(K) bool) {
+	r.bounded(r.root, visit, r.above(lo, inclusive), r.unbounded, true)
+}
+
+// ReverseKeysBelow is KeysBelow, visiting the keys in reverse order, like
+// the latest keys before hi.
+func (r Set[K]) ReverseKeysBelow(hi K, inclusive bool, visit func(K) bool) {
+	r.bounded(r.root, visit, r.unbounded, r.below(hi, inclusive), true)
+}
+
+// bounded visits the keys of h within bounds, in order or in reverse.
+// aboveLo and belowHi tell if a key is within the lower and the upper bound,
+// which are only crossed once going through the keys in order.
+func (r Set[K]) bounded(h *treenode[K], visit func(K) bool, aboveLo, belowHi func(K) bool, reverse bool) bool {
+	if h == nil {
+		return true
+	}
+	inLo, inHi := aboveLo(h.key), belowHi(h.key)
+	// the keys of the left subtree are all out of bounds when h is below
+	// lo, and those of the right subtree when h is above hi
+	first, last := h.left, h.right
+	inFirst, inLast := inLo, inHi
+	if reverse {
+		first, last = last, first
+		inFirst, inLast = inLast, inFirst
+	}
+	if inFirst && !r.bounded(first, visit, aboveLo, belowHi, reverse) {
+		return false
+	}
+	if inLo && inHi && !visit(h.key) {
+		return false
+	}
+	if inLast && !r.bounded(last, visit, aboveLo, belowHi, reverse) {
+		return false
+	}
+	return true
+}
+
+// above returns the lower bound lo, which keys are within when they're
+// larger than lo, or equal to it if inclusive.
+func (r Set[K]) above(lo K, inclusive bool) func(K) bool {
+	return func(k K) bool {
+		cmp := r.compare(k, lo)
+		return cmp > 0 || inclusive && cmp == 0
+	}
+}
+
+// below returns the upper bound hi, which keys are within when they're
+// smaller than hi, or equal to it if inclusive.
+func (r Set[K]) below(hi K, inclusive bool) func(K) bool {
+	return func(k K) bool {
+		cmp := r.compare(k, hi)
+		return cmp < 0 || inclusive && cmp == 0
+	}
+}
+
+// unbounded is the bound of an open end, which all keys are within.
+func (r Set[K]) unbounded(K) bool { return true }
+
+// DeleteMin removes the smallest key from the sorted set.
+func (r *Set[K]) DeleteMin() (oldk K, ok bool) {
+	r.root, oldk, ok = r.deleteMin(r.root)
+	if !r.IsEmpty() {
+		r.root.colorRed = false
+	}
+	return
+}
+
+func (r *Set[K]) deleteMin(h *treenode[K]) (_ *treenode[K], oldk K, ok bool) {
+	if h == nil {
+		return nil, oldk, false
+	}
+
+	if h.left == nil {
+		return nil, h.key, true
+	}
+	if !h.left.isRed() && !h.left.left.isRed() {
+		h = r.moveRedLeft(h)
+	}
+	h.left, oldk, ok = r.deleteMin(h.left)
+	return r.balance(h), oldk, ok
+}
+
+// DeleteMax removes the largest key from the sorted set.
+func (r *Set[K]) DeleteMax() (oldk K, ok bool) {
+	r.root, oldk, ok = r.deleteMax(r.root)
+	if !r.IsEmpty() {
+		r.root.colorRed = false
+	}
+	return
+}
+
+func (r *Set[K]) deleteMax(h *treenode[K]) (_ *treenode[K], oldk K, ok bool) {
+	if h == nil {
+		return nil, oldk, ok
+	}
+	if h.left.isRed() {
+		h = r.rotateRight(h)
+	}
+	if h.right == nil {
+		return nil, h.key, true
+	}
+	if !h.right.isRed() && !h.right.left.isRed() {
+		h = r.moveRedRight(h)
+	}
+	h.right, oldk, ok = r.deleteMax(h.right)
+	return r.balance(h), oldk, ok
+}
+
+// Delete key `k` from sorted set, if it exists.
+func (r *Set[K]) Delete(k K) (ok bool) {
+	if r.root == nil {
+		return
+	}
+	r.root, ok = r.delete(r.root, k)
+	if !r.IsEmpty() {
+		r.root.colorRed = false
+	}
+	return
+}
+
+func (r *Set[K]) delete(h *treenode[K], k K) (_ *treenode[K], ok bool) {
+
+	if h == nil {
+		return h, false
+	}
+
+	if r.compare(k, h.key) < 0 {
+		if h.left == nil {
+			return h, false
+		}
+
+		if !h.left.isRed() && !h.left.left.isRed() {
+			h = r.moveRedLeft(h)
+		}
+
+		h.left, ok = r.delete(h.left, k)
+		h = r.balance(h)
+		return h, ok
+	}
+
+	if h.left.isRed() {
+		h = r.rotateRight(h)
+	}
+
+	if r.compare(k, h.key) == 0 && h.right == nil {
+		return nil, true
+	}
+
+	if h.right != nil && !h.right.isRed() && !h.right.left.isRed() {
+		h = r.moveRedRight(h)
+	}
+
+	if r.compare(k, h.key) == 0 {
+
+		var subk K
+		h.right, subk, ok = r.deleteMin(h.right)
+		h.key = subk
+		ok = true
+	} else {
+		h.right, ok = r.delete(h.right, k)
+	}
+
+	h = r.balance(h)
+	return h, ok
+}
+
+// DeleteRange removes the keys between lo and hi from the sorted set, and
+// returns how many there were. A few keys are deleted one by one, in
+// O(log n) each, and otherwise the sorted set is rebuilt from the keys
+// left in O(n).
+func (r *Set[K]) DeleteRange(lo, hi K) int {
+	count := r.CountRange(lo, hi)
+	size := r.Size()
+	if count*bits.Len(uint(size)) < size {
+		for i := 0; i < count; i++ {
+			r.Delete(r.ceiling(r.root, lo).key)
+		}
+		return count
+	}
+	kept := r.outside(r.root, lo, hi, make([]*treenode[K], 0, size-count))
+	// the smallest height of a 2-3 tree holding the keys left, which holds
+	// up to 3^(height+1)-1 keys
+	height := 0
+	for max := 2; max < len(kept); max = 3*max + 2 {
+		height++
+	}
+	r.root = r.build(kept, height)
+	return count
+}
+
+// outside appends the nodes of h whose keys aren't between lo and hi to
+// nodes, in order.
+func (r *Set[K]) outside(h *treenode[K], lo, hi K, nodes []*treenode[K]) []*treenode[K] {
+	if h == nil {
+		return nodes
+	}
+	nodes = r.outside(h.left, lo, hi, nodes)
+	if r.compare(h.key, lo) < 0 || r.compare(h.key, hi) > 0 {
+		nodes = append(nodes, h)
+	}
+	return r.outside(h.right, lo, hi, nodes)
+}
+
+// build links nodes, in order, into a 2-3 tree of the given height, whose
+// 3-nodes are a black node leaning on a red left child. A 2-3 tree of that
+// height holds between 2^(height+1)-1 and 3^(height+1)-1 keys, which nodes
+// must be within.
+func (r *Set[K]) build(nodes []*treenode[K], height int) *treenode[K] {
+	if len(nodes) == 0 {
+		return nil
+	}
+	// the subtrees hold up to 3^height-1 keys
+	max := 1
+	for i := 0; i < height; i++ {
+		max *= 3
+	}
+	max--
+
+	if len(nodes) <= 2*max+1 {
+		mid := len(nodes) / 2
+		h := nodes[mid]
+		h.left = r.build(nodes[:mid], height-1)
+		h.right = r.build(nodes[mid+1:], height-1)
+		h.n, h.colorRed = len(nodes), false
+		return h
+	}
+	a := (len(nodes) - 2) / 3
+	b := (len(nodes) - 2 - a) / 2
+	left, h := nodes[a], nodes[a+b+1]
+	left.left = r.build(nodes[:a], height-1)
+	left.right = r.build(nodes[a+1:a+b+1], height-1)
+	left.n, left.colorRed = a+b+1, true
+	h.left = left
+	h.right = r.build(nodes[a+b+2:], height-1)
+	h.n, h.colorRed = len(nodes), false
+	return h
+}
+
+// deletions
+
+func (r *Set[K]) moveRedLeft(h *treenode[K]) *treenode[K] {
+	r.flipColors(h)
+	if h.right.left.isRed() {
+		h.right = r.rotateRight(h.right)
+		h = r.rotateLeft(h)
+		r.flipColors(h)
+	}
+	return h
+}
+
+func (r *Set[K]) moveRedRight(h *treenode[K]) *treenode[K] {
+	r.flipColors(h)
+	if h.left.left.isRed() {
+		h = r.rotateRight(h)
+		r.flipColors(h)
+	}
+	return h
+}
+
+func (r *Set[K]) balance(h *treenode[K]) *treenode[K] {
+	if h.right.isRed() {
+		h = r.rotateLeft(h)
+	}
+	if h.left.isRed() && h.left.left.isRed() {
+		h = r.rotateRight(h)
+	}
+	if h.left.isRed() && h.right.isRed() {
+		r.flipColors(h)
+	}
+	h.n = h.left.size() + h.right.size() + 1
+	return h
+}
+
+func (r *Set[K]) rotateLeft(h *treenode[K]) *treenode[K] {
+	x := h.right
+	h.right = x.left
+	x.left = h
+	x.colorRed = h.colorRed
+	h.colorRed = true
+	x.n = h.n
+	h.n = 1 + h.left.size() + h.right.size()
+	return x
+}
+
+func (r *Set[K]) rotateRight(h *treenode[K]) *treenode[K] {
+	x := h.left
+	h.left = x.right
+	x.right = h
+	x.colorRed = h.colorRed
+	h.colorRed = true
+	x.n = h.n
+	h.n = 1 + h.left.size() + h.right.size()
+	return x
+}
+
+func (r *Set[K]) flipColors(h *treenode[K]) {
+	h.colorRed = !h.colorRed
+	h.left.colorRed = !h.left.colorRed
+	h.right.colorRed = !h.right.colorRed
+}
+
+// nodes
+
+type treenode[K any] struct {
+	key         K
+	left, right *treenode[K]
+	n           int
+	colorRed    bool
+}
+
+func (x *treenode[K]) isRed() bool { return (x != nil) && (x.colorRed == true) }
+
+func (x *treenode[K]) size() int {
+	if x == nil {
+		return 0
+	}
+	return x.n
+}
@@ -0,0 +1,18 @@
+// Code generated by datagen master. DO NOT EDIT.
+//
+// The command that generated this file was:
+//
+//	datagen generic-library -kind sset -iterators -o set_iter.go
+
+//go:build go1.23
+
+package redblackbst
+
+import "iter"
+
+// All returns the keys of the sorted set, in increasing order.
+func (r Set[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		r.Keys(yield)
+	}
+}
@@ -1,9 +1,16 @@
 module github.com/aybabtme/datagen
 
-go 1.12
+go 1.22.0
 
 require (
 	github.com/oklog/ulid v1.3.1
 	github.com/pkg/browser v0.0.0-20170505125900-c90ca0c84f15
+	golang.org/x/tools v0.24.1
 	gopkg.in/urfave/cli.v1 v1.20.0
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require (
+	golang.org/x/mod v0.21.0 // indirect
+	golang.org/x/sync v0.8.0 // indirect
 )
@@ -9,6 +9,9 @@ type Int int
 
 func (i Int) Compare(other KType) int { return int(i - other.(Int)) }
 
+// genKType generates the keys of the tests found in template_test.go.
+func genKType(i int) KType { return Int(i) }
+
 // Adapted from `container/heap`.
 
 // Copyright 2009 The Go Authors. All rights reserved.
@@ -169,6 +172,24 @@ func TestHeapRemoveNotThere(t *testing.T) {
 	}
 }
 
+// Removing a key below the top once swapped it with the top, and sank the
+// top where the key was, so a larger key could be left below a smaller one.
+func TestHeapRemoveKeepsTheOrder(t *testing.T) {
+	h := NewHeap()
+	for _, i := range []int{9, 8, 7, 1, 2, 3, 4} {
+		h.Push(Int(i))
+	}
+	if !h.Remove(Int(1)) {
+		t.Fatal("should have removed 1")
+	}
+	verify(t, h, 0)
+	for _, want := range []int{9, 8, 7, 4, 3, 2} {
+		if got := h.Pop(); got != Int(want) {
+			t.Errorf("pop got %v; want %v", got, want)
+		}
+	}
+}
+
 func BenchmarkDup(b *testing.B) {
 	const n = 10000
 	buf := make([]KType, 0, n)
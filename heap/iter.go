@@ -0,0 +1,21 @@
+//go:build go1.23
+
+package heap
+
+import "iter"
+
+// The iterators of this file are only generated along the heaps of the
+// modules whose Go version supports them, from Go 1.23 on.
+
+// Drain returns the elements of the heap in decreasing order, removing
+// them as they're iterated over. The elements left once the iteration
+// stops stay in the heap.
+func (h *Heap) Drain() iter.Seq[KType] {
+	return func(yield func(KType) bool) {
+		for h.Len() > 0 {
+			if !yield(h.Pop()) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package heap
+
+// GENERATED CODE!!!
+
+// The benchmarks of this file are also generated along the heaps, by
+// `datagen heap -bench`. They only rely on genKType(i), which returns the
+// keys of the benchmarks. Each benchmark is followed by the same work done
+// with container/heap, as a baseline.
+
+import (
+	stdheap "container/heap"
+	"math/rand"
+	"testing"
+)
+
+// keysHeapBench returns n keys in a random order.
+func keysHeapBench(n int) []KType {
+	keys := make([]KType, 0, n)
+	for _, i := range rand.Perm(n) {
+		keys = append(keys, genKType(i))
+	}
+	return keys
+}
+
+// baselineHeap is a container/heap of the same keys, with the same
+// comparison rules.
+type baselineHeap []KType
+
+func (h baselineHeap) Len() int            { return len(h) }
+func (h baselineHeap) Less(i, j int) bool  { return Heap{}.compare(h[i], h[j]) > 0 }
+func (h baselineHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *baselineHeap) Push(x interface{}) { *h = append(*h, x.(KType)) }
+func (h *baselineHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+func BenchmarkHeap_Push(b *testing.B) {
+	keys := keysHeapBench(b.N)
+	h := NewHeap()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Push(keys[i])
+	}
+}
+
+func BenchmarkHeap_Push_ContainerHeap(b *testing.B) {
+	keys := keysHeapBench(b.N)
+	h := &baselineHeap{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stdheap.Push(h, keys[i])
+	}
+}
+
+func BenchmarkHeap_Pop(b *testing.B) {
+	h := NewHeap(keysHeapBench(b.N)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.Pop()
+	}
+}
+
+func BenchmarkHeap_Pop_ContainerHeap(b *testing.B) {
+	h := baselineHeap(keysHeapBench(b.N))
+	stdheap.Init(&h)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stdheap.Pop(&h)
+	}
+}
+
+func BenchmarkHeap_PushPop(b *testing.B) {
+	const n = 10000
+	keys := keysHeapBench(n)
+	h := NewHeap(make([]KType, 0, n)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, k := range keys {
+			h.Push(k)
+		}
+		for h.Len() > 0 {
+			h.Pop()
+		}
+	}
+}
+
+func BenchmarkHeap_PushPop_ContainerHeap(b *testing.B) {
+	const n = 10000
+	keys := keysHeapBench(n)
+	h := make(baselineHeap, 0, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, k := range keys {
+			stdheap.Push(&h, k)
+		}
+		for h.Len() > 0 {
+			stdheap.Pop(&h)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+//go:build go1.23
+
+package heap
+
+// The tests of this file are also generated along the heaps that have
+// iterators, by `datagen heap -tests`.
+
+import "testing"
+
+func TestHeap_Drain(t *testing.T) {
+	const n = 100
+	h := newHeapTest(t, n)
+
+	i := n - 1
+	for k := range h.Drain() {
+		if h.compare(k, genKType(i)) != 0 {
+			t.Fatalf("want %v, got %v", genKType(i), k)
+		}
+		if i--; i < n/2 {
+			break
+		}
+	}
+	if h.Len() != n/2 {
+		t.Fatalf("want %d elements left once the iteration stopped, got %d", n/2, h.Len())
+	}
+	checkHeap(t, h)
+	for range h.Drain() {
+	}
+	if h.Len() != 0 {
+		t.Errorf("want the heap drained, %d elements left", h.Len())
+	}
+}
@@ -0,0 +1,122 @@
+package heap
+
+// GENERATED CODE!!!
+
+// The tests of this file are also generated along the heaps, by `datagen
+// heap -tests`. They only rely on genKType(i), which returns keys that
+// increase with i, so that they can run against any key type.
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// newHeapTest returns a heap holding the keys 0 to n-1, put in a random
+// order.
+func newHeapTest(t *testing.T, n int) *Heap {
+	h := NewHeap()
+	for _, i := range rand.Perm(n) {
+		h.Push(genKType(i))
+		checkHeap(t, h)
+	}
+	return h
+}
+
+// checkHeap verifies that no element of the heap is larger than its parent.
+func checkHeap(t *testing.T, h *Heap) {
+	if len(h.pq) != h.Len()+1 {
+		t.Fatalf("heap of Len=%d holds %d elements", h.Len(), len(h.pq)-1)
+	}
+	for i := 2; i <= h.Len(); i++ {
+		if h.compare(h.pq[i/2], h.pq[i]) < 0 {
+			t.Fatalf("heap invariant invalidated: [%d] = %v < [%d] = %v", i/2, h.pq[i/2], i, h.pq[i])
+		}
+	}
+}
+
+func TestHeap_PushPop(t *testing.T) {
+	const n = 200
+	h := newHeapTest(t, n)
+	if h.Len() != n {
+		t.Fatalf("want Len=%d, got %d", n, h.Len())
+	}
+	for i := n - 1; i >= 0; i-- {
+		if k := h.Peek(); h.compare(k, genKType(i)) != 0 {
+			t.Fatalf("want to peek %v, got %v", genKType(i), k)
+		}
+		if k := h.Pop(); h.compare(k, genKType(i)) != 0 {
+			t.Fatalf("want to pop %v, got %v", genKType(i), k)
+		}
+		checkHeap(t, h)
+	}
+	if h.Len() != 0 {
+		t.Fatalf("want Len=0 once everything is popped, got %d", h.Len())
+	}
+}
+
+func TestHeap_NewWithKeys(t *testing.T) {
+	const n = 200
+	keys := make([]KType, 0, n)
+	for _, i := range rand.Perm(n) {
+		keys = append(keys, genKType(i))
+	}
+	h := NewHeap(keys...)
+	checkHeap(t, h)
+	for i := n - 1; i >= 0; i-- {
+		if k := h.Pop(); h.compare(k, genKType(i)) != 0 {
+			t.Fatalf("want to pop %v, got %v", genKType(i), k)
+		}
+	}
+}
+
+func TestHeap_Duplicates(t *testing.T) {
+	h := NewHeap()
+	for i := 0; i < 20; i++ {
+		h.Push(genKType(0))
+		h.Push(genKType(1))
+		checkHeap(t, h)
+	}
+	for i := 0; i < 40; i++ {
+		want := genKType(1)
+		if i >= 20 {
+			want = genKType(0)
+		}
+		if k := h.Pop(); h.compare(k, want) != 0 {
+			t.Fatalf("%d.th pop: want %v, got %v", i, want, k)
+		}
+		checkHeap(t, h)
+	}
+}
+
+func TestHeap_Remove(t *testing.T) {
+	const n = 100
+	h := newHeapTest(t, n)
+
+	if h.Remove(genKType(n)) {
+		t.Errorf("removed %v, which is larger than the largest", genKType(n))
+	}
+	for _, i := range rand.Perm(n) {
+		if !h.Remove(genKType(i)) {
+			t.Fatalf("should have removed %v", genKType(i))
+		}
+		checkHeap(t, h)
+		if h.Len() > 0 && h.Remove(genKType(i)) {
+			t.Fatalf("removed %v twice", genKType(i))
+		}
+	}
+	if h.Len() != 0 {
+		t.Fatalf("want Len=0 once everything is removed, got %d", h.Len())
+	}
+}
+
+func TestHeap_Fix(t *testing.T) {
+	const n = 100
+	h := newHeapTest(t, n)
+
+	// change the elements in place, as if their comparison value changed
+	for i := 0; i < 100; i++ {
+		h.pq[1+rand.Intn(h.Len())] = genKType(rand.Intn(2 * n))
+		h.Fix()
+		checkHeap(t, h)
+	}
+}
@@ -28,6 +28,11 @@ func (k K) Compare(other KType) int {
 	return 0
 }
 
+// genKType and genVType generate the keys and values of the tests found in
+// template_test.go.
+func genKType(i int) KType { return K(fmt.Sprintf("key:%08d", i)) }
+func genVType(i int) VType { return fmt.Sprintf("value:%d", i) }
+
 func loadWordlist(filename string, fallback []string) (out []string) {
 	if testing.Short() {
 		return fallback
@@ -0,0 +1,45 @@
+//go:build go1.23
+
+package redblackbst
+
+import "iter"
+
+// The iterators of this file are only generated along the sorted maps of
+// the modules whose Go version supports them, from Go 1.23 on.
+
+// All returns the keys/values of the sorted map, in increasing order.
+func (r RedBlack) All() iter.Seq2[KType, VType] {
+	return func(yield func(KType, VType) bool) {
+		r.Keys(yield)
+	}
+}
+
+// Backward returns the keys/values of the sorted map, in decreasing order.
+func (r RedBlack) Backward() iter.Seq2[KType, VType] {
+	return func(yield func(KType, VType) bool) {
+		r.ReverseKeys(yield)
+	}
+}
+
+// Range returns the keys/values between lo and hi in the sorted map, in
+// increasing order.
+func (r RedBlack) Range(lo, hi KType) iter.Seq2[KType, VType] {
+	return func(yield func(KType, VType) bool) {
+		r.RangedKeys(lo, hi, yield)
+	}
+}
+
+// AllKeys returns the keys of the sorted map, in increasing order.
+func (r RedBlack) AllKeys() iter.Seq[KType] {
+	return func(yield func(KType) bool) {
+		r.Keys(func(k KType, _ VType) bool { return yield(k) })
+	}
+}
+
+// AllValues returns the values of the sorted map, in the increasing order
+// of their keys.
+func (r RedBlack) AllValues() iter.Seq[VType] {
+	return func(yield func(VType) bool) {
+		r.Keys(func(_ KType, v VType) bool { return yield(v) })
+	}
+}
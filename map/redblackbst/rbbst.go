@@ -2,6 +2,8 @@ package redblackbst
 
 // GENERATED CODE!!!
 
+import "math/bits"
+
 func (r RedBlack) compare(a, b KType) int { return a.Compare(b) }
 
 // RedBlack is a sorted map built on a left leaning red black balanced
@@ -125,7 +127,7 @@ func (r RedBlack) max(x *mapnode) *mapnode {
 }
 
 // Floor returns the largest key/value in the sorted map that is smaller than
-// `k`.
+// or equal to `k`.
 func (r RedBlack) Floor(key KType) (k KType, v VType, ok bool) {
 	x := r.floor(r.root, key)
 	if x == nil {
@@ -153,7 +155,7 @@ func (r RedBlack) floor(h *mapnode, k KType) *mapnode {
 }
 
 // Ceiling returns the smallest key/value in the sorted map that is larger than
-// `k`.
+// or equal to `k`.
 func (r RedBlack) Ceiling(key KType) (k KType, v VType, ok bool) {
 	x := r.ceiling(r.root, key)
 	if x == nil {
@@ -180,6 +182,73 @@ func (r RedBlack) ceiling(h *mapnode, k KType) *mapnode {
 	return h
 }
 
+// Lower returns the largest key/value in the sorted map that is strictly
+// smaller than `k`.
+func (r RedBlack) Lower(key KType) (k KType, v VType, ok bool) {
+	x := r.lower(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, x.val, true
+}
+
+func (r RedBlack) lower(h *mapnode, k KType) *mapnode {
+	if h == nil {
+		return nil
+	}
+	if r.compare(k, h.key) <= 0 {
+		return r.lower(h.left, k)
+	}
+	t := r.lower(h.right, k)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// Higher returns the smallest key/value in the sorted map that is strictly
+// larger than `k`.
+func (r RedBlack) Higher(key KType) (k KType, v VType, ok bool) {
+	x := r.higher(r.root, key)
+	if x == nil {
+		return
+	}
+	return x.key, x.val, true
+}
+
+func (r RedBlack) higher(h *mapnode, k KType) *mapnode {
+	if h == nil {
+		return nil
+	}
+	if r.compare(k, h.key) >= 0 {
+		return r.higher(h.right, k)
+	}
+	t := r.higher(h.left, k)
+	if t != nil {
+		return t
+	}
+	return h
+}
+
+// FloorRank returns the rank of the floor of `k`, if there's one. Its
+// neighbours in the sorted map are at the next and previous ranks, which
+// Select finds.
+func (r RedBlack) FloorRank(key KType) (rank int, ok bool) {
+	x := r.floor(r.root, key)
+	if x == nil {
+		return
+	}
+	return r.keyrank(x.key, r.root), true
+}
+
+// CeilingRank returns the rank of the ceiling of `k`, if there's one. Its
+// neighbours in the sorted map are at the next and previous ranks, which
+// Select finds.
+func (r RedBlack) CeilingRank(key KType) (rank int, ok bool) {
+	rank = r.keyrank(key, r.root)
+	return rank, rank < r.Size()
+}
+
 // Select key of rank k, meaning the k-th biggest KType in the sorted map.
 func (r RedBlack) Select(key int) (k KType, v VType, ok bool) {
 	x := r.nodeselect(r.root, key)
@@ -222,6 +291,19 @@ func (r RedBlack) keyrank(k KType, h *mapnode) int {
 	}
 }
 
+// CountRange returns the number of keys between lo and hi in the sorted
+// map, which it counts from their ranks in O(log n).
+func (r RedBlack) CountRange(lo, hi KType) int {
+	if r.compare(lo, hi) > 0 {
+		return 0
+	}
+	count := r.keyrank(hi, r.root) - r.keyrank(lo, r.root)
+	if r.Has(hi) {
+		count++
+	}
+	return count
+}
+
 // Keys visit each keys in the sorted map, in order.
 // It stops when visit returns false.
 func (r RedBlack) Keys(visit func(KType, VType) bool) {
@@ -264,6 +346,253 @@ func (r RedBlack) keys(h *mapnode, visit func(KType, VType) bool, lo, hi KType)
 	return true
 }
 
+// KeysBetween visit each keys between lo and hi in the sorted map, in
+// order, like RangedKeys, but leaves lo out unless loInclusive and hi out
+// unless hiInclusive: `KeysBetween(lo, true, hi, false, visit)` visits the
+// keys of [lo, hi). It stops when visit returns false.
+func (r RedBlack) KeysBetween(lo KType, loInclusive bool, hi KType, hiInclusive bool, visit func(KType, VType) bool) {
+	r.bounded(r.root, visit, r.above(lo, loInclusive), r.below(hi, hiInclusive), false)
+}
+
+// KeysAbove visit each keys larger than lo in the sorted map, or equal to
+// it if inclusive, in order. It stops when visit returns false.
+func (r RedBlack) KeysAbove(lo KType, inclusive bool, visit func(KType, VType) bool) {
+	r.bounded(r.root, visit, r.above(lo, inclusive), r.unbounded, false)
+}
+
+// KeysBelow visit each keys smaller than hi in the sorted map, or equal to
+// it if inclusive, in order. It stops when visit returns false.
+func (r RedBlack) KeysBelow(hi KType, inclusive bool, visit func(KType, VType) bool) {
+	r.bounded(r.root, visit, r.unbounded, r.below(hi, inclusive), false)
+}
+
+// ReverseKeys visit each keys in the sorted map, in reverse order.
+// It stops when visit returns false.
+func (r RedBlack) ReverseKeys(visit func(KType, VType) bool) {
+	r.bounded(r.root, visit, r.unbounded, r.unbounded, true)
+}
+
+// ReverseRangedKeys visit each keys between lo and hi in the sorted map,
+// in reverse order, from hi down to lo. It stops when visit returns false.
+func (r RedBlack) ReverseRangedKeys(lo, hi KType, visit func(KType, VType) bool) {
+	r.bounded(r.root, visit, r.above(lo, true), r.below(hi, true), true)
+}
+
+// ReverseKeysBetween is KeysBetween, visiting the keys in reverse order.
+func (r RedBlack) ReverseKeysBetween(lo KType, loInclusive bool, hi KType, hiInclusive bool, visit func(KType, VType) bool) {
+	r.bounded(r.root, visit, r.above(lo, loInclusive), r.below(hi, hiInclusive), true)
+}
+
+// ReverseKeysAbove is KeysAbove, visiting the keys in reverse order.
+func (r RedBlack) ReverseKeysAbove(lo KType, inclusive bool, visit func(KType, VType) bool) {
+	r.bounded(r.root, visit, r.above(lo, inclusive), r.unbounded, true)
+}
+
+// ReverseKeysBelow is KeysBelow, visiting the keys in reverse order, like
+// the latest keys before hi.
+func (r RedBlack) ReverseKeysBelow(hi KType, inclusive bool, visit func(KType, VType) bool) {
+	r.bounded(r.root, visit, r.unbounded, r.below(hi, inclusive), true)
+}
+
+// bounded visits the keys of h within bounds, in order or in reverse.
+// aboveLo and belowHi tell if a key is within the lower and the upper bound,
+// which are only crossed once going through the keys in order.
+func (r RedBlack) bounded(h *mapnode, visit func(KType, VType) bool, aboveLo, belowHi func(KType) bool, reverse bool) bool {
+	if h == nil {
+		return true
+	}
+	inLo, inHi := aboveLo(h.key), belowHi(h.key)
+	// the keys of the left subtree are all out of bounds when h is below
+	// lo, and those of the right subtree when h is above hi
+	first, last := h.left, h.right
+	inFirst, inLast := inLo, inHi
+	if reverse {
+		first, last = last, first
+		inFirst, inLast = inLast, inFirst
+	}
+	if inFirst && !r.bounded(first, visit, aboveLo, belowHi, reverse) {
+		return false
+	}
+	if inLo && inHi && !visit(h.key, h.val) {
+		return false
+	}
+	if inLast && !r.bounded(last, visit, aboveLo, belowHi, reverse) {
+		return false
+	}
+	return true
+}
+
+// above returns the lower bound lo, which keys are within when they're
+// larger than lo, or equal to it if inclusive.
+func (r RedBlack) above(lo KType, inclusive bool) func(KType) bool {
+	return func(k KType) bool {
+		cmp := r.compare(k, lo)
+		return cmp > 0 || inclusive && cmp == 0
+	}
+}
+
+// below returns the upper bound hi, which keys are within when they're
+// smaller than hi, or equal to it if inclusive.
+func (r RedBlack) below(hi KType, inclusive bool) func(KType) bool {
+	return func(k KType) bool {
+		cmp := r.compare(k, hi)
+		return cmp < 0 || inclusive && cmp == 0
+	}
+}
+
+// unbounded is the bound of an open end, which all keys are within.
+func (r RedBlack) unbounded(KType) bool { return true }
+
+// Iterator returns an iterator over the keys/values of the sorted map, in
+// order. It isn't positioned yet: Next moves it to the smallest key, and
+// Prev to the largest one.
+func (r *RedBlack) Iterator() *RedBlackIterator {
+	return &RedBlackIterator{tree: r}
+}
+
+// RedBlackIterator is a cursor over the keys/values of a sorted map, which
+// moves in either direction. Putting a new key in the sorted map or
+// deleting one invalidates its iterators: seek the last key seen to
+// resume.
+type RedBlackIterator struct {
+	tree *RedBlack
+	// path from the root to the node of the current key, empty when the
+	// iterator isn't positioned at a key.
+	path []*mapnode
+	// done once the iterator moved past either end of the sorted map. It
+	// stays there until it's positioned again.
+	done bool
+}
+
+// First moves the iterator to the smallest key, and tells if there's one.
+func (it *RedBlackIterator) First() bool {
+	it.path, it.done = it.path[:0], false
+	it.pushLeft(it.tree.root)
+	return it.Valid()
+}
+
+// Last moves the iterator to the largest key, and tells if there's one.
+func (it *RedBlackIterator) Last() bool {
+	it.path, it.done = it.path[:0], false
+	it.pushRight(it.tree.root)
+	return it.Valid()
+}
+
+// SeekCeiling moves the iterator to the smallest key larger than or equal
+// to `k`, and tells if there's one.
+func (it *RedBlackIterator) SeekCeiling(k KType) bool {
+	it.path, it.done = it.path[:0], false
+	// the key sought is the last one of the path to `k` that's larger
+	ceiling := 0
+	for h := it.tree.root; h != nil; {
+		it.path = append(it.path, h)
+		cmp := it.tree.compare(k, h.key)
+		if cmp == 0 {
+			return true
+		} else if cmp < 0 {
+			ceiling = len(it.path)
+			h = h.left
+		} else {
+			h = h.right
+		}
+	}
+	it.path, it.done = it.path[:ceiling], ceiling == 0
+	return it.Valid()
+}
+
+// SeekFloor moves the iterator to the largest key smaller than or equal
+// to `k`, and tells if there's one.
+func (it *RedBlackIterator) SeekFloor(k KType) bool {
+	it.path, it.done = it.path[:0], false
+	// the key sought is the last one of the path to `k` that's smaller
+	floor := 0
+	for h := it.tree.root; h != nil; {
+		it.path = append(it.path, h)
+		cmp := it.tree.compare(k, h.key)
+		if cmp == 0 {
+			return true
+		} else if cmp > 0 {
+			floor = len(it.path)
+			h = h.right
+		} else {
+			h = h.left
+		}
+	}
+	it.path, it.done = it.path[:floor], floor == 0
+	return it.Valid()
+}
+
+// Next moves the iterator to the next key, and tells if there's one.
+func (it *RedBlackIterator) Next() bool {
+	if it.done {
+		return false
+	}
+	if !it.Valid() {
+		return it.First()
+	}
+	if h := it.path[len(it.path)-1]; h.right != nil {
+		it.pushLeft(h.right)
+		return true
+	}
+	// the next key is the first ancestor found on the right
+	for len(it.path) > 1 {
+		child := it.path[len(it.path)-1]
+		it.path = it.path[:len(it.path)-1]
+		if it.path[len(it.path)-1].left == child {
+			return true
+		}
+	}
+	it.path, it.done = it.path[:0], true
+	return false
+}
+
+// Prev moves the iterator to the previous key, and tells if there's one.
+func (it *RedBlackIterator) Prev() bool {
+	if it.done {
+		return false
+	}
+	if !it.Valid() {
+		return it.Last()
+	}
+	if h := it.path[len(it.path)-1]; h.left != nil {
+		it.pushRight(h.left)
+		return true
+	}
+	// the previous key is the first ancestor found on the left
+	for len(it.path) > 1 {
+		child := it.path[len(it.path)-1]
+		it.path = it.path[:len(it.path)-1]
+		if it.path[len(it.path)-1].right == child {
+			return true
+		}
+	}
+	it.path, it.done = it.path[:0], true
+	return false
+}
+
+// Valid tells if the iterator is positioned at a key.
+func (it *RedBlackIterator) Valid() bool { return len(it.path) > 0 }
+
+// Key the iterator is positioned at. It panics if the iterator isn't
+// valid.
+func (it *RedBlackIterator) Key() KType { return it.path[len(it.path)-1].key }
+
+// Value of the key the iterator is positioned at. It panics if the
+// iterator isn't valid.
+func (it *RedBlackIterator) Value() VType { return it.path[len(it.path)-1].val }
+
+func (it *RedBlackIterator) pushLeft(h *mapnode) {
+	for ; h != nil; h = h.left {
+		it.path = append(it.path, h)
+	}
+}
+
+func (it *RedBlackIterator) pushRight(h *mapnode) {
+	for ; h != nil; h = h.right {
+		it.path = append(it.path, h)
+	}
+}
+
 // DeleteMin removes the smallest key and its value from the sorted map.
 func (r *RedBlack) DeleteMin() (oldk KType, oldv VType, ok bool) {
 	r.root, oldk, oldv, ok = r.deleteMin(r.root)
@@ -374,6 +703,78 @@ func (r *RedBlack) delete(h *mapnode, k KType) (_ *mapnode, old VType, ok bool)
 	return h, old, ok
 }
 
+// DeleteRange removes the keys between lo and hi from the sorted map, and
+// returns how many there were. A few keys are deleted one by one, in
+// O(log n) each, and otherwise the sorted map is rebuilt from the keys
+// left in O(n).
+func (r *RedBlack) DeleteRange(lo, hi KType) int {
+	count := r.CountRange(lo, hi)
+	size := r.Size()
+	if count*bits.Len(uint(size)) < size {
+		for i := 0; i < count; i++ {
+			r.Delete(r.ceiling(r.root, lo).key)
+		}
+		return count
+	}
+	kept := r.outside(r.root, lo, hi, make([]*mapnode, 0, size-count))
+	// the smallest height of a 2-3 tree holding the keys left, which holds
+	// up to 3^(height+1)-1 keys
+	height := 0
+	for max := 2; max < len(kept); max = 3*max + 2 {
+		height++
+	}
+	r.root = r.build(kept, height)
+	return count
+}
+
+// outside appends the nodes of h whose keys aren't between lo and hi to
+// nodes, in order.
+func (r *RedBlack) outside(h *mapnode, lo, hi KType, nodes []*mapnode) []*mapnode {
+	if h == nil {
+		return nodes
+	}
+	nodes = r.outside(h.left, lo, hi, nodes)
+	if r.compare(h.key, lo) < 0 || r.compare(h.key, hi) > 0 {
+		nodes = append(nodes, h)
+	}
+	return r.outside(h.right, lo, hi, nodes)
+}
+
+// build links nodes, in order, into a 2-3 tree of the given height, whose
+// 3-nodes are a black node leaning on a red left child. A 2-3 tree of that
+// height holds between 2^(height+1)-1 and 3^(height+1)-1 keys, which nodes
+// must be within.
+func (r *RedBlack) build(nodes []*mapnode, height int) *mapnode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	// the subtrees hold up to 3^height-1 keys
+	max := 1
+	for i := 0; i < height; i++ {
+		max *= 3
+	}
+	max--
+
+	if len(nodes) <= 2*max+1 {
+		mid := len(nodes) / 2
+		h := nodes[mid]
+		h.left = r.build(nodes[:mid], height-1)
+		h.right = r.build(nodes[mid+1:], height-1)
+		h.n, h.colorRed = len(nodes), false
+		return h
+	}
+	a := (len(nodes) - 2) / 3
+	b := (len(nodes) - 2 - a) / 2
+	left, h := nodes[a], nodes[a+b+1]
+	left.left = r.build(nodes[:a], height-1)
+	left.right = r.build(nodes[a+1:a+b+1], height-1)
+	left.n, left.colorRed = a+b+1, true
+	h.left = left
+	h.right = r.build(nodes[a+b+2:], height-1)
+	h.n, h.colorRed = len(nodes), false
+	return h
+}
+
 // deletions
 
 func (r *RedBlack) moveRedLeft(h *mapnode) *mapnode {
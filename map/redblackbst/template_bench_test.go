@@ -0,0 +1,107 @@
+package redblackbst
+
+// GENERATED CODE!!!
+
+// The benchmarks of this file are also generated along the sorted maps, by
+// `datagen smap -bench`. They only rely on genKType(i), which returns keys,
+// and genVType(i), which returns values. When the keys can be used in a Go
+// map, the benchmarks are followed by the same work done with a map, as a
+// baseline.
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// keysRedBlackBench returns n keys in a random order.
+func keysRedBlackBench(n int) []KType {
+	keys := make([]KType, 0, n)
+	for _, i := range rand.Perm(n) {
+		keys = append(keys, genKType(i))
+	}
+	return keys
+}
+
+// newRedBlackBench returns a sorted map holding the keys.
+func newRedBlackBench(keys []KType) *RedBlack {
+	tree := NewRedBlack()
+	v := genVType(0)
+	for _, k := range keys {
+		tree.Put(k, v)
+	}
+	return tree
+}
+
+// newRedBlackGoMapBench returns a Go map holding the keys.
+func newRedBlackGoMapBench(keys []KType) map[KType]VType {
+	m := make(map[KType]VType)
+	v := genVType(0)
+	for _, k := range keys {
+		m[k] = v
+	}
+	return m
+}
+
+func BenchmarkRedBlack_Put(b *testing.B) {
+	keys := keysRedBlackBench(b.N)
+	tree := NewRedBlack()
+	v := genVType(0)
+	b.ResetTimer()
+	for _, k := range keys {
+		tree.Put(k, v)
+	}
+}
+
+func BenchmarkRedBlack_Put_GoMap(b *testing.B) {
+	keys := keysRedBlackBench(b.N)
+	m := make(map[KType]VType)
+	v := genVType(0)
+	b.ResetTimer()
+	for _, k := range keys {
+		m[k] = v
+	}
+}
+
+func BenchmarkRedBlack_Get(b *testing.B) {
+	keys := keysRedBlackBench(b.N)
+	tree := newRedBlackBench(keys)
+	b.ResetTimer()
+	for _, k := range keys {
+		tree.Get(k)
+	}
+}
+
+func BenchmarkRedBlack_Get_GoMap(b *testing.B) {
+	keys := keysRedBlackBench(b.N)
+	m := newRedBlackGoMapBench(keys)
+	b.ResetTimer()
+	for _, k := range keys {
+		_ = m[k]
+	}
+}
+
+func BenchmarkRedBlack_Delete(b *testing.B) {
+	keys := keysRedBlackBench(b.N)
+	tree := newRedBlackBench(keys)
+	b.ResetTimer()
+	for _, k := range keys {
+		tree.Delete(k)
+	}
+}
+
+func BenchmarkRedBlack_Delete_GoMap(b *testing.B) {
+	keys := keysRedBlackBench(b.N)
+	m := newRedBlackGoMapBench(keys)
+	b.ResetTimer()
+	for _, k := range keys {
+		delete(m, k)
+	}
+}
+
+func BenchmarkRedBlack_DeleteMin(b *testing.B) {
+	tree := newRedBlackBench(keysRedBlackBench(b.N))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.DeleteMin()
+	}
+}
@@ -0,0 +1,66 @@
+//go:build go1.23
+
+package redblackbst
+
+// The tests of this file are also generated along the sorted maps that
+// have iterators, by `datagen smap -tests`.
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedBlack_All(t *testing.T) {
+	const n = 100
+	tree := newRedBlackTest(t, n)
+
+	i := 1
+	for k, v := range tree.All() {
+		if tree.compare(k, genKType(i)) != 0 || !reflect.DeepEqual(v, genVType(i)) {
+			t.Fatalf("want %v, got %v", genKType(i), k)
+		}
+		i += 2
+	}
+	if i != 2*n+1 {
+		t.Errorf("iterated over %d keys, want %d", i/2, n)
+	}
+
+	i = 2*n - 1
+	for k := range tree.Backward() {
+		if tree.compare(k, genKType(i)) != 0 {
+			t.Fatalf("want %v, got %v", genKType(i), k)
+		}
+		if i -= 2; i < n {
+			break
+		}
+	}
+	if i >= n {
+		t.Errorf("want to stop iterating backward at %v", genKType(n))
+	}
+
+	i = 11
+	for k := range tree.Range(genKType(10), genKType(21)) {
+		if tree.compare(k, genKType(i)) != 0 {
+			t.Fatalf("want %v, got %v", genKType(i), k)
+		}
+		i += 2
+	}
+	if i != 23 {
+		t.Errorf("want keys up to %v, stopped before %v", genKType(21), genKType(i))
+	}
+
+	i = 1
+	for k := range tree.AllKeys() {
+		if tree.compare(k, genKType(i)) != 0 {
+			t.Fatalf("want %v, got %v", genKType(i), k)
+		}
+		i += 2
+	}
+	i = 1
+	for v := range tree.AllValues() {
+		if !reflect.DeepEqual(v, genVType(i)) {
+			t.Fatalf("want %v, got %v", genVType(i), v)
+		}
+		i += 2
+	}
+}
@@ -0,0 +1,511 @@
+package redblackbst
+
+// GENERATED CODE!!!
+
+// The tests of this file are also generated along the sorted maps, by
+// `datagen smap -tests`. They only rely on genKType(i), which returns keys
+// that increase with i, and genVType(i), which returns values, so that they
+// can run against any key type.
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// newRedBlackTest returns a sorted map holding the n odd keys, put in a
+// random order, so that the even keys can be used to look around them.
+func newRedBlackTest(t *testing.T, n int) *RedBlack {
+	tree := NewRedBlack()
+	for _, i := range rand.Perm(n) {
+		if old, overwrite := tree.Put(genKType(2*i+1), genVType(2*i+1)); overwrite {
+			t.Fatalf("put %v: shouldn't have overwritten %v", genKType(2*i+1), old)
+		}
+	}
+	checkRedBlack(t, tree)
+	return tree
+}
+
+// checkRedBlack verifies the invariants of the sorted map: its keys are
+// visited in increasing order, and its tree is a balanced left leaning red
+// black tree where the size of every subtree is known.
+func checkRedBlack(t *testing.T, tree *RedBlack) {
+	size := 0
+	var last KType
+	tree.Keys(func(k KType, _ VType) bool {
+		if size > 0 && tree.compare(last, k) >= 0 {
+			t.Fatalf("key %v is visited after key %v", k, last)
+		}
+		last = k
+		size++
+		return true
+	})
+	if size != tree.Size() {
+		t.Fatalf("visited %d keys, want Size=%d", size, tree.Size())
+	}
+	// the root is black, even if the tree doesn't bother coloring it
+	checkRedBlackNode(t, tree.root, false)
+}
+
+// checkRedBlackNode verifies the subtree at x, of the given color, and
+// returns its number of black links.
+func checkRedBlackNode(t *testing.T, x *mapnode, red bool) int {
+	if x == nil {
+		return 0
+	}
+	if x.right.isRed() {
+		t.Fatalf("red link of %v leans right", x.key)
+	}
+	if red && x.left.isRed() {
+		t.Fatalf("two red links in a row at %v", x.key)
+	}
+	if want := 1 + x.left.size() + x.right.size(); x.n != want {
+		t.Fatalf("size of %v is %d, want %d", x.key, x.n, want)
+	}
+	left := checkRedBlackNode(t, x.left, x.left.isRed())
+	right := checkRedBlackNode(t, x.right, x.right.isRed())
+	if left != right {
+		t.Fatalf("%v is unbalanced, %d black links on the left, %d on the right", x.key, left, right)
+	}
+	if !red {
+		left++
+	}
+	return left
+}
+
+func TestRedBlack_Empty(t *testing.T) {
+	tree := NewRedBlack()
+	if !tree.IsEmpty() || tree.Size() != 0 {
+		t.Fatalf("new tree isn't empty, Size=%d", tree.Size())
+	}
+	if k, _, ok := tree.Min(); ok {
+		t.Errorf("empty tree has a min: %v", k)
+	}
+	if k, _, ok := tree.Max(); ok {
+		t.Errorf("empty tree has a max: %v", k)
+	}
+	if k, _, ok := tree.DeleteMin(); ok {
+		t.Errorf("empty tree deleted a min: %v", k)
+	}
+	if k, _, ok := tree.DeleteMax(); ok {
+		t.Errorf("empty tree deleted a max: %v", k)
+	}
+	tree.Keys(func(k KType, _ VType) bool {
+		t.Errorf("empty tree visited %v", k)
+		return true
+	})
+
+	tree = newRedBlackTest(t, 10)
+	tree.Clear()
+	if !tree.IsEmpty() || tree.Size() != 0 {
+		t.Fatalf("cleared tree isn't empty, Size=%d", tree.Size())
+	}
+}
+
+func TestRedBlack_PutGet(t *testing.T) {
+	const n = 200
+	tree := newRedBlackTest(t, n)
+	if tree.Size() != n {
+		t.Fatalf("want Size=%d, got %d", n, tree.Size())
+	}
+	for i := 0; i < 2*n; i++ {
+		v, ok := tree.Get(genKType(i))
+		if ok != (i%2 == 1) || ok != tree.Has(genKType(i)) {
+			t.Fatalf("get %v: found=%v, has=%v", genKType(i), ok, tree.Has(genKType(i)))
+		}
+		if ok && !reflect.DeepEqual(v, genVType(i)) {
+			t.Fatalf("get %v: want %v, got %v", genKType(i), genVType(i), v)
+		}
+	}
+
+	for i := 1; i < 2*n; i += 2 {
+		old, overwrite := tree.Put(genKType(i), genVType(i+1))
+		if !overwrite || !reflect.DeepEqual(old, genVType(i)) {
+			t.Fatalf("put %v: want overwrite of %v, got %v, %v", genKType(i), genVType(i), overwrite, old)
+		}
+		if v, _ := tree.Get(genKType(i)); !reflect.DeepEqual(v, genVType(i+1)) {
+			t.Fatalf("get %v: want %v, got %v", genKType(i), genVType(i+1), v)
+		}
+	}
+	checkRedBlack(t, tree)
+	if tree.Size() != n {
+		t.Fatalf("want Size=%d after overwrites, got %d", n, tree.Size())
+	}
+}
+
+func TestRedBlack_Mutate(t *testing.T) {
+	tree := NewRedBlack()
+	created, mutated := 0, 0
+	create := func() VType { created++; return genVType(0) }
+	mutate := func(old VType) VType {
+		if !reflect.DeepEqual(old, genVType(0)) {
+			t.Errorf("want to mutate %v, got %v", genVType(0), old)
+		}
+		mutated++
+		return genVType(1)
+	}
+	tree.Mutate(genKType(0), create, mutate)
+	tree.Mutate(genKType(0), create, mutate)
+	if created != 1 || mutated != 1 {
+		t.Fatalf("want 1 creation and 1 mutation, got %d and %d", created, mutated)
+	}
+	if v, _ := tree.Get(genKType(0)); !reflect.DeepEqual(v, genVType(1)) {
+		t.Fatalf("want %v, got %v", genVType(1), v)
+	}
+}
+
+func TestRedBlack_MinMax(t *testing.T) {
+	const n = 100
+	tree := newRedBlackTest(t, n)
+	if k, v, ok := tree.Min(); !ok || tree.compare(k, genKType(1)) != 0 || !reflect.DeepEqual(v, genVType(1)) {
+		t.Errorf("want min %v, got %v (%v)", genKType(1), k, ok)
+	}
+	if k, v, ok := tree.Max(); !ok || tree.compare(k, genKType(2*n-1)) != 0 || !reflect.DeepEqual(v, genVType(2*n-1)) {
+		t.Errorf("want max %v, got %v (%v)", genKType(2*n-1), k, ok)
+	}
+}
+
+func TestRedBlack_FloorCeiling(t *testing.T) {
+	const n = 100
+	tree := newRedBlackTest(t, n)
+
+	if k, _, ok := tree.Floor(genKType(0)); ok {
+		t.Errorf("want no floor below the min, got %v", k)
+	}
+	if k, _, ok := tree.Ceiling(genKType(2 * n)); ok {
+		t.Errorf("want no ceiling above the max, got %v", k)
+	}
+	for i := 1; i < 2*n; i++ {
+		floor, ceiling := i, i
+		if i%2 == 0 {
+			floor, ceiling = i-1, i+1
+		}
+		if k, _, ok := tree.Floor(genKType(i)); !ok || tree.compare(k, genKType(floor)) != 0 {
+			t.Errorf("want floor of %v to be %v, got %v (%v)", genKType(i), genKType(floor), k, ok)
+		}
+		if i == 2*n-1 {
+			continue
+		}
+		if k, _, ok := tree.Ceiling(genKType(i)); !ok || tree.compare(k, genKType(ceiling)) != 0 {
+			t.Errorf("want ceiling of %v to be %v, got %v (%v)", genKType(i), genKType(ceiling), k, ok)
+		}
+	}
+}
+
+func TestRedBlack_LowerHigher(t *testing.T) {
+	const n = 100
+	tree := newRedBlackTest(t, n)
+
+	if k, _, ok := tree.Lower(genKType(1)); ok {
+		t.Errorf("want nothing lower than the min, got %v", k)
+	}
+	if k, _, ok := tree.Higher(genKType(2*n - 1)); ok {
+		t.Errorf("want nothing higher than the max, got %v", k)
+	}
+	for i := 2; i < 2*n-1; i++ {
+		lower, higher := i-1, i+1
+		if i%2 == 1 {
+			lower, higher = i-2, i+2
+		}
+		if k, _, ok := tree.Lower(genKType(i)); !ok || tree.compare(k, genKType(lower)) != 0 {
+			t.Errorf("want lower of %v to be %v, got %v (%v)", genKType(i), genKType(lower), k, ok)
+		}
+		if k, _, ok := tree.Higher(genKType(i)); !ok || tree.compare(k, genKType(higher)) != 0 {
+			t.Errorf("want higher of %v to be %v, got %v (%v)", genKType(i), genKType(higher), k, ok)
+		}
+	}
+
+	if rank, ok := tree.FloorRank(genKType(0)); ok {
+		t.Errorf("want no floor rank below the min, got %d", rank)
+	}
+	if rank, ok := tree.CeilingRank(genKType(2 * n)); ok {
+		t.Errorf("want no ceiling rank above the max, got %d", rank)
+	}
+	for i := 1; i < 2*n; i++ {
+		// the keys 2*rank+1 are at rank
+		floor, ceiling := (i-1)/2, i/2
+		if rank, ok := tree.FloorRank(genKType(i)); !ok || rank != floor {
+			t.Errorf("want floor rank of %v to be %d, got %d (%v)", genKType(i), floor, rank, ok)
+		}
+		if rank, ok := tree.CeilingRank(genKType(i)); !ok || rank != ceiling {
+			t.Errorf("want ceiling rank of %v to be %d, got %d (%v)", genKType(i), ceiling, rank, ok)
+		}
+	}
+}
+
+func TestRedBlack_SelectRank(t *testing.T) {
+	const n = 100
+	tree := newRedBlackTest(t, n)
+	for i := 0; i < n; i++ {
+		if k, _, ok := tree.Select(i); !ok || tree.compare(k, genKType(2*i+1)) != 0 {
+			t.Errorf("want select %d to be %v, got %v (%v)", i, genKType(2*i+1), k, ok)
+		}
+		if rank := tree.Rank(genKType(2*i + 1)); rank != i {
+			t.Errorf("want rank of %v to be %d, got %d", genKType(2*i+1), i, rank)
+		}
+		if rank := tree.Rank(genKType(2 * i)); rank != i {
+			t.Errorf("want rank of %v to be %d, got %d", genKType(2*i), i, rank)
+		}
+	}
+	if k, _, ok := tree.Select(n); ok {
+		t.Errorf("want nothing selected past the max, got %v", k)
+	}
+}
+
+func TestRedBlack_Keys(t *testing.T) {
+	const n = 100
+	tree := newRedBlackTest(t, n)
+
+	i := 1
+	tree.Keys(func(k KType, v VType) bool {
+		if tree.compare(k, genKType(i)) != 0 || !reflect.DeepEqual(v, genVType(i)) {
+			t.Fatalf("want to visit %v, got %v", genKType(i), k)
+		}
+		i += 2
+		return true
+	})
+	if i != 2*n+1 {
+		t.Errorf("visited %d keys, want %d", i/2, n)
+	}
+
+	visited := 0
+	tree.Keys(func(KType, VType) bool {
+		visited++
+		return visited < 10
+	})
+	if visited != 10 {
+		t.Errorf("want to stop after 10 keys, visited %d", visited)
+	}
+
+	i = 11
+	tree.RangedKeys(genKType(10), genKType(21), func(k KType, _ VType) bool {
+		if tree.compare(k, genKType(i)) != 0 {
+			t.Fatalf("want to visit %v, got %v", genKType(i), k)
+		}
+		i += 2
+		return true
+	})
+	if i != 23 {
+		t.Errorf("want to visit keys up to %v, stopped before %v", genKType(21), genKType(i))
+	}
+}
+
+func TestRedBlack_BoundedKeys(t *testing.T) {
+	const n = 100
+	tree := newRedBlackTest(t, n)
+	key := genKType
+
+	tests := []struct {
+		name string
+		keys func(visit func(KType, VType) bool)
+		// first and last keys visited, by index, or 0 when none are
+		first, last int
+	}{
+		{"[11, 21)", func(visit func(KType, VType) bool) { tree.KeysBetween(key(11), true, key(21), false, visit) }, 11, 19},
+		{"(11, 21]", func(visit func(KType, VType) bool) { tree.KeysBetween(key(11), false, key(21), true, visit) }, 13, 21},
+		{"(10, 22)", func(visit func(KType, VType) bool) { tree.KeysBetween(key(10), false, key(22), false, visit) }, 11, 21},
+		{"(11, 13)", func(visit func(KType, VType) bool) { tree.KeysBetween(key(11), false, key(13), false, visit) }, 0, 0},
+		{"(189, inf)", func(visit func(KType, VType) bool) { tree.KeysAbove(key(189), false, visit) }, 191, 199},
+		{"[189, inf)", func(visit func(KType, VType) bool) { tree.KeysAbove(key(189), true, visit) }, 189, 199},
+		{"(-inf, 11)", func(visit func(KType, VType) bool) { tree.KeysBelow(key(11), false, visit) }, 1, 9},
+		{"(-inf, 11]", func(visit func(KType, VType) bool) { tree.KeysBelow(key(11), true, visit) }, 1, 11},
+		{"reversed", tree.ReverseKeys, 199, 1},
+		{"reversed [10, 21]", func(visit func(KType, VType) bool) { tree.ReverseRangedKeys(key(10), key(21), visit) }, 21, 11},
+		{"reversed [11, 21)", func(visit func(KType, VType) bool) { tree.ReverseKeysBetween(key(11), true, key(21), false, visit) }, 19, 11},
+		{"reversed (189, inf)", func(visit func(KType, VType) bool) { tree.ReverseKeysAbove(key(189), false, visit) }, 199, 191},
+		{"reversed (-inf, 11]", func(visit func(KType, VType) bool) { tree.ReverseKeysBelow(key(11), true, visit) }, 11, 1},
+	}
+	for _, tt := range tests {
+		var want []KType
+		if tt.first != 0 {
+			step := 2
+			if tt.first > tt.last {
+				step = -2
+			}
+			for i := tt.first; i != tt.last+step; i += step {
+				want = append(want, genKType(i))
+			}
+		}
+		var got []KType
+		tt.keys(func(k KType, _ VType) bool {
+			got = append(got, k)
+			return true
+		})
+		if len(got) != len(want) {
+			t.Errorf("%s: want %d keys, got %d", tt.name, len(want), len(got))
+			continue
+		}
+		for i := range want {
+			if tree.compare(got[i], want[i]) != 0 {
+				t.Errorf("%s: want %v at %d, got %v", tt.name, want[i], i, got[i])
+			}
+		}
+	}
+
+	visited := 0
+	tree.ReverseKeysBelow(genKType(101), false, func(KType, VType) bool {
+		visited++
+		return visited < 10
+	})
+	if visited != 10 {
+		t.Errorf("want to stop after 10 keys, visited %d", visited)
+	}
+}
+
+func TestRedBlack_Iterator(t *testing.T) {
+	const n = 100
+	tree := newRedBlackTest(t, n)
+
+	it := tree.Iterator()
+	for i := 1; i < 2*n; i += 2 {
+		if !it.Next() || tree.compare(it.Key(), genKType(i)) != 0 || !reflect.DeepEqual(it.Value(), genVType(i)) {
+			t.Fatalf("want next to be %v, got %v", genKType(i), it.Valid())
+		}
+	}
+	if it.Next() || it.Valid() || it.Prev() {
+		t.Fatal("want the iterator to stop past the max")
+	}
+	it = tree.Iterator()
+	for i := 2*n - 1; i > 0; i -= 2 {
+		if !it.Prev() || tree.compare(it.Key(), genKType(i)) != 0 {
+			t.Fatalf("want previous to be %v, got %v", genKType(i), it.Valid())
+		}
+	}
+	if it.Prev() || it.Next() {
+		t.Fatal("want the iterator to stop past the min")
+	}
+	if !it.First() || tree.compare(it.Key(), genKType(1)) != 0 {
+		t.Errorf("want first to be %v", genKType(1))
+	}
+	if !it.Last() || tree.compare(it.Key(), genKType(2*n-1)) != 0 {
+		t.Errorf("want last to be %v", genKType(2*n-1))
+	}
+
+	for i := 0; i < 2*n; i++ {
+		ceiling := i | 1
+		if !it.SeekCeiling(genKType(i)) || tree.compare(it.Key(), genKType(ceiling)) != 0 {
+			t.Fatalf("want to seek the ceiling of %v at %v, got %v", genKType(i), genKType(ceiling), it.Valid())
+		}
+		if ceiling == 1 {
+			continue
+		}
+		if !it.Prev() || tree.compare(it.Key(), genKType(ceiling-2)) != 0 {
+			t.Fatalf("want previous of %v to be %v, got %v", genKType(ceiling), genKType(ceiling-2), it.Valid())
+		}
+		if !it.Next() || tree.compare(it.Key(), genKType(ceiling)) != 0 {
+			t.Fatalf("want next of %v to be %v, got %v", genKType(ceiling-2), genKType(ceiling), it.Valid())
+		}
+	}
+	if it.SeekCeiling(genKType(2*n)) || it.Next() {
+		t.Error("want nothing past the max")
+	}
+
+	for i := 1; i <= 2*n; i++ {
+		floor := (i - 1) | 1
+		if !it.SeekFloor(genKType(i)) || tree.compare(it.Key(), genKType(floor)) != 0 {
+			t.Fatalf("want to seek the floor of %v at %v, got %v", genKType(i), genKType(floor), it.Valid())
+		}
+		if floor == 2*n-1 {
+			continue
+		}
+		if !it.Next() || tree.compare(it.Key(), genKType(floor+2)) != 0 {
+			t.Fatalf("want next of %v to be %v, got %v", genKType(floor), genKType(floor+2), it.Valid())
+		}
+	}
+	if it.SeekFloor(genKType(0)) || it.Prev() {
+		t.Error("want nothing before the min")
+	}
+
+	empty := NewRedBlack().Iterator()
+	if empty.Next() || empty.Prev() || empty.First() || empty.Last() || empty.SeekCeiling(genKType(0)) || empty.SeekFloor(genKType(0)) {
+		t.Error("want an empty sorted map to have no keys")
+	}
+}
+
+func TestRedBlack_Delete(t *testing.T) {
+	const n = 200
+	tree := newRedBlackTest(t, n)
+	for _, i := range rand.Perm(n) {
+		if _, ok := tree.Delete(genKType(2 * i)); ok {
+			t.Fatalf("deleted %v, which isn't there", genKType(2*i))
+		}
+		old, ok := tree.Delete(genKType(2*i + 1))
+		if !ok || !reflect.DeepEqual(old, genVType(2*i+1)) {
+			t.Fatalf("delete %v: want %v, got %v (%v)", genKType(2*i+1), genVType(2*i+1), old, ok)
+		}
+		if tree.Has(genKType(2*i + 1)) {
+			t.Fatalf("%v is still there once deleted", genKType(2*i+1))
+		}
+		checkRedBlack(t, tree)
+	}
+	if !tree.IsEmpty() {
+		t.Fatalf("tree isn't empty once everything is deleted, Size=%d", tree.Size())
+	}
+}
+
+func TestRedBlack_CountDeleteRange(t *testing.T) {
+	const n = 100
+	tests := []struct {
+		lo, hi int
+		count  int
+	}{
+		{10, 21, 6},
+		{11, 11, 1},
+		{12, 12, 0},
+		{21, 10, 0},
+		{0, 2 * n, n},
+		{0, 150, 75},
+		{51, 2 * n, 75},
+		{20, 180, 80},
+	}
+	for _, tt := range tests {
+		tree := newRedBlackTest(t, n)
+		if count := tree.CountRange(genKType(tt.lo), genKType(tt.hi)); count != tt.count {
+			t.Errorf("want %d keys between %v and %v, counted %d", tt.count, genKType(tt.lo), genKType(tt.hi), count)
+		}
+		if count := tree.DeleteRange(genKType(tt.lo), genKType(tt.hi)); count != tt.count {
+			t.Errorf("want %d keys between %v and %v, deleted %d", tt.count, genKType(tt.lo), genKType(tt.hi), count)
+		}
+		checkRedBlack(t, tree)
+		if tree.Size() != n-tt.count {
+			t.Errorf("want Size=%d once the range %v-%v is deleted, was %d", n-tt.count, genKType(tt.lo), genKType(tt.hi), tree.Size())
+		}
+		for i := 1; i < 2*n; i += 2 {
+			inRange := tt.lo <= i && i <= tt.hi
+			if tree.Has(genKType(i)) == inRange {
+				t.Errorf("deleting the range %v-%v: want %v to be there: %v", genKType(tt.lo), genKType(tt.hi), genKType(i), !inRange)
+			}
+		}
+	}
+
+	// the trees rebuilt from the keys left are balanced whatever their size
+	for size := 0; size < 100; size++ {
+		tree := newRedBlackTest(t, size+10)
+		if count := tree.DeleteRange(genKType(2*size), genKType(2*size+20)); count != 10 {
+			t.Fatalf("want 10 keys deleted, deleted %d", count)
+		}
+		checkRedBlack(t, tree)
+	}
+}
+
+func TestRedBlack_DeleteMinMax(t *testing.T) {
+	const n = 100
+	tree := newRedBlackTest(t, n)
+	for i := 0; i < n/2; i++ {
+		k, v, ok := tree.DeleteMin()
+		if !ok || tree.compare(k, genKType(2*i+1)) != 0 || !reflect.DeepEqual(v, genVType(2*i+1)) {
+			t.Fatalf("want to delete min %v, got %v (%v)", genKType(2*i+1), k, ok)
+		}
+		checkRedBlack(t, tree)
+
+		j := n - 1 - i
+		k, v, ok = tree.DeleteMax()
+		if !ok || tree.compare(k, genKType(2*j+1)) != 0 || !reflect.DeepEqual(v, genVType(2*j+1)) {
+			t.Fatalf("want to delete max %v, got %v (%v)", genKType(2*j+1), k, ok)
+		}
+		checkRedBlack(t, tree)
+	}
+	if !tree.IsEmpty() {
+		t.Fatalf("tree isn't empty once everything is deleted, Size=%d", tree.Size())
+	}
+}
@@ -0,0 +1,20 @@
+//go:build go1.23
+
+package queue
+
+import "iter"
+
+// The iterators of this file are only generated along the queues of the
+// modules whose Go version supports them, from Go 1.23 on.
+
+// All returns the elements of the queue, from its front to its end,
+// without removing them.
+func (q *Queue) All() iter.Seq[KType] {
+	return func(yield func(KType) bool) {
+		for i := 0; i < q.Len(); i++ {
+			if !yield(q.Get(i)) {
+				return
+			}
+		}
+	}
+}
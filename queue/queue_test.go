@@ -2,6 +2,9 @@ package queue
 
 import "testing"
 
+// genKType generates the elements of the tests found in template_test.go.
+func genKType(i int) KType { return i }
+
 func TestQueueLen(t *testing.T) {
 	q := NewQueue(0)
 
@@ -0,0 +1,106 @@
+package queue
+
+// GENERATED CODE!!!
+
+// The benchmarks of this file are also generated along the queues, by
+// `datagen queue -bench`. They only rely on genKType(i), which returns the
+// elements of the benchmarks. Each benchmark is followed by the same work
+// done with container/list, as a baseline.
+
+import (
+	"container/list"
+	"testing"
+)
+
+// elemsQueueBench returns n elements.
+func elemsQueueBench(n int) []KType {
+	elems := make([]KType, 0, n)
+	for i := 0; i < n; i++ {
+		elems = append(elems, genKType(i))
+	}
+	return elems
+}
+
+func BenchmarkQueue_Push(b *testing.B) {
+	elems := elemsQueueBench(b.N)
+	q := NewQueue(0)
+	b.ResetTimer()
+	for _, e := range elems {
+		q.Push(e)
+	}
+}
+
+func BenchmarkQueue_Push_List(b *testing.B) {
+	elems := elemsQueueBench(b.N)
+	q := list.New()
+	b.ResetTimer()
+	for _, e := range elems {
+		q.PushBack(e)
+	}
+}
+
+func BenchmarkQueue_Pop(b *testing.B) {
+	q := NewQueue(0)
+	for _, e := range elemsQueueBench(b.N) {
+		q.Push(e)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Pop()
+	}
+}
+
+func BenchmarkQueue_Pop_List(b *testing.B) {
+	q := list.New()
+	for _, e := range elemsQueueBench(b.N) {
+		q.PushBack(e)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.Remove(q.Front())
+	}
+}
+
+func BenchmarkQueue_Serial(b *testing.B) {
+	elems := elemsQueueBench(b.N)
+	q := NewQueue(0)
+	b.ResetTimer()
+	for _, e := range elems {
+		q.Push(e)
+	}
+	for i := 0; i < b.N; i++ {
+		q.Pop()
+	}
+}
+
+func BenchmarkQueue_Serial_List(b *testing.B) {
+	elems := elemsQueueBench(b.N)
+	q := list.New()
+	b.ResetTimer()
+	for _, e := range elems {
+		q.PushBack(e)
+	}
+	for i := 0; i < b.N; i++ {
+		q.Remove(q.Front())
+	}
+}
+
+func BenchmarkQueue_TickTock(b *testing.B) {
+	elems := elemsQueueBench(b.N)
+	q := NewQueue(0)
+	b.ResetTimer()
+	for _, e := range elems {
+		q.Push(e)
+		q.Pop()
+	}
+}
+
+func BenchmarkQueue_TickTock_List(b *testing.B) {
+	elems := elemsQueueBench(b.N)
+	q := list.New()
+	b.ResetTimer()
+	for _, e := range elems {
+		q.PushBack(e)
+		q.Remove(q.Front())
+	}
+}
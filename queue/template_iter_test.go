@@ -0,0 +1,31 @@
+//go:build go1.23
+
+package queue
+
+// The tests of this file are also generated along the queues that have
+// iterators, by `datagen queue -tests`.
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQueue_All(t *testing.T) {
+	const n = 100
+	q := NewQueue(0)
+	for i := 0; i < n; i++ {
+		q.Push(genKType(i))
+	}
+	q.Pop()
+
+	i := 1
+	for k := range q.All() {
+		if !reflect.DeepEqual(k, genKType(i)) {
+			t.Fatalf("want %v, got %v", genKType(i), k)
+		}
+		i++
+	}
+	if i != n || q.Len() != n-1 {
+		t.Errorf("iterated over %d elements of %d, want %d", i-1, q.Len(), n-1)
+	}
+}
@@ -0,0 +1,76 @@
+package queue
+
+// GENERATED CODE!!!
+
+// The tests of this file are also generated along the queues, by `datagen
+// queue -tests`. They only rely on genKType(i), which returns distinct
+// elements for each i, so that they can run against any element type.
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQueue_PushPop(t *testing.T) {
+	const n = 1000
+	q := NewQueue(0)
+	for i := 0; i < n; i++ {
+		q.Push(genKType(i))
+		if q.Len() != i+1 {
+			t.Fatalf("pushing: queue with %d elements has length %d", i+1, q.Len())
+		}
+		for j := 0; j < q.Len(); j++ {
+			if k := q.Get(j); !reflect.DeepEqual(k, genKType(j)) {
+				t.Fatalf("index %d: want %v, got %v", j, genKType(j), k)
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		if k := q.Peek(); !reflect.DeepEqual(k, genKType(i)) {
+			t.Fatalf("want to peek %v, got %v", genKType(i), k)
+		}
+		if k := q.Pop(); !reflect.DeepEqual(k, genKType(i)) {
+			t.Fatalf("want to pop %v, got %v", genKType(i), k)
+		}
+		if q.Len() != n-i-1 {
+			t.Fatalf("popping: queue with %d elements has length %d", n-i-1, q.Len())
+		}
+	}
+}
+
+func TestQueue_TickTock(t *testing.T) {
+	q := NewQueue(0)
+	for i := 0; i < 100; i++ {
+		q.Push(genKType(2 * i))
+		q.Push(genKType(2*i + 1))
+		if k := q.Pop(); !reflect.DeepEqual(k, genKType(i)) {
+			t.Fatalf("want to pop %v, got %v", genKType(i), k)
+		}
+	}
+	if q.Len() != 100 {
+		t.Fatalf("want Len=100, got %d", q.Len())
+	}
+}
+
+func TestQueue_OutOfRangePanics(t *testing.T) {
+	q := NewQueue(0)
+	panicsQueue(t, "peeking an empty queue", func() { q.Peek() })
+	panicsQueue(t, "popping an empty queue", func() { q.Pop() })
+
+	q.Push(genKType(0))
+	panicsQueue(t, "getting a negative index", func() { q.Get(-1) })
+	panicsQueue(t, "getting an index past the length", func() { q.Get(1) })
+
+	q.Pop()
+	panicsQueue(t, "peeking an emptied queue", func() { q.Peek() })
+	panicsQueue(t, "popping an emptied queue", func() { q.Pop() })
+}
+
+func panicsQueue(t *testing.T, name string, f func()) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("%s: didn't panic as expected", name)
+		}
+	}()
+	f()
+}
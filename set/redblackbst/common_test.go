@@ -28,6 +28,9 @@ func (k K) Compare(other KType) int {
 	return 0
 }
 
+// genKType generates the keys of the tests found in template_test.go.
+func genKType(i int) KType { return K(fmt.Sprintf("key:%08d", i)) }
+
 func loadWordlist(filename string, fallback []string) (out []string) {
 	if testing.Short() {
 		return fallback
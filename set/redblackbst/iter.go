@@ -0,0 +1,15 @@
+//go:build go1.23
+
+package redblackbst
+
+import "iter"
+
+// The iterators of this file are only generated along the sorted sets of
+// the modules whose Go version supports them, from Go 1.23 on.
+
+// All returns the keys of the sorted set, in increasing order.
+func (r RedBlack) All() iter.Seq[KType] {
+	return func(yield func(KType) bool) {
+		r.Keys(yield)
+	}
+}
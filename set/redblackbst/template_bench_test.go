@@ -0,0 +1,102 @@
+package redblackbst
+
+// GENERATED CODE!!!
+
+// The benchmarks of this file are also generated along the sorted sets, by
+// `datagen sset -bench`. They only rely on genKType(i), which returns keys.
+// When the keys can be used in a Go map, the benchmarks are followed by the
+// same work done with a map, as a baseline.
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// keysRedBlackBench returns n keys in a random order.
+func keysRedBlackBench(n int) []KType {
+	keys := make([]KType, 0, n)
+	for _, i := range rand.Perm(n) {
+		keys = append(keys, genKType(i))
+	}
+	return keys
+}
+
+// newRedBlackBench returns a sorted set holding the keys.
+func newRedBlackBench(keys []KType) *RedBlack {
+	tree := NewRedBlack()
+	for _, k := range keys {
+		tree.Put(k)
+	}
+	return tree
+}
+
+// newRedBlackGoMapBench returns a Go map holding the keys.
+func newRedBlackGoMapBench(keys []KType) map[KType]struct{} {
+	m := make(map[KType]struct{})
+	for _, k := range keys {
+		m[k] = struct{}{}
+	}
+	return m
+}
+
+func BenchmarkRedBlack_Put(b *testing.B) {
+	keys := keysRedBlackBench(b.N)
+	tree := NewRedBlack()
+	b.ResetTimer()
+	for _, k := range keys {
+		tree.Put(k)
+	}
+}
+
+func BenchmarkRedBlack_Put_GoMap(b *testing.B) {
+	keys := keysRedBlackBench(b.N)
+	m := make(map[KType]struct{})
+	b.ResetTimer()
+	for _, k := range keys {
+		m[k] = struct{}{}
+	}
+}
+
+func BenchmarkRedBlack_Contains(b *testing.B) {
+	keys := keysRedBlackBench(b.N)
+	tree := newRedBlackBench(keys)
+	b.ResetTimer()
+	for _, k := range keys {
+		tree.Contains(k)
+	}
+}
+
+func BenchmarkRedBlack_Contains_GoMap(b *testing.B) {
+	keys := keysRedBlackBench(b.N)
+	m := newRedBlackGoMapBench(keys)
+	b.ResetTimer()
+	for _, k := range keys {
+		_ = m[k]
+	}
+}
+
+func BenchmarkRedBlack_Delete(b *testing.B) {
+	keys := keysRedBlackBench(b.N)
+	tree := newRedBlackBench(keys)
+	b.ResetTimer()
+	for _, k := range keys {
+		tree.Delete(k)
+	}
+}
+
+func BenchmarkRedBlack_Delete_GoMap(b *testing.B) {
+	keys := keysRedBlackBench(b.N)
+	m := newRedBlackGoMapBench(keys)
+	b.ResetTimer()
+	for _, k := range keys {
+		delete(m, k)
+	}
+}
+
+func BenchmarkRedBlack_DeleteMin(b *testing.B) {
+	tree := newRedBlackBench(keysRedBlackBench(b.N))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.DeleteMin()
+	}
+}
@@ -0,0 +1,26 @@
+//go:build go1.23
+
+package redblackbst
+
+// The tests of this file are also generated along the sorted sets that
+// have iterators, by `datagen sset -tests`.
+
+import "testing"
+
+func TestRedBlack_All(t *testing.T) {
+	const n = 100
+	tree := newRedBlackTest(t, n)
+
+	i := 1
+	for k := range tree.All() {
+		if tree.compare(k, genKType(i)) != 0 {
+			t.Fatalf("want %v, got %v", genKType(i), k)
+		}
+		if i += 2; i > n {
+			break
+		}
+	}
+	if i <= n {
+		t.Errorf("want to stop iterating at %v", genKType(n))
+	}
+}